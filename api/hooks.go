@@ -0,0 +1,21 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// HookSignaturePrefix the prefix of the hex encoded HMAC in the X-Sync-Signature header.
+const HookSignaturePrefix = "sha256="
+
+// SignHook returns the X-Sync-Signature header value of a webhook request:
+// the HMAC-SHA256 of the unix timestamp and the body, joined by a dot, with the secret of the hook.
+func SignHook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return HookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,3965 @@
+// Package api provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.1 DO NOT EDIT.
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+	"github.com/oapi-codegen/runtime"
+)
+
+const (
+	BasicAuthScopes  = "basicAuth.Scopes"
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for DiffChangeOperation.
+const (
+	Add    DiffChangeOperation = "add"
+	Delete DiffChangeOperation = "delete"
+	Set    DiffChangeOperation = "set"
+	Update DiffChangeOperation = "update"
+)
+
+// Defines values for InstanceDetailStatus.
+const (
+	InstanceDetailStatusDanger  InstanceDetailStatus = "danger"
+	InstanceDetailStatusInfo    InstanceDetailStatus = "info"
+	InstanceDetailStatusSuccess InstanceDetailStatus = "success"
+	InstanceDetailStatusWarning InstanceDetailStatus = "warning"
+)
+
+// Defines values for InstanceStatusStatus.
+const (
+	InstanceStatusStatusDanger  InstanceStatusStatus = "danger"
+	InstanceStatusStatusInfo    InstanceStatusStatus = "info"
+	InstanceStatusStatusSuccess InstanceStatusStatus = "success"
+	InstanceStatusStatusWarning InstanceStatusStatus = "warning"
+)
+
+// Defines values for LogLevel.
+const (
+	LogLevelDebug  LogLevel = "debug"
+	LogLevelDpanic LogLevel = "dpanic"
+	LogLevelError  LogLevel = "error"
+	LogLevelFatal  LogLevel = "fatal"
+	LogLevelInfo   LogLevel = "info"
+	LogLevelPanic  LogLevel = "panic"
+	LogLevelWarn   LogLevel = "warn"
+)
+
+// Defines values for StatsHistoryResolution.
+const (
+	Day  StatsHistoryResolution = "day"
+	Hour StatsHistoryResolution = "hour"
+)
+
+// Defines values for SyncEventType.
+const (
+	Action  SyncEventType = "action"
+	Replica SyncEventType = "replica"
+	Run     SyncEventType = "run"
+)
+
+// Defines values for SyncRunStatus.
+const (
+	Failed    SyncRunStatus = "failed"
+	Queued    SyncRunStatus = "queued"
+	Running   SyncRunStatus = "running"
+	Succeeded SyncRunStatus = "succeeded"
+)
+
+// Defines values for GetLogsParamsFormat.
+const (
+	Json GetLogsParamsFormat = "json"
+	Text GetLogsParamsFormat = "text"
+)
+
+// Defines values for SearchQueryLogParamsStatus.
+const (
+	All                 SearchQueryLogParamsStatus = "all"
+	Blocked             SearchQueryLogParamsStatus = "blocked"
+	BlockedParental     SearchQueryLogParamsStatus = "blocked_parental"
+	BlockedSafebrowsing SearchQueryLogParamsStatus = "blocked_safebrowsing"
+	Filtered            SearchQueryLogParamsStatus = "filtered"
+	Processed           SearchQueryLogParamsStatus = "processed"
+	Rewritten           SearchQueryLogParamsStatus = "rewritten"
+	SafeSearch          SearchQueryLogParamsStatus = "safe_search"
+	Whitelisted         SearchQueryLogParamsStatus = "whitelisted"
+)
+
+// DiffChange defines model for DiffChange.
+type DiffChange struct {
+	// Fields The differing fields of updated items and settings
+	Fields    *[]FieldDiff        `json:"fields,omitempty"`
+	Item      string              `json:"item"`
+	Operation DiffChangeOperation `json:"operation"`
+}
+
+// DiffChangeOperation defines model for DiffChange.Operation.
+type DiffChangeOperation string
+
+// Error defines model for Error.
+type Error struct {
+	Error string `json:"error"`
+}
+
+// FeatureDiff The changes a sync would apply for a feature; no changes if the feature is in sync.
+type FeatureDiff struct {
+	Changes []DiffChange `json:"changes"`
+	Error   *string      `json:"error,omitempty"`
+	Feature string       `json:"feature"`
+
+	// Skipped The feature is not supported by the replica version
+	Skipped *bool `json:"skipped,omitempty"`
+}
+
+// FieldDiff defines model for FieldDiff.
+type FieldDiff struct {
+	Origin  *string `json:"origin"`
+	Path    string  `json:"path"`
+	Replica *string `json:"replica"`
+}
+
+// InstanceDetail defines model for InstanceDetail.
+type InstanceDetail struct {
+	Allowlists *int    `json:"allowlists"`
+	Clients    *int    `json:"clients"`
+	Error      *string `json:"error,omitempty"`
+
+	// Filters The number of block lists
+	Filters *int   `json:"filters"`
+	Host    string `json:"host"`
+
+	// LastSync The result of the last sync to a replica
+	LastSync *SyncResult `json:"last_sync,omitempty"`
+
+	// Origin Whether the instance is the origin
+	Origin            bool  `json:"origin"`
+	ProtectionEnabled *bool `json:"protection_enabled"`
+	Rewrites          *int  `json:"rewrites"`
+	StaticLeases      *int  `json:"static_leases"`
+
+	// Status The status as dashboard color
+	Status InstanceDetailStatus `json:"status"`
+	Url    string               `json:"url"`
+
+	// UserRules The number of user rules, without comments
+	UserRules *int    `json:"user_rules"`
+	Version   *string `json:"version,omitempty"`
+}
+
+// InstanceDetailStatus The status as dashboard color
+type InstanceDetailStatus string
+
+// InstanceDiff defines model for InstanceDiff.
+type InstanceDiff struct {
+	Features []FeatureDiff `json:"features"`
+	Host     string        `json:"host"`
+	Origin   string        `json:"origin"`
+}
+
+// InstanceError defines model for InstanceError.
+type InstanceError struct {
+	Error    string `json:"error"`
+	Instance string `json:"instance"`
+}
+
+// InstanceStatus defines model for InstanceStatus.
+type InstanceStatus struct {
+	Error             *string `json:"error,omitempty"`
+	Host              string  `json:"host"`
+	ProtectionEnabled *bool   `json:"protection_enabled"`
+
+	// Status The status as dashboard color
+	Status InstanceStatusStatus `json:"status"`
+	Url    string               `json:"url"`
+}
+
+// InstanceStatusStatus The status as dashboard color
+type InstanceStatusStatus string
+
+// LogEntry defines model for LogEntry.
+type LogEntry struct {
+	Fields  *map[string]interface{} `json:"fields,omitempty"`
+	Level   LogLevel                `json:"level"`
+	Logger  *string                 `json:"logger,omitempty"`
+	Message string                  `json:"message"`
+	Time    time.Time               `json:"time"`
+}
+
+// LogLevel defines model for LogLevel.
+type LogLevel string
+
+// QueryLogEntry defines model for QueryLogEntry.
+type QueryLogEntry struct {
+	Answers    *[]string `json:"answers,omitempty"`
+	Cached     bool      `json:"cached"`
+	Client     *string   `json:"client,omitempty"`
+	ClientName *string   `json:"clientName,omitempty"`
+	Domain     string    `json:"domain"`
+	ElapsedMs  float32   `json:"elapsedMs"`
+
+	// Instance The host of the instance that answered the query
+	Instance string `json:"instance"`
+
+	// Reason The filtering reason, e.g. `FilteredBlackList`
+	Reason *string `json:"reason,omitempty"`
+
+	// Rules The filtering rules that matched
+	Rules *[]string `json:"rules,omitempty"`
+
+	// Status The DNS response status, e.g. `NOERROR`
+	Status *string   `json:"status,omitempty"`
+	Time   time.Time `json:"time"`
+
+	// Type The DNS question type, e.g. `A`
+	Type     *string `json:"type,omitempty"`
+	Upstream *string `json:"upstream,omitempty"`
+}
+
+// QueryLogSearch defines model for QueryLogSearch.
+type QueryLogSearch struct {
+	Entries []QueryLogEntry  `json:"entries"`
+	Errors  *[]InstanceError `json:"errors,omitempty"`
+
+	// Oldest The `older_than` of the next page, not set if there are no more entries
+	Oldest *string `json:"oldest,omitempty"`
+}
+
+// StatsHistory defines model for StatsHistory.
+type StatsHistory struct {
+	From       time.Time              `json:"from"`
+	Instances  []StatsHistorySeries   `json:"instances"`
+	Resolution StatsHistoryResolution `json:"resolution"`
+	To         time.Time              `json:"to"`
+
+	// Total The sum of the selected instances
+	Total []StatsHistoryPoint `json:"total"`
+}
+
+// StatsHistoryResolution defines model for StatsHistory.Resolution.
+type StatsHistoryResolution string
+
+// StatsHistoryPoint defines model for StatsHistoryPoint.
+type StatsHistoryPoint struct {
+	BlockedFiltering     int `json:"blockedFiltering"`
+	DnsQueries           int `json:"dnsQueries"`
+	ReplacedParental     int `json:"replacedParental"`
+	ReplacedSafebrowsing int `json:"replacedSafebrowsing"`
+
+	// Time The start of the hour or day
+	Time time.Time `json:"time"`
+}
+
+// StatsHistorySeries defines model for StatsHistorySeries.
+type StatsHistorySeries struct {
+	// Instance The host of the instance
+	Instance string              `json:"instance"`
+	Points   []StatsHistoryPoint `json:"points"`
+}
+
+// SyncEvent defines model for SyncEvent.
+type SyncEvent struct {
+	Action  *string  `json:"action,omitempty"`
+	Error   *string  `json:"error,omitempty"`
+	Level   LogLevel `json:"level"`
+	Replica *string  `json:"replica,omitempty"`
+	Run     *SyncRun `json:"run,omitempty"`
+
+	// Status The status of the run (queued, running, succeeded, failed), replica (started, succeeded, failed)
+	// or action (applied, failed, skipped)
+	Status string        `json:"status"`
+	Time   time.Time     `json:"time"`
+	Type   SyncEventType `json:"type"`
+}
+
+// SyncEventType defines model for SyncEvent.Type.
+type SyncEventType string
+
+// SyncResult The result of the last sync to a replica
+type SyncResult struct {
+	// Duration The duration in seconds
+	Duration float32       `json:"duration"`
+	Error    *string       `json:"error,omitempty"`
+	Finished time.Time     `json:"finished"`
+	Status   SyncRunStatus `json:"status"`
+}
+
+// SyncRun defines model for SyncRun.
+type SyncRun struct {
+	Error *string `json:"error,omitempty"`
+
+	// Features The selected features, all if empty
+	Features *[]string  `json:"features,omitempty"`
+	Finished *time.Time `json:"finished,omitempty"`
+	Id       string     `json:"id"`
+	Queued   time.Time  `json:"queued"`
+
+	// Replicas The selected replicas, all if empty
+	Replicas *[]string     `json:"replicas,omitempty"`
+	Started  *time.Time    `json:"started,omitempty"`
+	Status   SyncRunStatus `json:"status"`
+
+	// Trigger What triggered the run, e.g. `api`, `cron`, `startup` or `hook:<name>`
+	Trigger string `json:"trigger"`
+}
+
+// SyncRunStatus defines model for SyncRunStatus.
+type SyncRunStatus string
+
+// SyncStatus defines model for SyncStatus.
+type SyncStatus struct {
+	Origin      InstanceStatus    `json:"origin"`
+	Replicas    *[]InstanceStatus `json:"replicas"`
+	SyncRunning bool              `json:"syncRunning"`
+
+	// Updated When the status of the instances was polled
+	Updated *time.Time `json:"updated,omitempty"`
+}
+
+// InstanceHost defines model for InstanceHost.
+type InstanceHost = string
+
+// BadRequest defines model for BadRequest.
+type BadRequest = Error
+
+// Forbidden defines model for Forbidden.
+type Forbidden = Error
+
+// NotFound defines model for NotFound.
+type NotFound = Error
+
+// StreamEventsParams defines parameters for StreamEvents.
+type StreamEventsParams struct {
+	// Level The minimum level of the streamed entries and events
+	Level *LogLevel `form:"level,omitempty" json:"level,omitempty"`
+
+	// Replica Only stream the entries and events of the replica with the given host
+	Replica *string `form:"replica,omitempty" json:"replica,omitempty"`
+}
+
+// TriggerHookParams defines parameters for TriggerHook.
+type TriggerHookParams struct {
+	// XSyncTimestamp The time of the request in unix seconds
+	XSyncTimestamp int64 `json:"X-Sync-Timestamp"`
+
+	// XSyncSignature The signature of the request, `sha256=` followed by the hex encoded HMAC
+	XSyncSignature string `json:"X-Sync-Signature"`
+}
+
+// GetInstanceDiffParams defines parameters for GetInstanceDiff.
+type GetInstanceDiffParams struct {
+	// Features Only compare the selected features, as for triggering a sync. Comma separated or repeated.
+	Features *[]string `form:"features,omitempty" json:"features,omitempty"`
+}
+
+// GetLogsParams defines parameters for GetLogs.
+type GetLogsParams struct {
+	// Level The minimum level of the entries
+	Level *LogLevel `form:"level,omitempty" json:"level,omitempty"`
+
+	// Logger The name of the logger (e.g. `sync` or `client`), including its child loggers
+	Logger *string `form:"logger,omitempty" json:"logger,omitempty"`
+
+	// Host The host of the origin or replica the entries refer to
+	Host *string `form:"host,omitempty" json:"host,omitempty"`
+
+	// Since A RFC3339 time or a duration (e.g. `15m`) of the oldest entries
+	Since *string `form:"since,omitempty" json:"since,omitempty"`
+
+	// Format The response format, `json` is also selected with the `Accept` header `application/json`
+	Format *GetLogsParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetLogsParamsFormat defines parameters for GetLogs.
+type GetLogsParamsFormat string
+
+// SearchQueryLogParams defines parameters for SearchQueryLog.
+type SearchQueryLogParams struct {
+	// Search Only entries with a domain or client containing the value
+	Search *string `form:"search,omitempty" json:"search,omitempty"`
+
+	// Client Only entries of the client with the given IP address, ClientID or name
+	Client *string `form:"client,omitempty" json:"client,omitempty"`
+
+	// Status Only entries with the response status
+	Status *SearchQueryLogParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Limit The maximum number of entries, default 100. A page doesn't end within entries of the same time,
+	// it has fewer entries, or more if all its entries have the same time.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// OlderThan Only entries older than the RFC3339 time, e.g. the `oldest` of the previous page
+	OlderThan *string `form:"older_than,omitempty" json:"older_than,omitempty"`
+}
+
+// SearchQueryLogParamsStatus defines parameters for SearchQueryLog.
+type SearchQueryLogParamsStatus string
+
+// GetStatsParams defines parameters for GetStats.
+type GetStatsParams struct {
+	// From A RFC3339 time or a duration (e.g. `168h`) before now of the start of the range, default `24h`
+	From *string `form:"from,omitempty" json:"from,omitempty"`
+
+	// To A RFC3339 time or a duration before now of the end of the range, default now
+	To *string `form:"to,omitempty" json:"to,omitempty"`
+
+	// Instance The host, web host or URL of the origin or a replica, all instances if not set
+	Instance *string `form:"instance,omitempty" json:"instance,omitempty"`
+}
+
+// TriggerSyncParams defines parameters for TriggerSync.
+type TriggerSyncParams struct {
+	// Features Only sync the selected features, by their config path (e.g. `dns.rewrites`) or group (e.g. `filters`).
+	// Comma separated or repeated. Features disabled in the config are never synced.
+	Features *[]string `form:"features,omitempty" json:"features,omitempty"`
+
+	// Replicas Only sync to the selected replicas, by host, web host or URL. Comma separated or repeated.
+	Replicas *[]string `form:"replicas,omitempty" json:"replicas,omitempty"`
+}
+
+// RequestEditorFn  is the function signature for the RequestEditor callback function
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// Doer performs HTTP requests.
+//
+// The standard http.Client implements this interface.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client which conforms to the OpenAPI3 specification for this service.
+type Client struct {
+	// The endpoint of the server conforming to this interface, with scheme,
+	// https://api.deepmap.com for example. This can contain a path relative
+	// to the server, such as https://api.deepmap.com/dev-test, and all the
+	// paths in the swagger spec will be appended to the server.
+	Server string
+
+	// Doer for performing requests, typically a *http.Client with any
+	// customized settings, such as certificate chains.
+	Client HttpRequestDoer
+
+	// A list of callbacks for modifying requests which are generated before sending over
+	// the network.
+	RequestEditors []RequestEditorFn
+}
+
+// ClientOption allows setting custom parameters during construction
+type ClientOption func(*Client) error
+
+// Creates a new Client, with reasonable defaults
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	// create a client with sane default values
+	client := Client{
+		Server: server,
+	}
+	// mutate client and add all optional params
+	for _, o := range opts {
+		if err := o(&client); err != nil {
+			return nil, err
+		}
+	}
+	// ensure the server URL always has a trailing slash
+	if !strings.HasSuffix(client.Server, "/") {
+		client.Server += "/"
+	}
+	// create httpClient, if not already present
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+	return &client, nil
+}
+
+// WithHTTPClient allows overriding the default Doer, which is
+// automatically created using http.Client. This is useful for tests.
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn allows setting up a callback function, which will be
+// called right before sending the request. This can be used to mutate the request.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// The interface specification for the client above.
+type ClientInterface interface {
+	// ClearLogs request
+	ClearLogs(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// StreamEvents request
+	StreamEvents(ctx context.Context, params *StreamEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// TriggerHookWithBody request with any body
+	TriggerHookWithBody(ctx context.Context, name string, params *TriggerHookParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetInstance request
+	GetInstance(ctx context.Context, host InstanceHost, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetInstanceDiff request
+	GetInstanceDiff(ctx context.Context, host InstanceHost, params *GetInstanceDiffParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetLogs request
+	GetLogs(ctx context.Context, params *GetLogsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetOpenAPI request
+	GetOpenAPI(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SearchQueryLog request
+	SearchQueryLog(ctx context.Context, params *SearchQueryLogParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetStats request
+	GetStats(ctx context.Context, params *GetStatsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetStatus request
+	GetStatus(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListSyncRuns request
+	ListSyncRuns(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// TriggerSync request
+	TriggerSync(ctx context.Context, params *TriggerSyncParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetSyncRun request
+	GetSyncRun(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetHealthz request
+	GetHealthz(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// HeadHealthz request
+	HeadHealthz(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetLivez request
+	GetLivez(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// HeadLivez request
+	HeadLivez(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetMetrics request
+	GetMetrics(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetReadyz request
+	GetReadyz(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// HeadReadyz request
+	HeadReadyz(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) ClearLogs(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewClearLogsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) StreamEvents(ctx context.Context, params *StreamEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewStreamEventsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) TriggerHookWithBody(ctx context.Context, name string, params *TriggerHookParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewTriggerHookRequestWithBody(c.Server, name, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetInstance(ctx context.Context, host InstanceHost, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetInstanceRequest(c.Server, host)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetInstanceDiff(ctx context.Context, host InstanceHost, params *GetInstanceDiffParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetInstanceDiffRequest(c.Server, host, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetLogs(ctx context.Context, params *GetLogsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetLogsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetOpenAPI(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetOpenAPIRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) SearchQueryLog(ctx context.Context, params *SearchQueryLogParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSearchQueryLogRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetStats(ctx context.Context, params *GetStatsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetStatsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetStatus(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetStatusRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListSyncRuns(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListSyncRunsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) TriggerSync(ctx context.Context, params *TriggerSyncParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewTriggerSyncRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetSyncRun(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetSyncRunRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetHealthz(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetHealthzRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) HeadHealthz(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewHeadHealthzRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetLivez(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetLivezRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) HeadLivez(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewHeadLivezRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetMetrics(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetMetricsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetReadyz(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetReadyzRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) HeadReadyz(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewHeadReadyzRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewClearLogsRequest generates requests for ClearLogs
+func NewClearLogsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/clear-logs")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewStreamEventsRequest generates requests for StreamEvents
+func NewStreamEventsRequest(server string, params *StreamEventsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/events")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Level != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "level", runtime.ParamLocationQuery, *params.Level); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Replica != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "replica", runtime.ParamLocationQuery, *params.Replica); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewTriggerHookRequestWithBody generates requests for TriggerHook with any type of body
+func NewTriggerHookRequestWithBody(server string, name string, params *TriggerHookParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/hooks/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		var headerParam0 string
+
+		headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Sync-Timestamp", runtime.ParamLocationHeader, params.XSyncTimestamp)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("X-Sync-Timestamp", headerParam0)
+
+		var headerParam1 string
+
+		headerParam1, err = runtime.StyleParamWithLocation("simple", false, "X-Sync-Signature", runtime.ParamLocationHeader, params.XSyncSignature)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("X-Sync-Signature", headerParam1)
+
+	}
+
+	return req, nil
+}
+
+// NewGetInstanceRequest generates requests for GetInstance
+func NewGetInstanceRequest(server string, host InstanceHost) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "host", runtime.ParamLocationPath, host)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/instances/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetInstanceDiffRequest generates requests for GetInstanceDiff
+func NewGetInstanceDiffRequest(server string, host InstanceHost, params *GetInstanceDiffParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "host", runtime.ParamLocationPath, host)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/instances/%s/diff", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Features != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "features", runtime.ParamLocationQuery, *params.Features); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetLogsRequest generates requests for GetLogs
+func NewGetLogsRequest(server string, params *GetLogsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/logs")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Level != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "level", runtime.ParamLocationQuery, *params.Level); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Logger != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "logger", runtime.ParamLocationQuery, *params.Logger); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Host != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "host", runtime.ParamLocationQuery, *params.Host); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Since != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "since", runtime.ParamLocationQuery, *params.Since); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Format != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "format", runtime.ParamLocationQuery, *params.Format); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetOpenAPIRequest generates requests for GetOpenAPI
+func NewGetOpenAPIRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/openapi.json")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSearchQueryLogRequest generates requests for SearchQueryLog
+func NewSearchQueryLogRequest(server string, params *SearchQueryLogParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/querylog")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Search != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "search", runtime.ParamLocationQuery, *params.Search); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Client != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "client", runtime.ParamLocationQuery, *params.Client); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Status != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "status", runtime.ParamLocationQuery, *params.Status); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.OlderThan != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "older_than", runtime.ParamLocationQuery, *params.OlderThan); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetStatsRequest generates requests for GetStats
+func NewGetStatsRequest(server string, params *GetStatsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/stats")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.From != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "from", runtime.ParamLocationQuery, *params.From); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.To != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "to", runtime.ParamLocationQuery, *params.To); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Instance != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "instance", runtime.ParamLocationQuery, *params.Instance); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetStatusRequest generates requests for GetStatus
+func NewGetStatusRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/status")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewListSyncRunsRequest generates requests for ListSyncRuns
+func NewListSyncRunsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/sync")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewTriggerSyncRequest generates requests for TriggerSync
+func NewTriggerSyncRequest(server string, params *TriggerSyncParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/sync")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Features != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "features", runtime.ParamLocationQuery, *params.Features); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Replicas != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "replicas", runtime.ParamLocationQuery, *params.Replicas); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetSyncRunRequest generates requests for GetSyncRun
+func NewGetSyncRunRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/sync/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetHealthzRequest generates requests for GetHealthz
+func NewGetHealthzRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/healthz")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewHeadHealthzRequest generates requests for HeadHealthz
+func NewHeadHealthzRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/healthz")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("HEAD", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetLivezRequest generates requests for GetLivez
+func NewGetLivezRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/livez")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewHeadLivezRequest generates requests for HeadLivez
+func NewHeadLivezRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/livez")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("HEAD", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetMetricsRequest generates requests for GetMetrics
+func NewGetMetricsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/metrics")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetReadyzRequest generates requests for GetReadyz
+func NewGetReadyzRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/readyz")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewHeadReadyzRequest generates requests for HeadReadyz
+func NewHeadReadyzRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/readyz")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("HEAD", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// ClearLogsWithResponse request
+	ClearLogsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ClearLogsResponse, error)
+
+	// StreamEventsWithResponse request
+	StreamEventsWithResponse(ctx context.Context, params *StreamEventsParams, reqEditors ...RequestEditorFn) (*StreamEventsResponse, error)
+
+	// TriggerHookWithBodyWithResponse request with any body
+	TriggerHookWithBodyWithResponse(ctx context.Context, name string, params *TriggerHookParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*TriggerHookResponse, error)
+
+	// GetInstanceWithResponse request
+	GetInstanceWithResponse(ctx context.Context, host InstanceHost, reqEditors ...RequestEditorFn) (*GetInstanceResponse, error)
+
+	// GetInstanceDiffWithResponse request
+	GetInstanceDiffWithResponse(ctx context.Context, host InstanceHost, params *GetInstanceDiffParams, reqEditors ...RequestEditorFn) (*GetInstanceDiffResponse, error)
+
+	// GetLogsWithResponse request
+	GetLogsWithResponse(ctx context.Context, params *GetLogsParams, reqEditors ...RequestEditorFn) (*GetLogsResponse, error)
+
+	// GetOpenAPIWithResponse request
+	GetOpenAPIWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetOpenAPIResponse, error)
+
+	// SearchQueryLogWithResponse request
+	SearchQueryLogWithResponse(ctx context.Context, params *SearchQueryLogParams, reqEditors ...RequestEditorFn) (*SearchQueryLogResponse, error)
+
+	// GetStatsWithResponse request
+	GetStatsWithResponse(ctx context.Context, params *GetStatsParams, reqEditors ...RequestEditorFn) (*GetStatsResponse, error)
+
+	// GetStatusWithResponse request
+	GetStatusWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetStatusResponse, error)
+
+	// ListSyncRunsWithResponse request
+	ListSyncRunsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListSyncRunsResponse, error)
+
+	// TriggerSyncWithResponse request
+	TriggerSyncWithResponse(ctx context.Context, params *TriggerSyncParams, reqEditors ...RequestEditorFn) (*TriggerSyncResponse, error)
+
+	// GetSyncRunWithResponse request
+	GetSyncRunWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetSyncRunResponse, error)
+
+	// GetHealthzWithResponse request
+	GetHealthzWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetHealthzResponse, error)
+
+	// HeadHealthzWithResponse request
+	HeadHealthzWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*HeadHealthzResponse, error)
+
+	// GetLivezWithResponse request
+	GetLivezWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetLivezResponse, error)
+
+	// HeadLivezWithResponse request
+	HeadLivezWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*HeadLivezResponse, error)
+
+	// GetMetricsWithResponse request
+	GetMetricsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetMetricsResponse, error)
+
+	// GetReadyzWithResponse request
+	GetReadyzWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetReadyzResponse, error)
+
+	// HeadReadyzWithResponse request
+	HeadReadyzWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*HeadReadyzResponse, error)
+}
+
+type ClearLogsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON403      *Forbidden
+}
+
+// Status returns HTTPResponse.Status
+func (r ClearLogsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ClearLogsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type StreamEventsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *BadRequest
+	JSON403      *Forbidden
+}
+
+// Status returns HTTPResponse.Status
+func (r StreamEventsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r StreamEventsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type TriggerHookResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON202      *SyncRun
+	JSON400      *BadRequest
+	JSON401      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r TriggerHookResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r TriggerHookResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetInstanceResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *InstanceDetail
+	JSON403      *Forbidden
+	JSON404      *NotFound
+}
+
+// Status returns HTTPResponse.Status
+func (r GetInstanceResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetInstanceResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetInstanceDiffResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *InstanceDiff
+	JSON400      *BadRequest
+	JSON403      *Forbidden
+	JSON404      *NotFound
+	JSON502      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetInstanceDiffResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetInstanceDiffResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetLogsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]LogEntry
+	JSON400      *BadRequest
+	JSON403      *Forbidden
+}
+
+// Status returns HTTPResponse.Status
+func (r GetLogsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetLogsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetOpenAPIResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *map[string]interface{}
+	JSON403      *Forbidden
+}
+
+// Status returns HTTPResponse.Status
+func (r GetOpenAPIResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetOpenAPIResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SearchQueryLogResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *QueryLogSearch
+	JSON400      *BadRequest
+	JSON403      *Forbidden
+	JSON502      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SearchQueryLogResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SearchQueryLogResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetStatsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *StatsHistory
+	JSON400      *BadRequest
+	JSON403      *Forbidden
+	JSON404      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetStatsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetStatsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetStatusResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SyncStatus
+	JSON403      *Forbidden
+}
+
+// Status returns HTTPResponse.Status
+func (r GetStatusResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetStatusResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListSyncRunsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]SyncRun
+	JSON403      *Forbidden
+}
+
+// Status returns HTTPResponse.Status
+func (r ListSyncRunsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListSyncRunsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type TriggerSyncResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON202      *SyncRun
+	JSON400      *BadRequest
+	JSON403      *Forbidden
+}
+
+// Status returns HTTPResponse.Status
+func (r TriggerSyncResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r TriggerSyncResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetSyncRunResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SyncRun
+	JSON403      *Forbidden
+	JSON404      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetSyncRunResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetSyncRunResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetHealthzResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r GetHealthzResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetHealthzResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type HeadHealthzResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r HeadHealthzResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r HeadHealthzResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetLivezResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r GetLivezResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetLivezResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type HeadLivezResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r HeadLivezResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r HeadLivezResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetMetricsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON403      *Forbidden
+}
+
+// Status returns HTTPResponse.Status
+func (r GetMetricsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetMetricsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetReadyzResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON503      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetReadyzResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetReadyzResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type HeadReadyzResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r HeadReadyzResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r HeadReadyzResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// ClearLogsWithResponse request returning *ClearLogsResponse
+func (c *ClientWithResponses) ClearLogsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ClearLogsResponse, error) {
+	rsp, err := c.ClearLogs(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseClearLogsResponse(rsp)
+}
+
+// StreamEventsWithResponse request returning *StreamEventsResponse
+func (c *ClientWithResponses) StreamEventsWithResponse(ctx context.Context, params *StreamEventsParams, reqEditors ...RequestEditorFn) (*StreamEventsResponse, error) {
+	rsp, err := c.StreamEvents(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStreamEventsResponse(rsp)
+}
+
+// TriggerHookWithBodyWithResponse request with arbitrary body returning *TriggerHookResponse
+func (c *ClientWithResponses) TriggerHookWithBodyWithResponse(ctx context.Context, name string, params *TriggerHookParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*TriggerHookResponse, error) {
+	rsp, err := c.TriggerHookWithBody(ctx, name, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTriggerHookResponse(rsp)
+}
+
+// GetInstanceWithResponse request returning *GetInstanceResponse
+func (c *ClientWithResponses) GetInstanceWithResponse(ctx context.Context, host InstanceHost, reqEditors ...RequestEditorFn) (*GetInstanceResponse, error) {
+	rsp, err := c.GetInstance(ctx, host, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetInstanceResponse(rsp)
+}
+
+// GetInstanceDiffWithResponse request returning *GetInstanceDiffResponse
+func (c *ClientWithResponses) GetInstanceDiffWithResponse(ctx context.Context, host InstanceHost, params *GetInstanceDiffParams, reqEditors ...RequestEditorFn) (*GetInstanceDiffResponse, error) {
+	rsp, err := c.GetInstanceDiff(ctx, host, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetInstanceDiffResponse(rsp)
+}
+
+// GetLogsWithResponse request returning *GetLogsResponse
+func (c *ClientWithResponses) GetLogsWithResponse(ctx context.Context, params *GetLogsParams, reqEditors ...RequestEditorFn) (*GetLogsResponse, error) {
+	rsp, err := c.GetLogs(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetLogsResponse(rsp)
+}
+
+// GetOpenAPIWithResponse request returning *GetOpenAPIResponse
+func (c *ClientWithResponses) GetOpenAPIWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetOpenAPIResponse, error) {
+	rsp, err := c.GetOpenAPI(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetOpenAPIResponse(rsp)
+}
+
+// SearchQueryLogWithResponse request returning *SearchQueryLogResponse
+func (c *ClientWithResponses) SearchQueryLogWithResponse(ctx context.Context, params *SearchQueryLogParams, reqEditors ...RequestEditorFn) (*SearchQueryLogResponse, error) {
+	rsp, err := c.SearchQueryLog(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSearchQueryLogResponse(rsp)
+}
+
+// GetStatsWithResponse request returning *GetStatsResponse
+func (c *ClientWithResponses) GetStatsWithResponse(ctx context.Context, params *GetStatsParams, reqEditors ...RequestEditorFn) (*GetStatsResponse, error) {
+	rsp, err := c.GetStats(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetStatsResponse(rsp)
+}
+
+// GetStatusWithResponse request returning *GetStatusResponse
+func (c *ClientWithResponses) GetStatusWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetStatusResponse, error) {
+	rsp, err := c.GetStatus(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetStatusResponse(rsp)
+}
+
+// ListSyncRunsWithResponse request returning *ListSyncRunsResponse
+func (c *ClientWithResponses) ListSyncRunsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListSyncRunsResponse, error) {
+	rsp, err := c.ListSyncRuns(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListSyncRunsResponse(rsp)
+}
+
+// TriggerSyncWithResponse request returning *TriggerSyncResponse
+func (c *ClientWithResponses) TriggerSyncWithResponse(ctx context.Context, params *TriggerSyncParams, reqEditors ...RequestEditorFn) (*TriggerSyncResponse, error) {
+	rsp, err := c.TriggerSync(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTriggerSyncResponse(rsp)
+}
+
+// GetSyncRunWithResponse request returning *GetSyncRunResponse
+func (c *ClientWithResponses) GetSyncRunWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetSyncRunResponse, error) {
+	rsp, err := c.GetSyncRun(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetSyncRunResponse(rsp)
+}
+
+// GetHealthzWithResponse request returning *GetHealthzResponse
+func (c *ClientWithResponses) GetHealthzWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetHealthzResponse, error) {
+	rsp, err := c.GetHealthz(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetHealthzResponse(rsp)
+}
+
+// HeadHealthzWithResponse request returning *HeadHealthzResponse
+func (c *ClientWithResponses) HeadHealthzWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*HeadHealthzResponse, error) {
+	rsp, err := c.HeadHealthz(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseHeadHealthzResponse(rsp)
+}
+
+// GetLivezWithResponse request returning *GetLivezResponse
+func (c *ClientWithResponses) GetLivezWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetLivezResponse, error) {
+	rsp, err := c.GetLivez(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetLivezResponse(rsp)
+}
+
+// HeadLivezWithResponse request returning *HeadLivezResponse
+func (c *ClientWithResponses) HeadLivezWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*HeadLivezResponse, error) {
+	rsp, err := c.HeadLivez(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseHeadLivezResponse(rsp)
+}
+
+// GetMetricsWithResponse request returning *GetMetricsResponse
+func (c *ClientWithResponses) GetMetricsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetMetricsResponse, error) {
+	rsp, err := c.GetMetrics(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetMetricsResponse(rsp)
+}
+
+// GetReadyzWithResponse request returning *GetReadyzResponse
+func (c *ClientWithResponses) GetReadyzWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetReadyzResponse, error) {
+	rsp, err := c.GetReadyz(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetReadyzResponse(rsp)
+}
+
+// HeadReadyzWithResponse request returning *HeadReadyzResponse
+func (c *ClientWithResponses) HeadReadyzWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*HeadReadyzResponse, error) {
+	rsp, err := c.HeadReadyz(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseHeadReadyzResponse(rsp)
+}
+
+// ParseClearLogsResponse parses an HTTP response from a ClearLogsWithResponse call
+func ParseClearLogsResponse(rsp *http.Response) (*ClearLogsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ClearLogsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseStreamEventsResponse parses an HTTP response from a StreamEventsWithResponse call
+func ParseStreamEventsResponse(rsp *http.Response) (*StreamEventsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &StreamEventsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseTriggerHookResponse parses an HTTP response from a TriggerHookWithResponse call
+func ParseTriggerHookResponse(rsp *http.Response) (*TriggerHookResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &TriggerHookResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 202:
+		var dest SyncRun
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON202 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetInstanceResponse parses an HTTP response from a GetInstanceWithResponse call
+func ParseGetInstanceResponse(rsp *http.Response) (*GetInstanceResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetInstanceResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest InstanceDetail
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetInstanceDiffResponse parses an HTTP response from a GetInstanceDiffWithResponse call
+func ParseGetInstanceDiffResponse(rsp *http.Response) (*GetInstanceDiffResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetInstanceDiffResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest InstanceDiff
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 502:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON502 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetLogsResponse parses an HTTP response from a GetLogsWithResponse call
+func ParseGetLogsResponse(rsp *http.Response) (*GetLogsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetLogsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []LogEntry
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case rsp.StatusCode == 200:
+		// Content-type (text/plain) unsupported
+
+	}
+
+	return response, nil
+}
+
+// ParseGetOpenAPIResponse parses an HTTP response from a GetOpenAPIWithResponse call
+func ParseGetOpenAPIResponse(rsp *http.Response) (*GetOpenAPIResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetOpenAPIResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest map[string]interface{}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSearchQueryLogResponse parses an HTTP response from a SearchQueryLogWithResponse call
+func ParseSearchQueryLogResponse(rsp *http.Response) (*SearchQueryLogResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SearchQueryLogResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest QueryLogSearch
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 502:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON502 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetStatsResponse parses an HTTP response from a GetStatsWithResponse call
+func ParseGetStatsResponse(rsp *http.Response) (*GetStatsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetStatsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest StatsHistory
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetStatusResponse parses an HTTP response from a GetStatusWithResponse call
+func ParseGetStatusResponse(rsp *http.Response) (*GetStatusResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetStatusResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest SyncStatus
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListSyncRunsResponse parses an HTTP response from a ListSyncRunsWithResponse call
+func ParseListSyncRunsResponse(rsp *http.Response) (*ListSyncRunsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListSyncRunsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []SyncRun
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseTriggerSyncResponse parses an HTTP response from a TriggerSyncWithResponse call
+func ParseTriggerSyncResponse(rsp *http.Response) (*TriggerSyncResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &TriggerSyncResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 202:
+		var dest SyncRun
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON202 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetSyncRunResponse parses an HTTP response from a GetSyncRunWithResponse call
+func ParseGetSyncRunResponse(rsp *http.Response) (*GetSyncRunResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetSyncRunResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest SyncRun
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetHealthzResponse parses an HTTP response from a GetHealthzWithResponse call
+func ParseGetHealthzResponse(rsp *http.Response) (*GetHealthzResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetHealthzResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseHeadHealthzResponse parses an HTTP response from a HeadHealthzWithResponse call
+func ParseHeadHealthzResponse(rsp *http.Response) (*HeadHealthzResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &HeadHealthzResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseGetLivezResponse parses an HTTP response from a GetLivezWithResponse call
+func ParseGetLivezResponse(rsp *http.Response) (*GetLivezResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetLivezResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseHeadLivezResponse parses an HTTP response from a HeadLivezWithResponse call
+func ParseHeadLivezResponse(rsp *http.Response) (*HeadLivezResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &HeadLivezResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseGetMetricsResponse parses an HTTP response from a GetMetricsWithResponse call
+func ParseGetMetricsResponse(rsp *http.Response) (*GetMetricsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetMetricsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetReadyzResponse parses an HTTP response from a GetReadyzWithResponse call
+func ParseGetReadyzResponse(rsp *http.Response) (*GetReadyzResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetReadyzResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 503:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON503 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseHeadReadyzResponse parses an HTTP response from a HeadReadyzWithResponse call
+func ParseHeadReadyzResponse(rsp *http.Response) (*HeadReadyzResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &HeadReadyzResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Clear the application logs
+	// (POST /api/v1/clear-logs)
+	ClearLogs(c *gin.Context)
+	// Stream the log entries and sync events as server-sent events
+	// (GET /api/v1/events)
+	StreamEvents(c *gin.Context, params StreamEventsParams)
+	// Trigger a sync with a signed webhook
+	// (POST /api/v1/hooks/{name})
+	TriggerHook(c *gin.Context, name string, params TriggerHookParams)
+	// Get the detail of the origin or a replica
+	// (GET /api/v1/instances/{host})
+	GetInstance(c *gin.Context, host InstanceHost)
+	// Get the differences of a replica to the origin
+	// (GET /api/v1/instances/{host}/diff)
+	GetInstanceDiff(c *gin.Context, host InstanceHost, params GetInstanceDiffParams)
+	// Get the application logs, oldest first
+	// (GET /api/v1/logs)
+	GetLogs(c *gin.Context, params GetLogsParams)
+	// Get this OpenAPI document
+	// (GET /api/v1/openapi.json)
+	GetOpenAPI(c *gin.Context)
+	// Search the query logs of the origin and all replicas
+	// (GET /api/v1/querylog)
+	SearchQueryLog(c *gin.Context, params SearchQueryLogParams)
+	// Get the stats history of the instances
+	// (GET /api/v1/stats)
+	GetStats(c *gin.Context, params GetStatsParams)
+	// Get the status of the origin and the replicas
+	// (GET /api/v1/status)
+	GetStatus(c *gin.Context)
+	// List the sync runs, newest first
+	// (GET /api/v1/sync)
+	ListSyncRuns(c *gin.Context)
+	// Trigger a sync
+	// (POST /api/v1/sync)
+	TriggerSync(c *gin.Context, params TriggerSyncParams)
+	// Get the status of a sync run
+	// (GET /api/v1/sync/{id})
+	GetSyncRun(c *gin.Context, id string)
+	// Check that the sync is ready
+	// (GET /healthz)
+	GetHealthz(c *gin.Context)
+	// Check that the sync is ready
+	// (HEAD /healthz)
+	HeadHealthz(c *gin.Context)
+	// Check that the sync process is alive
+	// (GET /livez)
+	GetLivez(c *gin.Context)
+	// Check that the sync process is alive
+	// (HEAD /livez)
+	HeadLivez(c *gin.Context)
+	// Get the Prometheus metrics
+	// (GET /metrics)
+	GetMetrics(c *gin.Context)
+	// Check that the sync is ready
+	// (GET /readyz)
+	GetReadyz(c *gin.Context)
+	// Check that the sync is ready
+	// (HEAD /readyz)
+	HeadReadyz(c *gin.Context)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandler       func(*gin.Context, error, int)
+}
+
+type MiddlewareFunc func(c *gin.Context)
+
+// ClearLogs operation middleware
+func (siw *ServerInterfaceWrapper) ClearLogs(c *gin.Context) {
+
+	c.Set(BasicAuthScopes, []string{"operator"})
+
+	c.Set(BearerAuthScopes, []string{"operator"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ClearLogs(c)
+}
+
+// StreamEvents operation middleware
+func (siw *ServerInterfaceWrapper) StreamEvents(c *gin.Context) {
+
+	var err error
+
+	c.Set(BasicAuthScopes, []string{"viewer"})
+
+	c.Set(BearerAuthScopes, []string{"viewer"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StreamEventsParams
+
+	// ------------- Optional query parameter "level" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "level", c.Request.URL.Query(), &params.Level)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter level: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "replica" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "replica", c.Request.URL.Query(), &params.Replica)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter replica: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.StreamEvents(c, params)
+}
+
+// TriggerHook operation middleware
+func (siw *ServerInterfaceWrapper) TriggerHook(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", c.Param("name"), &name, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter name: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params TriggerHookParams
+
+	headers := c.Request.Header
+
+	// ------------- Required header parameter "X-Sync-Timestamp" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Sync-Timestamp")]; found {
+		var XSyncTimestamp int64
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for X-Sync-Timestamp, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Sync-Timestamp", valueList[0], &XSyncTimestamp, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter X-Sync-Timestamp: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.XSyncTimestamp = XSyncTimestamp
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Header parameter X-Sync-Timestamp is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Required header parameter "X-Sync-Signature" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Sync-Signature")]; found {
+		var XSyncSignature string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for X-Sync-Signature, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Sync-Signature", valueList[0], &XSyncSignature, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter X-Sync-Signature: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.XSyncSignature = XSyncSignature
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Header parameter X-Sync-Signature is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.TriggerHook(c, name, params)
+}
+
+// GetInstance operation middleware
+func (siw *ServerInterfaceWrapper) GetInstance(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "host" -------------
+	var host InstanceHost
+
+	err = runtime.BindStyledParameterWithOptions("simple", "host", c.Param("host"), &host, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter host: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BasicAuthScopes, []string{"viewer"})
+
+	c.Set(BearerAuthScopes, []string{"viewer"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetInstance(c, host)
+}
+
+// GetInstanceDiff operation middleware
+func (siw *ServerInterfaceWrapper) GetInstanceDiff(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "host" -------------
+	var host InstanceHost
+
+	err = runtime.BindStyledParameterWithOptions("simple", "host", c.Param("host"), &host, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter host: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BasicAuthScopes, []string{"viewer"})
+
+	c.Set(BearerAuthScopes, []string{"viewer"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetInstanceDiffParams
+
+	// ------------- Optional query parameter "features" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "features", c.Request.URL.Query(), &params.Features)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter features: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetInstanceDiff(c, host, params)
+}
+
+// GetLogs operation middleware
+func (siw *ServerInterfaceWrapper) GetLogs(c *gin.Context) {
+
+	var err error
+
+	c.Set(BasicAuthScopes, []string{"viewer"})
+
+	c.Set(BearerAuthScopes, []string{"viewer"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetLogsParams
+
+	// ------------- Optional query parameter "level" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "level", c.Request.URL.Query(), &params.Level)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter level: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "logger" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logger", c.Request.URL.Query(), &params.Logger)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter logger: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "host" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "host", c.Request.URL.Query(), &params.Host)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter host: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", c.Request.URL.Query(), &params.Since)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter since: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", c.Request.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter format: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetLogs(c, params)
+}
+
+// GetOpenAPI operation middleware
+func (siw *ServerInterfaceWrapper) GetOpenAPI(c *gin.Context) {
+
+	c.Set(BasicAuthScopes, []string{"viewer"})
+
+	c.Set(BearerAuthScopes, []string{"viewer"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetOpenAPI(c)
+}
+
+// SearchQueryLog operation middleware
+func (siw *ServerInterfaceWrapper) SearchQueryLog(c *gin.Context) {
+
+	var err error
+
+	c.Set(BasicAuthScopes, []string{"viewer"})
+
+	c.Set(BearerAuthScopes, []string{"viewer"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SearchQueryLogParams
+
+	// ------------- Optional query parameter "search" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "search", c.Request.URL.Query(), &params.Search)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter search: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "client" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "client", c.Request.URL.Query(), &params.Client)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter client: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", c.Request.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter status: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "older_than" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "older_than", c.Request.URL.Query(), &params.OlderThan)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter older_than: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.SearchQueryLog(c, params)
+}
+
+// GetStats operation middleware
+func (siw *ServerInterfaceWrapper) GetStats(c *gin.Context) {
+
+	var err error
+
+	c.Set(BasicAuthScopes, []string{"viewer"})
+
+	c.Set(BearerAuthScopes, []string{"viewer"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsParams
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", c.Request.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter from: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", c.Request.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter to: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "instance" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "instance", c.Request.URL.Query(), &params.Instance)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter instance: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetStats(c, params)
+}
+
+// GetStatus operation middleware
+func (siw *ServerInterfaceWrapper) GetStatus(c *gin.Context) {
+
+	c.Set(BasicAuthScopes, []string{"viewer"})
+
+	c.Set(BearerAuthScopes, []string{"viewer"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetStatus(c)
+}
+
+// ListSyncRuns operation middleware
+func (siw *ServerInterfaceWrapper) ListSyncRuns(c *gin.Context) {
+
+	c.Set(BasicAuthScopes, []string{"viewer"})
+
+	c.Set(BearerAuthScopes, []string{"viewer"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListSyncRuns(c)
+}
+
+// TriggerSync operation middleware
+func (siw *ServerInterfaceWrapper) TriggerSync(c *gin.Context) {
+
+	var err error
+
+	c.Set(BasicAuthScopes, []string{"operator"})
+
+	c.Set(BearerAuthScopes, []string{"operator"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params TriggerSyncParams
+
+	// ------------- Optional query parameter "features" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "features", c.Request.URL.Query(), &params.Features)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter features: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "replicas" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "replicas", c.Request.URL.Query(), &params.Replicas)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter replicas: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.TriggerSync(c, params)
+}
+
+// GetSyncRun operation middleware
+func (siw *ServerInterfaceWrapper) GetSyncRun(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BasicAuthScopes, []string{"viewer"})
+
+	c.Set(BearerAuthScopes, []string{"viewer"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetSyncRun(c, id)
+}
+
+// GetHealthz operation middleware
+func (siw *ServerInterfaceWrapper) GetHealthz(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetHealthz(c)
+}
+
+// HeadHealthz operation middleware
+func (siw *ServerInterfaceWrapper) HeadHealthz(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.HeadHealthz(c)
+}
+
+// GetLivez operation middleware
+func (siw *ServerInterfaceWrapper) GetLivez(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetLivez(c)
+}
+
+// HeadLivez operation middleware
+func (siw *ServerInterfaceWrapper) HeadLivez(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.HeadLivez(c)
+}
+
+// GetMetrics operation middleware
+func (siw *ServerInterfaceWrapper) GetMetrics(c *gin.Context) {
+
+	c.Set(BasicAuthScopes, []string{"viewer"})
+
+	c.Set(BearerAuthScopes, []string{"viewer"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetMetrics(c)
+}
+
+// GetReadyz operation middleware
+func (siw *ServerInterfaceWrapper) GetReadyz(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetReadyz(c)
+}
+
+// HeadReadyz operation middleware
+func (siw *ServerInterfaceWrapper) HeadReadyz(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.HeadReadyz(c)
+}
+
+// GinServerOptions provides options for the Gin server.
+type GinServerOptions struct {
+	BaseURL      string
+	Middlewares  []MiddlewareFunc
+	ErrorHandler func(*gin.Context, error, int)
+}
+
+// RegisterHandlers creates http.Handler with routing matching OpenAPI spec.
+func RegisterHandlers(router gin.IRouter, si ServerInterface) {
+	RegisterHandlersWithOptions(router, si, GinServerOptions{})
+}
+
+// RegisterHandlersWithOptions creates http.Handler with additional options
+func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options GinServerOptions) {
+	errorHandler := options.ErrorHandler
+	if errorHandler == nil {
+		errorHandler = func(c *gin.Context, err error, statusCode int) {
+			c.JSON(statusCode, gin.H{"msg": err.Error()})
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandler:       errorHandler,
+	}
+
+	router.POST(options.BaseURL+"/api/v1/clear-logs", wrapper.ClearLogs)
+	router.GET(options.BaseURL+"/api/v1/events", wrapper.StreamEvents)
+	router.POST(options.BaseURL+"/api/v1/hooks/:name", wrapper.TriggerHook)
+	router.GET(options.BaseURL+"/api/v1/instances/:host", wrapper.GetInstance)
+	router.GET(options.BaseURL+"/api/v1/instances/:host/diff", wrapper.GetInstanceDiff)
+	router.GET(options.BaseURL+"/api/v1/logs", wrapper.GetLogs)
+	router.GET(options.BaseURL+"/api/v1/openapi.json", wrapper.GetOpenAPI)
+	router.GET(options.BaseURL+"/api/v1/querylog", wrapper.SearchQueryLog)
+	router.GET(options.BaseURL+"/api/v1/stats", wrapper.GetStats)
+	router.GET(options.BaseURL+"/api/v1/status", wrapper.GetStatus)
+	router.GET(options.BaseURL+"/api/v1/sync", wrapper.ListSyncRuns)
+	router.POST(options.BaseURL+"/api/v1/sync", wrapper.TriggerSync)
+	router.GET(options.BaseURL+"/api/v1/sync/:id", wrapper.GetSyncRun)
+	router.GET(options.BaseURL+"/healthz", wrapper.GetHealthz)
+	router.HEAD(options.BaseURL+"/healthz", wrapper.HeadHealthz)
+	router.GET(options.BaseURL+"/livez", wrapper.GetLivez)
+	router.HEAD(options.BaseURL+"/livez", wrapper.HeadLivez)
+	router.GET(options.BaseURL+"/metrics", wrapper.GetMetrics)
+	router.GET(options.BaseURL+"/readyz", wrapper.GetReadyz)
+	router.HEAD(options.BaseURL+"/readyz", wrapper.HeadReadyz)
+}
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/+x8eY/URpv4Vyn595MW3td0DxCy2Yn2jwlHGGkCLEP0rsQgutr1dLsydpVTVe6mQfPd",
+	"V08dPtrlPmCAZLV/0WPX8dy3+ZRksqykAGF0cvopqaiiJRhQ9q9zoQ0VGTyX2uDfDHSmeGW4FMlp8iYH",
+	"kkttUrKGuf1FpCK/v74gckFMDkQqvuQCH1KioCp4RpM04bi3oiZP0kTQEpLTBPcmaaLgz5orYMmpUTWk",
+	"ic5yKClebDYVrtNGcbFMbm5ucLGupNBgAf2FstfwZw0OzEwKA8L+pJW9FiGe/qER7E+dY/+/gkVymvy/",
+	"aUuEqXurp0+Vkspd1Uf7XKxowRnpkOomTZ5JNeeMgfj6ACDdlSwgkLnWoJDIRl6DIFwTIQ3R9WLBM45Q",
+	"3KTJC2meyVqwrw/c7+JayLUg3IsO3v67oLXJpeIfgQ3F6Kw2OQjj4SCNEODZ/jrc9IQvFo9zKpaAf1VK",
+	"VqAMd+xfcCiYjkso44sFoNgQtwqpVleMGmCEGyg1oYIRDcZwsdQon/hwH/7P8CwECfHz0kmVopvkxp0Q",
+	"kdo0QZipg+1TAqIuk9O3CWUsSRMHUoLkLMD+0GCSd+lA9Ltq8rZzor+23SHnf0Bmue+YNaAahMe7r3DL",
+	"Yuc+A2pqBZYMUdpnll+aUKI3IiNrWReMoNBtyMJahYU74WciZLOYO6n2r1CeubD7J0m6hYHfgj8P4lpH",
+	"hiJsG6NHmnhYou/0Na8qYHECdJBwSllVUqHkzTcWSW8VyQqUdkz058+lLICKATMCJGmDe5QxjXQOmO5s",
+	"Mv4SdVHQeQHB2g4wszY6hrKH+oBDtqD3Vr/1BR6aGA7B9zwBQ3lhrVVRvFwkp2938zjsuzTU1GiatylA",
+	"i0KuC66N3oEAFwaWoBCQrOAgDl284EVwnkNpEHU5R1O9IPNCZtfEAZEecGxBtXmPOrBPwi83InsNui4s",
+	"DVtm94H5Vw4mB2VFMBhqlNHWaUckEfm2VtzAgaTQhhqevS+A6kO3oCd7r+oC9hIQVxK7MiVrbnJZG5LJ",
+	"sgRxIEWDxu01fw09GuxbkWj5nXbFqofINiGGsv6uK+1RpfVaf7ih61rmiKXLfTw3dFCNwOwmig/YGto0",
+	"AO7S5CPdUJo0McReeJqV6Q53tWUZjgBklF6VkgYylND3IFDg2A5B72iSbkAYCrl7R6gmjOp8LqliJJOF",
+	"VEnaxAy6zjLQNloRC5mkyZoqgTClCUO3oCKRQ5rUqjiYtbi2gTNGzQu5fCqM2uyKxihjHDGjxavOih5R",
+	"2gMLWEGxT7Av5PLCrsMNcomYxvhSgtZ0GXfYhpf2xUKqkprkNMHA6559us9/+UUO0vaWEfJcBIQC1xjM",
+	"62WfZ43ApgmrqOBZkibh3wU1tIgy8r9qUJtxBlCh16D61mJIhi2bkNEsd+I7FFdn76LHuFcvaBknNpMl",
+	"jRqUNIGCVhrYb13wnIHf1v142hnSn7CSmJwa4nAHZl/9iXRK0lgEQ326MzzbmXRMGNyqlMBkOSGzZ/Y5",
+	"sF8Kml1fcG1m0ZPHvVfnYFzk4C2psXRPj2DVLuvx5MUlCamxNyUBgRcvn75+/fJ1FOxjlCI8GLve5uGY",
+	"yuGycPlZ9Nq60kYBLY+y8B4qL1pdQWqkOKaRQWkugaosj5h/YRQ/wsf2lXAsnzj8vL6bjJwnCwZjdZgZ",
+	"vlTvTU7FLCiGgA+GVHQJqUs+wPjcSgGhCjDnKqUCEhDfZ/3Cuhhx0afq51wbGfUISpaHS1dg9eGk695+",
+	"CRbICP0UaFnU2+l3LmtrfekmamqNPEItJBrsuFOvy8AWDQVktvrQ4Jkej+YryV1pp4/ldrqIhLdI9NAP",
+	"oHZpvY+r7sIBa20mA+xZsG0dTe5E20xoVBe/Z/geM0KaAXtFFQhPxPFVl3QBcyXXevS+YM6i0ZVqXAfy",
+	"HitnyPz0CwKCDnrpkCIjgEew3seDy4aEfSYc7yxj4lshhz9P6Q6Txs7l/q4owhuRPV1BTNhoZuI5267i",
+	"zdFxZae8EXHv4qAUvD40zPd8UbUgd/6soQaW4h8YzKfEhvnA8NmC8gLY3bQpGd2xkgwstupKYHXNEovc",
+	"sUXe9mVKfMXq7pW4vUggWFOkT7fA4zm2t45p36bbAfaO9KNT6IiSV9l3gbxYP3E1SCN7zYi+fLG6Lc8O",
+	"jwxvbT0SMilYx2e2keuOMiIXXPsg+zDytgJ0gMSFitcWaf0ZnevTFtFR0jo5PzRB7hYoIqIePF5YlhJa",
+	"FBiMQFmZzVGx7/E05Cx6sFO2w4/xMrMPxbDsC1D0iv2VpSRNjOIhgd4uD1JD/FufSam6yYNoxWcpmWVK",
+	"CvzXQltXM/Sjs1zK69Or+uTkYSZoCfYXzPa6UY4yGcBJW4n1PNohpG0tJ1ggvydNvBXF84KBtEk1L3pH",
+	"tmTEI8dqQ21h7Kjac09qjsoC2iNGqkmtuDhKiH4w1MnefbcrxmcQLiTt+aImJiRrqkkli8JR7vjwqAta",
+	"2q2keqIMGYv4QFYrbjaXSBEfZFLNM+wTNg1KiyI+bWHIjakQ3TlQBWq42j7eXn5z48sxw7bkq3N0Fl4q",
+	"bZOwlIIb6crmiFqupOAfnU+QC3LGfq2xWPdclh0iTq7ElXAeyRGm6d1SQZr2HVbfl3wFAut+OpMVtOmC",
+	"I0fYb0vcp1dituKwBjUjJcV3lKVk5o6T/mFbfSs2DR4It2t5YshBWcmFjxQQ0q1eLNdEimJDQCykyjBl",
+	"WRCkS61B6dQ1nDXxFClgSbONfYe6b++oqNZrqZjNNzMpFnxZK2ATG3oUPAOhbfjg5wDOKkzf7z2YnPgK",
+	"pGOTPp1O1+v1hNrXE6mWU79XTy/OHz99cfnU7rHRiynsSY4XlhXW75+9Ok86Vf9kdd83ZAWteHKaPJyc",
+	"TB4mrullZW5KKz5d3Z9mBVB1r5BL+7TyleCGcecsOU0e45oLadvHvbmEBycncX+B5zmi4FZgCMwPJ/fH",
+	"rENz6LTXS7ebHu7f1A4ndBXMttI6qhW6yVg+x7ZZV5F6796hSS1LqjYBdysAnWkCi1+SJoYi2d4m9s93",
+	"eHkgK6xCU20JYzWNQi5nxC1E4TEUZZXMQs1lltprZ8jfZh0lsyaBmE2uxDkrgLgikyYKMuArINcAFaEF",
+	"/gxNIyeSfbZe2m1PV77X052NeRsDuOSCl3VJbPja6K89BFgos1i9gHAm+pQklCm9FjTR70GTGG3mcpNu",
+	"A/USldcBYGEZghCgDFkF9tLsA2eMfDMgBmUbQ4+P6byLq0NnAsXAB+Nk4V5bCdw59zMku93u0XQqcbJf",
+	"JToTQ99S9Rq1uWy5UshljzPWXgVx1kSDWoG6pxHJRm7GFQtDMD39hFy66VqsLdcPc7vQ2iDaWv12NOH5",
+	"b2eP710+P3vw6Eei+VK4QQYvLuj9taFlZQE23ruBNmQu2eZKNGKkIVPQqbfI69S6RqAMH1rrY+9HL+IM",
+	"jncr6OL++x7q8r3LcP0p0Tl98OjH/8zhw528pNl79/cdd03ageuf5CqZXCXknxaiu3fRFniGayfltLPa",
+	"Vk+xIC/II1Tj2oD1a1QQWqBztX6NdQiBdFPwhw36Gxcf8hvnX32U0/F7duolEMIegbwGFjM+b5zDfi7l",
+	"9SG2x7rcDpnjg3b2n2MG7dLYXYa3dwW+c0FqwT90EmN7fQ6U2ajLA+A5+iZQficwTcTJhfnxh2TYxo+D",
+	"N5BWDyMmK058ZmQhsV3fSnsOHwiITDJgVvL3wN9I5FHEfOcWgza/SLbZsoT/mP6jb/sa7Odc0FgbK24N",
+	"K7oppNMuRGztFN0nb/ikqnXubSYGoeRXbryNSW4G9vrBrU0MNmWxONjW6GFIyjVpUjhHfQvKhcx2lGZQ",
+	"yhuHG05q0sgd/uSL3MW3maQMRtNOnaYEPlQ2i5DKWhi66Zql7fiuF6m9CamMn8ZzRhD3Agty0nEtuKjv",
+	"WpqcZvoJA4Ob0ejtQlLmpol8wJ2SdlKicRmZrHsRiBuvSYmfrkl9w9Q7RTtEQ9wQDcG+Ri9XnVyJx+44",
+	"29mqXe3F5LDBawpmO2Bz9LWUxe3tr2DOO/Xpvr2NMbBdMu3NSR8Q9Xy+tGxNxY0oU9MSZ37ZN4pwcMcP",
+	"+3c008j9kOhXMJanDujdQ+RBSH1Kjtq8U1SnzA9WReX1dS10azsiITGOl9m5Vezec+P9u6mV39fMrpru",
+	"kGuK3sXHBHtEzk5qfZnYxaN/3IkqYUbqsNrFJM40IHbOOkzIY1mWlGjA+0xjbwB/T0ZSgnBsz+QeWvf8",
+	"NmpjB+LiSuMGxcEWvozsyN5fOac4WuPS5NHJg6/vvN70NLerThFzPGYHOgyxkUo4os+d/cYgFG+87g+U",
+	"0FduPi+/b6cnvm4uvx3ku+k3cscV5V0NBEvwznvO7mLIkBU1cxZLkyznBfO7RqGVoQJ/XELQ7TC3XG/Y",
+	"1VKJKFjYDG8EAF9vOOL6M/L62eOHDx/+h89K0E00jTpPnPuPytndBj47RbOHbZq7MOBIOjSDVy52T8kM",
+	"1WmGMS0ttGwNcJMfz86yDCozIy7Uxe5KXxdnY7bW3tADMXRAsKaSpAnujjVev9TOHtTGGJ+Lukld0acq",
+	"/GDgkdWeTrXkb1PsCRZtu0KaBmlccKVNx5INyzq+Uj0J/BizZS8rEK7W/UVM3m7LRFnh7yJMZnUJ4rsS",
+	"l+shOPs9g1WqQi5HI0M3LAi6HSe1AigXBGiWt4G2bQ+BWvqVwdxhdYGXkBIB64bPWJVuWmx2CLTnE7W7",
+	"kvkSk/9giAsycwOFs599MIfL7FCH/WZKSAH+oM4h0bK2fRfGF/c5vpeuAeTw8TmjG71EY+u8TajOo6+x",
+	"WR8tahgzrfb242xrDwZvx/3NWwXr81fY9lKgdUoe2xXnTxBOX/iKAeQO+gKAGhC25m7HCDAsSzSfAhZF",
+	"800JsHaWrP31XvdHyMLjKoyQpck65wYKro3d59JqAwIvpAt439C/UjIDraPN8Lh7K+kHG/+0n+F4EqSE",
+	"wYLivM39k5MJObNTp4RJ0OLfDAHhHB4X2zzUGM9YBbkS3JAcsxFsarbnSuVmVPnCzVMY3ZyR0xX0T3HC",
+	"Hg1seMn7LPa4JKf3T05O0sSHdsnp/UOqjH15bGvHCE03HOlU3WbO0jfDuZWCFZe1tqQagbqd6f3CLsvn",
+	"B/hbQ9MjjqA1jH8Dz/yNMqAXsvUOA7O83Qyyj/s+Rm8F1OhhUAWa4Yn93g0tze4WKw7A2hYhNZHxD+eA",
+	"MqmwKi6Fc3klGMUzTXSmaOUc3zVUpmltdLodCpC+XArsvbgCSV1h9vbvOHHrzqfLpYKlrTA4YFJSSIGZ",
+	"jRSAn0DxYjMhVuHoinI7CRM+EQ6g4Dn+86uRMoudVd3n6Q5KJX78KZ/dJXNYSDtBv27bvJ25YoXItkZx",
+	"9uCHfDSOdxPat5XxDAFD6xsHS8j1CFBGHgfS5/xPEH5ArhE2G8LYrxRGgOpMD38fW9j7xmGsjWE1KQ+L",
+	"/gaFo69fB+qqKbLYq2ooDHU/QK5dz2MkfeoRd2CuDjSI9W6L6JYQHgbfMPDGa+Y0u14qWftYZtvUhW3C",
+	"gFrRYjJmhGqdfE0JbScYd7XZPBm+ayrcHzns+LhOrfAglvqP4kcZake/75+cYEvQySC6qyGD8Is+36PU",
+	"ybcokXS+E9iqkOxskH4nviF5eu1V3U9qh43DdGT0pIcMoWGQUtYaPf2/cl5AaFFy3X4Lgd9KWscigCxq",
+	"Zf//gl6/+OcrEZ77dob2PRoLd5jjcIvdQPOaMxDNEABXvkAnld4xkYF8OyhptiiM9F2aG50NcQ1sH2Mw",
+	"oSehG4p1S0XQ8FThte+Kzu7anud4k4b4/wRAE8Y1nXdsmb/T2mNY+aHQEDvdfmMn3UEd2SdQOz0/38Qj",
+	"is9qTHXsya00pv5vKuK71lVvdX61PxWxc/4Bn0w/cXZzKw4HIwLP/QN6T+dPtjkZn/Di7PiRpK8YjRwg",
+	"2//bQt4wt9PitycAol2WRqQvB1qY/GNP6CoFGTUth/sgPGne45QztbdMrfv7GJXD5/6GQ6fXG+eMR7qK",
+	"ysPdKzHu96t3TCg9ziG7diVxM7hmJBZ09vDWqfIcKPs7kwUFBwfcPx6S7QwqPx60TArNGShgQwJh39ye",
+	"fxR1fNHZdUT5Co7Ge3DAAWJxi1ijWPyV0Ua2+3x7lPGHF9NiTP/Nn37YaP/ndnkDND5ifaVkCSaHWhM8",
+	"1vfXv73nOLi4MWL0O3iUDR1HGels0ygfn2KbjRrfcsTFXKCMVLLg2YbQJUXRti/df4syJvg/Y7QdqoI7",
+	"Ss6krLVtUTqPtInKx2sH9JfazK9flvq2rukvzji0a7fEue/i7XamBe5LyVhS0LxBCOwMvIu/228PT6fT",
+	"Qma0wBT09KeTn07sfK8HYoBs5GNRhF93ur8Y10WK+luTIfYI+4Fa88lPO6OlIwe4WCUNkmIhaCyM39oh",
+	"2c27m/8ZAJ2FJOr7WQAA",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bakito/adguardhome-sync/api"
+	"github.com/bakito/adguardhome-sync/internal/config"
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+const (
+	flagClientURL                = "url"
+	flagClientUsername           = "username"
+	flagClientPassword           = "password"
+	flagClientToken              = "token"
+	flagClientInsecureSkipVerify = "insecure-skip-verify"
+	flagClientOutput             = "output"
+
+	outputText = "text"
+	outputJSON = "json"
+
+	// envClientToken the env variable of the bearer token used by the client commands.
+	envClientToken = "API_CLIENT_TOKEN"
+)
+
+var (
+	errInvalidOutput = errors.New("invalid output format")
+	errAPIResponse   = errors.New("unexpected sync API response")
+	errAPIDisabled   = errors.New("the sync API is disabled (api.port is 0); use --" + flagClientURL)
+)
+
+// clientOptions the options of the commands talking to the API of a running daemon.
+type clientOptions struct {
+	url                string
+	username           string
+	password           string
+	token              string
+	insecureSkipVerify bool
+	output             string
+}
+
+// addClientFlags adds the flags to connect to the API of a running daemon.
+func addClientFlags(cmd *cobra.Command, opts *clientOptions) {
+	cmd.Flags().StringVar(&opts.url, flagClientURL, "",
+		"URL of the sync API (default: localhost with the configured API port and TLS)")
+	cmd.Flags().StringVar(&opts.username, flagClientUsername, "",
+		"Sync API username (default: the configured API username)")
+	cmd.Flags().StringVar(&opts.password, flagClientPassword, "",
+		"Sync API password (default: the configured API password)")
+	cmd.Flags().StringVar(&opts.token, flagClientToken, "",
+		"Sync API bearer token (default: env "+envClientToken+")")
+	cmd.Flags().BoolVar(&opts.insecureSkipVerify, flagClientInsecureSkipVerify, false,
+		"Skip the verification of the sync API TLS certificate")
+	cmd.Flags().StringVarP(&opts.output, flagClientOutput, "o", outputText, "Output format (text or json)")
+}
+
+// newClient creates an API client from the options, completed by the API config of the daemon.
+func (o *clientOptions) newClient() (*api.ClientWithResponses, error) {
+	if o.output != outputText && o.output != outputJSON {
+		return nil, fmt.Errorf("%w %q: expected %s or %s", errInvalidOutput, o.output, outputText, outputJSON)
+	}
+	cfg, err := config.Get(cfgFile, nil)
+	if err != nil {
+		return nil, err
+	}
+	apiCfg := cfg.Get().API
+
+	server := o.url
+	if server == "" {
+		if server, err = apiURL(apiCfg); err != nil {
+			return nil, err
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.TLSClientConfig = tlsConfig(apiCfg.TLS, o.insecureSkipVerify)
+
+	token := o.token
+	if token == "" {
+		token = os.Getenv(envClientToken)
+	}
+	username, password := o.username, o.password
+	if username == "" && password == "" {
+		username, password = apiCfg.Username, apiCfg.Password
+	}
+
+	return api.NewClientWithResponses(server,
+		api.WithHTTPClient(&http.Client{Transport: transport}),
+		api.WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			} else if username != "" {
+				req.SetBasicAuth(username, password)
+			}
+			return nil
+		}),
+	)
+}
+
+// apiURL returns the URL of the API of a daemon running on this host.
+func apiURL(apiCfg types.API) (string, error) {
+	if apiCfg.Port == 0 {
+		return "", errAPIDisabled
+	}
+	if apiCfg.TLS.Enabled() {
+		return fmt.Sprintf("https://localhost:%d", apiCfg.Port), nil
+	}
+	return fmt.Sprintf("http://localhost:%d", apiCfg.Port), nil
+}
+
+// tlsConfig trusts the configured API certificate in addition to the system certificates.
+func tlsConfig(t types.TLS, insecureSkipVerify bool) *tls.Config {
+	// #nosec G402 has to be explicitly enabled
+	tc := &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+	if !t.Enabled() || insecureSkipVerify {
+		return tc
+	}
+	cert, _ := t.Certs()
+	pem, err := os.ReadFile(cert)
+	if err != nil {
+		// the certificate is not readable, rely on the system certificates
+		return tc
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	pool.AppendCertsFromPEM(pem)
+	tc.RootCAs = pool
+	return tc
+}
+
+// checkResponse returns an error if the API did not respond with the expected status.
+func checkResponse(resp interface{ StatusCode() int }, body []byte, want int) error {
+	if resp.StatusCode() == want {
+		return nil
+	}
+	msg := strings.TrimSpace(string(body))
+	var apiErr api.Error
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != "" {
+		msg = apiErr.Error
+	}
+	if msg == "" {
+		msg = http.StatusText(resp.StatusCode())
+	}
+	return fmt.Errorf("%w %d: %s", errAPIResponse, resp.StatusCode(), msg)
+}
+
+// printJSON prints the value as indented JSON.
+func printJSON(out io.Writer, v any) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// deref returns the value of an optional field or the zero value if not set.
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
@@ -1,7 +1,23 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/bakito/adguardhome-sync/api"
+	"github.com/bakito/adguardhome-sync/internal/types"
 )
 
 func Test_RootCommand(t *testing.T) {
@@ -64,3 +80,200 @@ func Test_RunCommand(t *testing.T) {
 		})
 	}
 }
+
+func Test_CredentialsCommands(t *testing.T) {
+	t.Run("should hash the password read from stdin", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		credentialsPasswordCmd.SetOut(out)
+		credentialsPasswordCmd.SetIn(strings.NewReader("secret\n"))
+		if err := credentialsPasswordCmd.RunE(credentialsPasswordCmd, nil); err != nil {
+			t.Fatalf("RunE() error = %v", err)
+		}
+		if err := bcrypt.CompareHashAndPassword(bytes.TrimSpace(out.Bytes()), []byte("secret")); err != nil {
+			t.Errorf("hash %q does not match the password: %v", out.String(), err)
+		}
+	})
+	t.Run("should hash the password argument with a warning", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+		credentialsPasswordCmd.SetOut(out)
+		credentialsPasswordCmd.SetErr(stderr)
+		defer credentialsPasswordCmd.SetErr(nil)
+		if err := credentialsPasswordCmd.RunE(credentialsPasswordCmd, []string{"secret"}); err != nil {
+			t.Fatalf("RunE() error = %v", err)
+		}
+		if err := bcrypt.CompareHashAndPassword(bytes.TrimSpace(out.Bytes()), []byte("secret")); err != nil {
+			t.Errorf("hash %q does not match the password: %v", out.String(), err)
+		}
+		if !strings.Contains(stderr.String(), "Warning") {
+			t.Errorf("stderr = %q, want a warning", stderr.String())
+		}
+	})
+	t.Run("should reject an empty password", func(t *testing.T) {
+		credentialsPasswordCmd.SetIn(strings.NewReader(""))
+		if err := credentialsPasswordCmd.RunE(credentialsPasswordCmd, nil); !errors.Is(err, errEmptyPassword) {
+			t.Errorf("RunE() error = %v, want %v", err, errEmptyPassword)
+		}
+	})
+	t.Run("should print the token with its hash", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		credentialsTokenCmd.SetOut(out)
+		if err := credentialsTokenCmd.RunE(credentialsTokenCmd, nil); err != nil {
+			t.Fatalf("RunE() error = %v", err)
+		}
+		var token, hash string
+		if _, err := fmt.Sscanf(out.String(), "token: %s\nhash:  %s\n", &token, &hash); err != nil {
+			t.Fatalf("Sscanf() error = %v", err)
+		}
+		sum := sha256.Sum256([]byte(token))
+		if hash != hex.EncodeToString(sum[:]) {
+			t.Errorf("hash = %s, want the SHA-256 of the token", hash)
+		}
+	})
+}
+
+func newFakeDaemon(t *testing.T) *httptest.Server {
+	t.Helper()
+	run := `{"id":"r1","trigger":"api","status":"%s","queued":"2025-01-01T12:00:00Z",` +
+		`"started":"2025-01-01T12:00:00Z","finished":"2025-01-01T12:00:02Z","features":["dns.rewrites"]}`
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"syncRunning":false,` +
+			`"origin":{"host":"origin:3000","url":"http://origin:3000","status":"success","protection_enabled":true},` +
+			`"replicas":[{"host":"replica:3000","url":"http://replica:3000","status":"danger","error":"unreachable",` +
+			`"protection_enabled":null}]}`))
+	})
+	mux.HandleFunc("POST /api/v1/sync", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("features") != "dns.rewrites" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"unknown feature"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = fmt.Fprintf(w, run, "queued")
+	})
+	mux.HandleFunc("GET /api/v1/sync", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, "["+run+"]", "succeeded")
+	})
+	mux.HandleFunc("GET /api/v1/sync/r1", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, run, "succeeded")
+	})
+	mux.HandleFunc("GET /api/v1/logs", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("format") == "json" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"time":"2025-01-01T12:00:00Z","level":"info","logger":"sync","message":"history"}]`))
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("history\n"))
+	})
+	mux.HandleFunc("GET /api/v1/events", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(":keep-alive\n\n" +
+			"event:sync\ndata:{\"type\":\"run\"}\n\n" +
+			"event:log\ndata:{\"time\":\"2025-01-01T12:00:01Z\",\"level\":\"info\",\"logger\":\"client\",\"message\":\"other\"}\n\n" +
+			"event:log\ndata:{\"time\":\"2025-01-01T12:00:01Z\",\"level\":\"warn\",\"logger\":\"sync\",\"message\":\"streamed\"}\n\n"))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func Test_ClientCommands(t *testing.T) {
+	srv := newFakeDaemon(t)
+	execute := func(t *testing.T, cmd *cobra.Command, opts *clientOptions, output string, args ...string) string {
+		t.Helper()
+		*opts = clientOptions{url: srv.URL, token: "secret", output: output}
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetContext(context.Background())
+		if err := cmd.RunE(cmd, args); err != nil {
+			t.Fatalf("RunE() error = %v", err)
+		}
+		return out.String()
+	}
+
+	t.Run("should print the status", func(t *testing.T) {
+		out := execute(t, statusCmd, &statusOpts, outputText)
+		for _, want := range []string{"Sync running: no", "origin:3000   ok", "replica:3000  error   -           unreachable"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("status output %q does not contain %q", out, want)
+			}
+		}
+	})
+	t.Run("should print the status as json", func(t *testing.T) {
+		out := execute(t, statusCmd, &statusOpts, outputJSON)
+		var status api.SyncStatus
+		if err := json.Unmarshal([]byte(out), &status); err != nil || status.Origin.Host != "origin:3000" {
+			t.Errorf("status output %q is not the json status: %v", out, err)
+		}
+	})
+	t.Run("should trigger and wait for a sync", func(t *testing.T) {
+		triggerFeatures, triggerWait = []string{"dns.rewrites"}, true
+		defer func() { triggerFeatures, triggerWait = nil, false }()
+		out := execute(t, triggerCmd, &triggerOpts, outputText)
+		if out != "Sync run r1 succeeded after 2s\n" {
+			t.Errorf("trigger output = %q", out)
+		}
+	})
+	t.Run("should report api errors", func(t *testing.T) {
+		triggerFeatures = []string{"unknown"}
+		defer func() { triggerFeatures = nil }()
+		triggerOpts = clientOptions{url: srv.URL, output: outputText}
+		err := triggerCmd.RunE(triggerCmd, nil)
+		if !errors.Is(err, errAPIResponse) || !strings.Contains(err.Error(), "unknown feature") {
+			t.Errorf("RunE() error = %v, want the api error", err)
+		}
+	})
+	t.Run("should list the runs", func(t *testing.T) {
+		out := execute(t, runsCmd, &runsOpts, outputText)
+		if !strings.Contains(out, "r1  api      succeeded") || !strings.Contains(out, "features=dns.rewrites") {
+			t.Errorf("runs output = %q", out)
+		}
+	})
+	t.Run("should follow the logs", func(t *testing.T) {
+		logsLogger, logsFollow = "sync", true
+		defer func() { logsLogger, logsFollow = "", false }()
+		out := execute(t, logsCmd, &logsOpts, outputText)
+		if !strings.HasPrefix(out, "history\n") || !strings.Contains(out, "WARN\tsync\tstreamed") ||
+			strings.Contains(out, "other") {
+			t.Errorf("logs output = %q", out)
+		}
+	})
+	t.Run("should follow the logs as json lines", func(t *testing.T) {
+		logsFollow = true
+		defer func() { logsFollow = false }()
+		out := execute(t, logsCmd, &logsOpts, outputJSON)
+		if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 3 {
+			t.Errorf("logs output = %q, want 3 lines", out)
+		}
+	})
+	t.Run("should reject invalid output formats", func(t *testing.T) {
+		statusOpts = clientOptions{url: srv.URL, output: "yaml"}
+		if err := statusCmd.RunE(statusCmd, nil); !errors.Is(err, errInvalidOutput) {
+			t.Errorf("RunE() error = %v, want %v", err, errInvalidOutput)
+		}
+	})
+}
+
+func Test_apiURL(t *testing.T) {
+	t.Run("should use the configured port", func(t *testing.T) {
+		url, err := apiURL(types.API{Port: 8080})
+		if err != nil || url != "http://localhost:8080" {
+			t.Errorf("apiURL() = %q, %v, want http://localhost:8080", url, err)
+		}
+	})
+	t.Run("should fail if the api is disabled", func(t *testing.T) {
+		if _, err := apiURL(types.API{}); !errors.Is(err, errAPIDisabled) {
+			t.Errorf("apiURL() error = %v, want %v", err, errAPIDisabled)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/term"
+)
+
+var (
+	errEmptyPassword      = errors.New("the password must not be empty")
+	errPasswordsDontMatch = errors.New("the passwords don't match")
+)
+
+// credentialsCmd represents the credentials command.
+var credentialsCmd = &cobra.Command{
+	Use:   "credentials",
+	Short: "Create hashed credentials for the API users and tokens",
+}
+
+// credentialsPasswordCmd represents the credentials password command.
+var credentialsPasswordCmd = &cobra.Command{
+	Use:   "password",
+	Short: "Print the bcrypt hash of a password to be used for an API user",
+	Long: `Reads the password from a prompt without echo, or the first line of stdin if it is no terminal.
+The password can also be passed as argument, which is discouraged as it shows up in the shell history
+and the process list.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var password string
+		if len(args) == 1 {
+			_, _ = fmt.Fprintln(cmd.ErrOrStderr(),
+				"Warning: the password argument shows up in the shell history and the process list, omit it to be prompted")
+			password = args[0]
+		} else {
+			var err error
+			if password, err = readPassword(cmd.InOrStdin(), cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+		}
+		if password == "" {
+			return errEmptyPassword
+		}
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), string(hash))
+		return err
+	},
+}
+
+// credentialsTokenCmd represents the credentials token command.
+var credentialsTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Generate a random API token and print it with its SHA-256 hash",
+	Long: `Generates a random API token. The token is used by the API clients as bearer token,
+the hash is configured as API token hash.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		token := rand.Text()
+		sum := sha256.Sum256([]byte(token))
+		_, err := fmt.Fprintf(cmd.OutOrStdout(), "token: %s\nhash:  %s\n", token, hex.EncodeToString(sum[:]))
+		return err
+	},
+}
+
+// readPassword prompts for the password twice if in is a terminal, otherwise it reads the first line of in.
+func readPassword(in io.Reader, prompt io.Writer) (string, error) {
+	f, ok := in.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	var passwords [2]string
+	for i, label := range []string{"Password: ", "Repeat password: "} {
+		_, _ = fmt.Fprint(prompt, label)
+		b, err := term.ReadPassword(int(f.Fd()))
+		_, _ = fmt.Fprintln(prompt)
+		if err != nil {
+			return "", err
+		}
+		passwords[i] = string(b)
+	}
+	if passwords[0] != passwords[1] {
+		return "", errPasswordsDontMatch
+	}
+	return passwords[0], nil
+}
+
+func init() {
+	credentialsCmd.AddCommand(credentialsPasswordCmd, credentialsTokenCmd)
+	rootCmd.AddCommand(credentialsCmd)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"reflect"
+	"slices"
 
 	"github.com/bakito/adguardhome-sync/internal/types"
 	"github.com/bakito/docs-gen/docs"
@@ -24,7 +25,8 @@ func main() {
 }
 
 func yamlPrefixCustomizer(yamlTag string, prefix *yaml.Prefix) {
-	if yamlTag == "replicas" && prefix.FieldType.Kind() == reflect.Slice {
+	if slices.Contains([]string{"replicas", "users", "tokens", "hooks"}, yamlTag) &&
+		prefix.FieldType.Kind() == reflect.Slice && prefix.FieldType.Elem().Kind() == reflect.Struct {
 		prefix.FieldType = prefix.FieldType.Elem()
 		prefix.First += "- "
 		prefix.Other += "  "
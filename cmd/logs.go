@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/bakito/adguardhome-sync/api"
+	"github.com/bakito/adguardhome-sync/internal/log"
+)
+
+const (
+	flagLogsLevel  = "level"
+	flagLogsLogger = "logger"
+	flagLogsHost   = "host"
+	flagLogsSince  = "since"
+	flagLogsFollow = "follow"
+
+	// eventLog the name of the server-sent events containing log entries.
+	eventLog = "log"
+)
+
+var (
+	logsOpts   clientOptions
+	logsLevel  string
+	logsLogger string
+	logsHost   string
+	logsSince  string
+	logsFollow bool
+)
+
+// logsCmd represents the logs command.
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Print the logs of a running sync daemon",
+	Long: `Prints the logs of a running sync daemon, oldest first.
+With --follow, new log entries are streamed until the command is interrupted.
+The json output of followed logs prints one entry per line.`,
+	SilenceUsage: true,
+	Args:         cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		filter, err := logsFilter()
+		if err != nil {
+			return err
+		}
+		cl, err := logsOpts.newClient()
+		if err != nil {
+			return err
+		}
+		params := &api.GetLogsParams{
+			Logger: optional(logsLogger),
+			Host:   optional(logsHost),
+			Since:  optional(logsSince),
+			Format: new(api.Text),
+		}
+		if logsLevel != "" {
+			params.Level = new(api.LogLevel(logsLevel))
+		}
+		if logsOpts.output == outputJSON {
+			params.Format = new(api.Json)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		resp, err := cl.GetLogsWithResponse(ctx, params)
+		if err != nil {
+			return err
+		}
+		if err := checkResponse(resp, resp.Body, http.StatusOK); err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		switch {
+		case logsOpts.output == outputText:
+			_, err = out.Write(resp.Body)
+		case logsFollow:
+			err = printJSONLines(out, *resp.JSON200)
+		default:
+			err = printJSON(out, resp.JSON200)
+		}
+		if err != nil || !logsFollow {
+			return err
+		}
+		return followLogs(ctx, cl, params.Level, filter, out, logsOpts.output)
+	},
+}
+
+// logsFilter creates the filter applied to the streamed entries, which are only filtered by level by the API.
+func logsFilter() (log.Filter, error) {
+	filter := log.Filter{Logger: logsLogger, Host: logsHost}
+	if logsLevel != "" {
+		level, err := zapcore.ParseLevel(logsLevel)
+		if err != nil {
+			return filter, err
+		}
+		filter.Level = level
+	}
+	return filter, nil
+}
+
+// followLogs prints the streamed log entries matching the filter until the context is done.
+func followLogs(
+	ctx context.Context,
+	cl api.ClientInterface,
+	level *api.LogLevel,
+	filter log.Filter,
+	out io.Writer,
+	output string,
+) error {
+	resp, err := cl.StreamEvents(ctx, &api.StreamEventsParams{Level: level})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return checkResponse(statusCode(resp.StatusCode), body, http.StatusOK)
+	}
+
+	err = readEvents(resp.Body, func(event, data string) error {
+		if event != eventLog {
+			return nil
+		}
+		var e log.Entry
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			return err
+		}
+		if !filter.Matches(e) {
+			return nil
+		}
+		if output == outputJSON {
+			_, err := fmt.Fprintln(out, data)
+			return err
+		}
+		_, err := io.WriteString(out, e.String())
+		return err
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// readEvents reads server-sent events and calls handle for each event.
+func readEvents(r io.Reader, handle func(event, data string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var event string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				if err := handle(event, strings.Join(data, "\n")); err != nil {
+					return err
+				}
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, ":"):
+			// comment, e.g. keep alive
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				event = value
+			case "data":
+				data = append(data, value)
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// printJSONLines prints one entry per line.
+func printJSONLines(out io.Writer, entries []api.LogEntry) error {
+	enc := json.NewEncoder(out)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statusCode adapts a plain status code to checkResponse.
+type statusCode int
+
+func (s statusCode) StatusCode() int {
+	return int(s)
+}
+
+// optional returns nil for empty values.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func init() {
+	addClientFlags(logsCmd, &logsOpts)
+	logsCmd.Flags().StringVar(&logsLevel, flagLogsLevel, "", "Minimum level of the entries (debug, info, warn, error)")
+	logsCmd.Flags().StringVar(&logsLogger, flagLogsLogger, "", "Name of the logger (e.g. 'sync' or 'client')")
+	logsCmd.Flags().StringVar(&logsHost, flagLogsHost, "", "Host of the origin or replica the entries refer to")
+	logsCmd.Flags().StringVar(&logsSince, flagLogsSince, "", "RFC3339 time or duration (e.g. 15m) of the oldest entries")
+	logsCmd.Flags().BoolVarP(&logsFollow, flagLogsFollow, "f", false, "Follow the logs")
+	rootCmd.AddCommand(logsCmd)
+}
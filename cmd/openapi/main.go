@@ -1,17 +1,25 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 
 	"gopkg.in/yaml.v3"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
+const (
+	schemaDir    = "internal/client/model/schema"
+	schemaSuffix = ".yaml.gz"
+)
+
 func main() {
 	version := "master"
 	fileName := "schema-master.yaml"
@@ -38,11 +46,22 @@ func main() {
 		log.Fatalln(err)
 	}
 	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("Error downloading schema version %s: %s", version, resp.Status)
+	}
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
 		log.Println(err)
 		return
 	}
+
+	if len(os.Args) > 1 {
+		// the unpatched document is embedded to validate the responses of instances of this version
+		log.Printf("Writing schema document %s/%s%s", schemaDir, version, schemaSuffix)
+		if err := saveSchema(schemaDir, version, data); err != nil {
+			log.Fatalln(err)
+		}
+	}
 	schema := make(map[string]any)
 	err = yaml.Unmarshal(data, &schema)
 	if err != nil {
@@ -86,6 +105,20 @@ func main() {
 	}
 }
 
+// saveSchema writes the gzip compressed openapi document '<version>.yaml.gz' into the directory.
+func saveSchema(dir, version string, data []byte) error {
+	var b bytes.Buffer
+	zw := gzip.NewWriter(&b)
+	zw.Name = version + ".yaml"
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, version+schemaSuffix), b.Bytes(), 0o600)
+}
+
 func correctEntries(map[string]any) {
 }
 
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -54,3 +58,23 @@ func Test_correctEntries(_ *testing.T) {
 	// Currently empty, but let's test that it doesn't panic
 	correctEntries(make(map[string]any))
 }
+
+func Test_saveSchema(t *testing.T) {
+	dir := t.TempDir()
+	if err := saveSchema(dir, "v0.107.78", []byte("openapi: 3.0.3\n")); err != nil {
+		t.Fatalf("saveSchema() error = %v", err)
+	}
+	f, err := os.Open(filepath.Join(dir, "v0.107.78.yaml.gz"))
+	if err != nil {
+		t.Fatalf("Open error = %v", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip.NewReader error = %v", err)
+	}
+	b, _ := io.ReadAll(zr)
+	if string(b) != "openapi: 3.0.3\n" {
+		t.Errorf("document = %q, want the downloaded document", string(b))
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bakito/adguardhome-sync/internal/log"
+	"github.com/bakito/adguardhome-sync/internal/recording"
+)
+
+const (
+	flagReplayFile = "file"
+	flagReplayPort = "port"
+	flagReplayHost = "host"
+)
+
+// replayCmd represents the replay command.
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Serve a recorded AdGuardHome HTTP traffic file as a fake instance",
+	Long: `Serves the responses of a file recorded with 'recordFile' back as a fake AdGuardHome instance.
+This allows to reproduce authentication or version specific issues without access to the original instance.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		logger = log.GetLogger("replay")
+		file, err := cmd.Flags().GetString(flagReplayFile)
+		if err != nil {
+			return err
+		}
+		port, err := cmd.Flags().GetInt(flagReplayPort)
+		if err != nil {
+			return err
+		}
+
+		host, err := cmd.Flags().GetString(flagReplayHost)
+		if err != nil {
+			return err
+		}
+
+		entries, err := recording.Load(file)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+		if host != "" {
+			entries = recording.ByHost(entries, host)
+		}
+
+		logger.With("file", file, "port", port, "entries", len(entries)).Info("Replaying recorded traffic")
+		server := &http.Server{
+			Addr:              fmt.Sprintf(":%d", port),
+			Handler:           recording.NewReplay(entries),
+			ReadHeaderTimeout: 1 * time.Second,
+		}
+		return server.ListenAndServe()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+	replayCmd.Flags().String(flagReplayFile, "", "The recorded traffic file")
+	replayCmd.Flags().Int(flagReplayPort, 3000, "The port to serve the replayed instance on")
+	replayCmd.Flags().String(flagReplayHost, "", "Only replay the entries of this host, e.g. '192.168.1.2:3000'")
+	cobra.CheckErr(replayCmd.MarkFlagRequired(flagReplayFile))
+}
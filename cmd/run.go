@@ -35,7 +35,21 @@ var doCmd = &cobra.Command{
 			return nil
 		}
 
-		return sync.Sync(cfg.Get())
+		features, err := cmd.Flags().GetStringSlice(config.FlagSyncFeatures)
+		if err != nil {
+			return err
+		}
+		replicas, err := cmd.Flags().GetStringSlice(config.FlagSyncReplicas)
+		if err != nil {
+			return err
+		}
+		selected, err := cfg.Get().Select(features, replicas)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return sync.Sync(selected)
 	},
 }
 
@@ -47,6 +61,10 @@ func init() {
 		"Can be used to debug the config E.g: when having authentication issues.")
 	doCmd.PersistentFlags().Bool(config.FlagContinueOnError, false, "If enabled, the synchronization task "+
 		"will not fail on single errors, but will log the errors and continue.")
+	doCmd.PersistentFlags().StringSlice(config.FlagSyncFeatures, nil, "Only sync the selected features "+
+		"(e.g. 'dns.rewrites' or 'filters'); all configured features if empty.")
+	doCmd.PersistentFlags().StringSlice(config.FlagSyncReplicas, nil, "Only sync to the selected replicas "+
+		"by host or URL; all replicas if empty.")
 
 	doCmd.PersistentFlags().
 		Int(config.FlagAPIPort, 8080, "Sync API Port, the API endpoint will be started to enable remote triggering; if 0 port API is disabled.")
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bakito/adguardhome-sync/api"
+)
+
+var runsOpts clientOptions
+
+// runsCmd represents the runs command.
+var runsCmd = &cobra.Command{
+	Use:          "runs [id]",
+	Short:        "List the sync runs of a running sync daemon, or show a single run",
+	SilenceUsage: true,
+	Args:         cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cl, err := runsOpts.newClient()
+		if err != nil {
+			return err
+		}
+
+		var runs []api.SyncRun
+		if len(args) == 1 {
+			resp, err := cl.GetSyncRunWithResponse(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			if err := checkResponse(resp, resp.Body, http.StatusOK); err != nil {
+				return err
+			}
+			if runsOpts.output == outputJSON {
+				return printJSON(cmd.OutOrStdout(), resp.JSON200)
+			}
+			runs = []api.SyncRun{*resp.JSON200}
+		} else {
+			resp, err := cl.ListSyncRunsWithResponse(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if err := checkResponse(resp, resp.Body, http.StatusOK); err != nil {
+				return err
+			}
+			if runsOpts.output == outputJSON {
+				return printJSON(cmd.OutOrStdout(), resp.JSON200)
+			}
+			runs = *resp.JSON200
+		}
+		return printRuns(cmd.OutOrStdout(), runs)
+	},
+}
+
+func printRuns(out io.Writer, runs []api.SyncRun) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tTRIGGER\tSTATUS\tQUEUED\tDURATION\tSELECTION\tERROR")
+	for _, run := range runs {
+		duration := "-"
+		if run.Started != nil && run.Finished != nil {
+			duration = run.Finished.Sub(*run.Started).Round(time.Millisecond).String()
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", run.Id, run.Trigger, run.Status,
+			run.Queued.Local().Format(time.DateTime), duration, selection(run), deref(run.Error))
+	}
+	return tw.Flush()
+}
+
+// selection returns the selected features and replicas of a run.
+func selection(run api.SyncRun) string {
+	var sel []string
+	if run.Features != nil && len(*run.Features) > 0 {
+		sel = append(sel, "features="+strings.Join(*run.Features, ","))
+	}
+	if run.Replicas != nil && len(*run.Replicas) > 0 {
+		sel = append(sel, "replicas="+strings.Join(*run.Replicas, ","))
+	}
+	if len(sel) == 0 {
+		return "all"
+	}
+	return strings.Join(sel, " ")
+}
+
+func init() {
+	addClientFlags(runsCmd, &runsOpts)
+	rootCmd.AddCommand(runsCmd)
+}
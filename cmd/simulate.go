@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+	"github.com/bakito/adguardhome-sync/internal/fakeagh"
+	"github.com/bakito/adguardhome-sync/internal/log"
+)
+
+const (
+	flagSimulatePort        = "port"
+	flagSimulateReplicas    = "replicas"
+	flagSimulateUsername    = "username"
+	flagSimulatePassword    = "password"
+	flagSimulateSetupNeeded = "setup-needed"
+	flagSimulateTraffic     = "traffic"
+)
+
+var (
+	simulatedDomains = []string{"example.com", "adguard.com", "github.com", "ads.example.com", "tracker.example.net"}
+	simulatedClients = []string{"192.168.1.100", "192.168.1.101", "192.168.1.102"}
+)
+
+// simulateCmd represents the simulate command.
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Start in memory AdGuardHome instances to try out a sync",
+	Long: `Starts an origin and the given number of replica AdGuardHome instances with in memory state.
+The origin is served on the given port and is populated with sample data, the replicas on the following ports.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		logger = log.GetLogger("simulate")
+		port, err := cmd.Flags().GetInt(flagSimulatePort)
+		if err != nil {
+			return err
+		}
+		replicas, err := cmd.Flags().GetInt(flagSimulateReplicas)
+		if err != nil {
+			return err
+		}
+		username, err := cmd.Flags().GetString(flagSimulateUsername)
+		if err != nil {
+			return err
+		}
+		password, err := cmd.Flags().GetString(flagSimulatePassword)
+		if err != nil {
+			return err
+		}
+		setupNeeded, err := cmd.Flags().GetBool(flagSimulateSetupNeeded)
+		if err != nil {
+			return err
+		}
+		traffic, err := cmd.Flags().GetDuration(flagSimulateTraffic)
+		if err != nil {
+			return err
+		}
+
+		origin := fakeagh.New(fakeagh.WithState(fakeagh.SampleState()), fakeagh.WithCredentials(username, password))
+		if traffic > 0 {
+			go simulateTraffic(origin, traffic)
+		}
+
+		errs := make(chan error, replicas+1)
+		go serveSimulated("origin", port, origin, errs)
+		for i := 1; i <= replicas; i++ {
+			opts := []fakeagh.Option{fakeagh.WithCredentials(username, password)}
+			if setupNeeded {
+				opts = append(opts, fakeagh.WithSetupNeeded())
+			}
+			go serveSimulated(fmt.Sprintf("replica%d", i), port+i, fakeagh.New(opts...), errs)
+		}
+		return <-errs
+	},
+}
+
+func serveSimulated(name string, port int, srv *fakeagh.Server, errs chan<- error) {
+	logger.With("name", name, "url", fmt.Sprintf("http://localhost:%d", port)).Info("Starting simulated instance")
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           srv,
+		ReadHeaderTimeout: 1 * time.Second,
+	}
+	errs <- server.ListenAndServe()
+}
+
+func simulateTraffic(srv *fakeagh.Server, interval time.Duration) {
+	for range time.Tick(interval) {
+		q := fakeagh.Query{
+			Domain:   simulatedDomains[rand.IntN(len(simulatedDomains))],
+			Client:   simulatedClients[rand.IntN(len(simulatedClients))],
+			Upstream: "https://dns10.quad9.net:443/dns-query",
+			Elapsed:  time.Duration(rand.IntN(50)) * time.Millisecond,
+		}
+		if rand.IntN(4) == 0 {
+			q.Reason = model.FilteredBlackList
+		}
+		srv.RecordQuery(q)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(simulateCmd)
+	simulateCmd.Flags().Int(flagSimulatePort, 3000, "The port of the origin instance, replicas use the following ports")
+	simulateCmd.Flags().Int(flagSimulateReplicas, 1, "The number of replica instances")
+	simulateCmd.Flags().String(flagSimulateUsername, "admin", "The username of the instances")
+	simulateCmd.Flags().String(flagSimulatePassword, "password", "The password of the instances")
+	simulateCmd.Flags().Bool(flagSimulateSetupNeeded, false, "Replicas have to be set up first (use with autoSetup)")
+	simulateCmd.Flags().Duration(flagSimulateTraffic, 0, "Interval of simulated DNS queries on the origin (0 = disabled)")
+}
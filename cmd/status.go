@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bakito/adguardhome-sync/api"
+)
+
+var statusOpts clientOptions
+
+// statusCmd represents the status command.
+var statusCmd = &cobra.Command{
+	Use:          "status",
+	Short:        "Show the status of the origin and replicas of a running sync daemon",
+	SilenceUsage: true,
+	Args:         cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		cl, err := statusOpts.newClient()
+		if err != nil {
+			return err
+		}
+		resp, err := cl.GetStatusWithResponse(cmd.Context())
+		if err != nil {
+			return err
+		}
+		if err := checkResponse(resp, resp.Body, http.StatusOK); err != nil {
+			return err
+		}
+		if statusOpts.output == outputJSON {
+			return printJSON(cmd.OutOrStdout(), resp.JSON200)
+		}
+		return printStatus(cmd.OutOrStdout(), resp.JSON200)
+	},
+}
+
+// instanceStates the human-readable instance states of the dashboard colors.
+var instanceStates = map[api.InstanceStatusStatus]string{
+	api.InstanceStatusStatusSuccess: "ok",
+	api.InstanceStatusStatusInfo:    "syncing",
+	api.InstanceStatusStatusWarning: "warning",
+	api.InstanceStatusStatusDanger:  "error",
+}
+
+func printStatus(out io.Writer, status *api.SyncStatus) error {
+	running := "no"
+	if status.SyncRunning {
+		running = "yes"
+	}
+	if _, err := fmt.Fprintf(out, "Sync running: %s\n\n", running); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "INSTANCE\tHOST\tSTATUS\tPROTECTION\tERROR")
+	printInstance(tw, "origin", status.Origin)
+	if status.Replicas != nil {
+		for _, r := range *status.Replicas {
+			printInstance(tw, "replica", r)
+		}
+	}
+	return tw.Flush()
+}
+
+func printInstance(out io.Writer, kind string, st api.InstanceStatus) {
+	protection := "-"
+	if st.ProtectionEnabled != nil {
+		protection = "disabled"
+		if *st.ProtectionEnabled {
+			protection = "enabled"
+		}
+	}
+	state, ok := instanceStates[st.Status]
+	if !ok {
+		state = string(st.Status)
+	}
+	_, _ = fmt.Fprintf(out, "%s\t%s\t%s\t%s\t%s\n", kind, st.Host, state, protection, deref(st.Error))
+}
+
+func init() {
+	addClientFlags(statusCmd, &statusOpts)
+	rootCmd.AddCommand(statusCmd)
+}
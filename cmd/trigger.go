@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bakito/adguardhome-sync/api"
+	"github.com/bakito/adguardhome-sync/internal/config"
+)
+
+const (
+	flagTriggerWait = "wait"
+
+	// runPollInterval the interval to poll the status of a sync run while waiting for it.
+	runPollInterval = time.Second
+)
+
+var errSyncRunFailed = errors.New("sync run failed")
+
+var (
+	triggerOpts     clientOptions
+	triggerFeatures []string
+	triggerReplicas []string
+	triggerWait     bool
+)
+
+// triggerCmd represents the trigger command.
+var triggerCmd = &cobra.Command{
+	Use:   "trigger",
+	Short: "Trigger a sync on a running sync daemon",
+	Long: `Triggers a sync on a running sync daemon and prints the queued sync run.
+With --wait, the command waits for the run to finish and fails if the run failed.`,
+	SilenceUsage: true,
+	Args:         cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		cl, err := triggerOpts.newClient()
+		if err != nil {
+			return err
+		}
+		params := &api.TriggerSyncParams{}
+		if len(triggerFeatures) > 0 {
+			params.Features = &triggerFeatures
+		}
+		if len(triggerReplicas) > 0 {
+			params.Replicas = &triggerReplicas
+		}
+		resp, err := cl.TriggerSyncWithResponse(cmd.Context(), params)
+		if err != nil {
+			return err
+		}
+		if err := checkResponse(resp, resp.Body, http.StatusAccepted); err != nil {
+			return err
+		}
+
+		run := resp.JSON202
+		if triggerWait {
+			if run, err = waitForRun(cmd.Context(), cl, run.Id, runPollInterval); err != nil {
+				return err
+			}
+		}
+
+		if triggerOpts.output == outputJSON {
+			err = printJSON(cmd.OutOrStdout(), run)
+		} else {
+			err = printRun(cmd.OutOrStdout(), run)
+		}
+		if err == nil && run.Status == api.Failed {
+			err = fmt.Errorf("%w: %s", errSyncRunFailed, deref(run.Error))
+		}
+		return err
+	},
+}
+
+// waitForRun polls the sync run until it is finished.
+func waitForRun(ctx context.Context, cl api.ClientWithResponsesInterface, id string, interval time.Duration) (*api.SyncRun, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		resp, err := cl.GetSyncRunWithResponse(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkResponse(resp, resp.Body, http.StatusOK); err != nil {
+			return nil, err
+		}
+		if resp.JSON200.Status == api.Succeeded || resp.JSON200.Status == api.Failed {
+			return resp.JSON200, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func printRun(out io.Writer, run *api.SyncRun) error {
+	msg := fmt.Sprintf("Sync run %s %s", run.Id, run.Status)
+	if run.Started != nil && run.Finished != nil {
+		msg += fmt.Sprintf(" after %s", run.Finished.Sub(*run.Started).Round(time.Millisecond))
+	}
+	if run.Error != nil {
+		msg += ": " + *run.Error
+	}
+	_, err := fmt.Fprintln(out, msg)
+	return err
+}
+
+func init() {
+	addClientFlags(triggerCmd, &triggerOpts)
+	triggerCmd.Flags().StringSliceVar(&triggerFeatures, config.FlagSyncFeatures, nil, "Only sync the selected features "+
+		"(e.g. 'dns.rewrites' or 'filters'); all configured features if empty.")
+	triggerCmd.Flags().StringSliceVar(&triggerReplicas, config.FlagSyncReplicas, nil, "Only sync to the selected replicas "+
+		"by host or URL; all replicas if empty.")
+	triggerCmd.Flags().BoolVar(&triggerWait, flagTriggerWait, false, "Wait for the sync run to finish")
+	rootCmd.AddCommand(triggerCmd)
+}
@@ -0,0 +1,327 @@
+// Package archive writes the query log entries of the instances to rotating local files.
+package archive
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bakito/adguardhome-sync/internal/log"
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+const (
+	filePrefix  = "querylog-"
+	cursorsFile = "cursors.json"
+	gzipSuffix  = ".gz"
+)
+
+var (
+	l = log.GetLogger("archive")
+
+	csvHeader = []string{
+		"time", "instance", "client", "client_name", "domain", "type", "status", "reason",
+		"rules", "upstream", "elapsed_ms", "cached", "answers",
+	}
+)
+
+// Entry a query log entry tagged with the instance that answered the query.
+type Entry struct {
+	Instance   string    `json:"instance"`
+	Time       time.Time `json:"time"`
+	Client     string    `json:"client,omitempty"`
+	ClientName string    `json:"clientName,omitempty"`
+	Domain     string    `json:"domain"`
+	Type       string    `json:"type,omitempty"`
+	Status     string    `json:"status,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	Rules      []string  `json:"rules,omitempty"`
+	Upstream   string    `json:"upstream,omitempty"`
+	ElapsedMs  float64   `json:"elapsedMs"`
+	Cached     bool      `json:"cached"`
+	Answers    []string  `json:"answers,omitempty"`
+}
+
+func (e Entry) csvRecord() []string {
+	return []string{
+		e.Time.Format(time.RFC3339Nano), e.Instance, e.Client, e.ClientName, e.Domain, e.Type, e.Status, e.Reason,
+		strings.Join(e.Rules, "; "), e.Upstream, strconv.FormatFloat(e.ElapsedMs, 'f', -1, 64),
+		strconv.FormatBool(e.Cached), strings.Join(e.Answers, "; "),
+	}
+}
+
+// Archive appends the query log entries to the files 'querylog-<date>.<format>' of the day of their query
+// in the archive directory. A file reaching the max size is continued in 'querylog-<date>.<n>.<format>'.
+// The files of the past days are compressed and removed once their day is older than the retention, if configured.
+// The time of the newest archived entry of each instance is persisted with the entries, to continue after a restart.
+// An Archive is not safe for concurrent use.
+type Archive struct {
+	dir       string
+	format    string
+	maxSize   int64
+	compress  bool
+	retention time.Duration
+	cursors   map[string]time.Time
+
+	file *os.File
+	day  string
+	size int64
+}
+
+// New creates the archive directory and loads the persisted cursors.
+func New(cfg types.QueryLogArchive) (*Archive, error) {
+	a := &Archive{
+		dir:       cfg.Dir,
+		format:    cfg.Format,
+		maxSize:   int64(cfg.MaxSize) << 20,
+		compress:  cfg.Compress,
+		retention: cfg.Retention,
+		cursors:   make(map[string]time.Time),
+	}
+	if a.format == "" {
+		a.format = types.QueryLogArchiveFormatJSONL
+	}
+	if err := os.MkdirAll(a.dir, 0o750); err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(filepath.Join(a.dir, cursorsFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return a, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &a.cursors); err != nil {
+		return nil, fmt.Errorf("invalid query log archive cursors %q: %w", filepath.Join(a.dir, cursorsFile), err)
+	}
+	return a, nil
+}
+
+// Cursor returns the time of the newest archived entry of the instance, false if none was archived yet.
+func (a *Archive) Cursor(instance string) (time.Time, bool) {
+	t, ok := a.cursors[instance]
+	return t, ok
+}
+
+// Write appends the entries, oldest first, and persists the cursor of the instance. Entries older than the
+// retention are skipped. If writing the cursor fails after the entries were written, the entries are archived
+// again by the next run.
+func (a *Archive) Write(instance string, entries []Entry, cursor time.Time, now time.Time) error {
+	for _, e := range entries {
+		t := e.Time
+		if t.IsZero() {
+			t = now
+		}
+		if a.expired(t, now) {
+			continue
+		}
+		if err := a.rotate(t); err != nil {
+			return err
+		}
+		n, err := a.writeEntry(e)
+		a.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	a.cursors[instance] = cursor
+	if err := a.saveCursors(); err != nil {
+		return err
+	}
+	a.cleanup(now)
+	return nil
+}
+
+// Close closes the current file.
+func (a *Archive) Close() error {
+	if a.file == nil {
+		return nil
+	}
+	err := a.file.Close()
+	a.file = nil
+	return err
+}
+
+func (a *Archive) writeEntry(e Entry) (int, error) {
+	if a.format == types.QueryLogArchiveFormatCSV {
+		var b strings.Builder
+		cw := csv.NewWriter(&b)
+		if a.size == 0 {
+			_ = cw.Write(csvHeader)
+		}
+		_ = cw.Write(e.csvRecord())
+		cw.Flush()
+		return io.WriteString(a.file, b.String())
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return 0, err
+	}
+	return a.file.Write(append(b, '\n'))
+}
+
+// rotate opens the file of the day of the query time if no file is open, the day changed or the current file
+// reached the max size.
+func (a *Archive) rotate(t time.Time) error {
+	day := t.UTC().Format(time.DateOnly)
+	if a.file != nil && a.day == day && (a.maxSize == 0 || a.size < a.maxSize) {
+		return nil
+	}
+	if err := a.Close(); err != nil {
+		return err
+	}
+	// continue the last file of the day that is not full, e.g. after a restart
+	for i := 0; ; i++ {
+		name := a.fileName(day, i)
+		if _, err := os.Stat(name + gzipSuffix); err == nil {
+			continue
+		}
+		fi, err := os.Stat(name)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if err == nil && a.maxSize > 0 && fi.Size() >= a.maxSize {
+			continue
+		}
+		f, err := os.OpenFile(name, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+		if err != nil {
+			return err
+		}
+		a.file, a.day, a.size = f, day, 0
+		if fi != nil {
+			a.size = fi.Size()
+		}
+		return nil
+	}
+}
+
+func (a *Archive) fileName(day string, i int) string {
+	name := filePrefix + day
+	if i > 0 {
+		name += "." + strconv.Itoa(i)
+	}
+	return filepath.Join(a.dir, name+"."+a.format)
+}
+
+func (a *Archive) saveCursors() error {
+	b, err := json.Marshal(a.cursors)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(a.dir, cursorsFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(a.dir, cursorsFile))
+}
+
+// cleanup compresses the rotated files and removes the files of the days older than the retention. Errors are
+// only logged, the files are handled again by the next write.
+func (a *Archive) cleanup(now time.Time) {
+	des, err := os.ReadDir(a.dir)
+	if err != nil {
+		l.With("error", err, "dir", a.dir).Warn("Error reading the query log archive")
+		return
+	}
+	today := now.UTC().Format(time.DateOnly)
+	for _, de := range des {
+		if de.IsDir() || !strings.HasPrefix(de.Name(), filePrefix) ||
+			!strings.HasSuffix(strings.TrimSuffix(de.Name(), gzipSuffix), "."+a.format) {
+			continue
+		}
+		name := filepath.Join(a.dir, de.Name())
+		if a.file != nil && name == a.file.Name() {
+			continue
+		}
+		fi, err := de.Info()
+		if err != nil {
+			continue
+		}
+		day, err := fileDay(de.Name())
+		if err != nil {
+			continue
+		}
+		// a file of the day that is not full is continued by the next write
+		if strings.HasPrefix(de.Name(), filePrefix+today) && !strings.HasSuffix(name, gzipSuffix) &&
+			(a.maxSize == 0 || fi.Size() < a.maxSize) {
+			continue
+		}
+		switch {
+		case a.expired(day.AddDate(0, 0, 1), now):
+			err = os.Remove(name)
+		case a.compress && !strings.HasSuffix(name, gzipSuffix):
+			err = compressFile(name)
+		}
+		if err != nil {
+			l.With("error", err, "file", name).Warn("Error rotating the query log archive")
+		}
+	}
+}
+
+// fileDay returns the day of the archive file 'querylog-<date>[.<n>].<format>[.gz]'.
+func fileDay(name string) (time.Time, error) {
+	day, _, _ := strings.Cut(strings.TrimPrefix(name, filePrefix), ".")
+	return time.Parse(time.DateOnly, day)
+}
+
+// expired returns true if the time is older than the retention.
+func (a *Archive) expired(t, now time.Time) bool {
+	return a.retention > 0 && now.Sub(t) > a.retention
+}
+
+// compressFile replaces the file with its gzip compressed copy, keeping the modification time.
+func compressFile(name string) error {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	zw := gzip.NewWriter(tmp)
+	zw.Name = filepath.Base(name)
+	zw.ModTime = fi.ModTime()
+	if _, err := io.Copy(zw, in); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chtimes(tmp.Name(), fi.ModTime(), fi.ModTime()); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), name+gzipSuffix); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
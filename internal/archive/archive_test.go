@@ -0,0 +1,188 @@
+package archive
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+var day1 = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func entry(instance, domain string, t time.Time) Entry {
+	return Entry{Instance: instance, Time: t, Client: "10.0.0.1", Domain: domain, Rules: []string{"||a^", "||b^"}}
+}
+
+func files(t *testing.T, dir string) []string {
+	t.Helper()
+	des, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	var names []string
+	for _, de := range des {
+		names = append(names, de.Name())
+	}
+	return names
+}
+
+func TestArchive_JSONL(t *testing.T) {
+	dir := t.TempDir()
+	a, err := New(types.QueryLogArchive{Dir: dir})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, ok := a.Cursor("a"); ok {
+		t.Errorf("Cursor() ok = true, want no cursor of a new archive")
+	}
+	if err := a.Write("a", []Entry{entry("a", "one.org", day1), entry("a", "two.org", day1)}, day1, day1); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	_ = a.Close()
+
+	// a restart continues the file with the persisted cursor
+	a, err = New(types.QueryLogArchive{Dir: dir})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer a.Close()
+	if c, ok := a.Cursor("a"); !ok || !c.Equal(day1) {
+		t.Errorf("Cursor() = %v, %v, want the persisted cursor", c, ok)
+	}
+	if err := a.Write("b", []Entry{entry("b", "three.org", day1)}, day1, day1); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "querylog-2025-01-01.jsonl"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("lines = %q, want 3 entries", lines)
+	}
+	var e Entry
+	if err := json.Unmarshal([]byte(lines[2]), &e); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if e.Instance != "b" || e.Client != "10.0.0.1" || e.Domain != "three.org" || !e.Time.Equal(day1) {
+		t.Errorf("entry = %+v, want the entry of b", e)
+	}
+}
+
+func TestArchive_CSV(t *testing.T) {
+	dir := t.TempDir()
+	a, err := New(types.QueryLogArchive{Dir: dir, Format: types.QueryLogArchiveFormatCSV})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer a.Close()
+	for _, d := range []string{"one.org", "two.org"} {
+		if err := a.Write("a", []Entry{entry("a", d, day1)}, day1, day1); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	f, err := os.Open(filepath.Join(dir, "querylog-2025-01-01.csv"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(records) != 3 || !slices.Equal(records[0], csvHeader) {
+		t.Fatalf("records = %q, want the header and 2 entries", records)
+	}
+	if r := records[2]; r[0] != "2025-01-01T12:00:00Z" || r[1] != "a" || r[2] != "10.0.0.1" || r[4] != "two.org" ||
+		r[8] != "||a^; ||b^" {
+		t.Errorf("record = %q, want the fields of the entry", r)
+	}
+}
+
+func TestArchive_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	a, err := New(types.QueryLogArchive{Dir: dir, MaxSize: 1, Compress: true, Retention: 48 * time.Hour})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer a.Close()
+	// an entry of more than 1 MB fills a file
+	big := entry("a", strings.Repeat("x", 1<<20), day1)
+
+	t.Run("should continue a full file in a new one", func(t *testing.T) {
+		if err := a.Write("a", []Entry{big, entry("a", "one.org", day1)}, day1, day1); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		want := []string{"cursors.json", "querylog-2025-01-01.1.jsonl", "querylog-2025-01-01.jsonl.gz"}
+		if got := files(t, dir); !slices.Equal(got, want) {
+			t.Errorf("files = %v, want %v", got, want)
+		}
+	})
+	t.Run("should start a file per day and compress the rotated file", func(t *testing.T) {
+		day2 := day1.AddDate(0, 0, 1)
+		if err := a.Write("a", []Entry{entry("a", "two.org", day2)}, day2, day2); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		want := []string{
+			"cursors.json", "querylog-2025-01-01.1.jsonl.gz", "querylog-2025-01-01.jsonl.gz", "querylog-2025-01-02.jsonl",
+		}
+		if got := files(t, dir); !slices.Equal(got, want) {
+			t.Errorf("files = %v, want %v", got, want)
+		}
+
+		f, err := os.Open(filepath.Join(dir, "querylog-2025-01-01.1.jsonl.gz"))
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		defer f.Close()
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("NewReader() error = %v", err)
+		}
+		b, _ := io.ReadAll(zr)
+		if !strings.Contains(string(b), `"domain":"one.org"`) {
+			t.Errorf("content = %q, want the entry", b)
+		}
+	})
+	t.Run("should write the entries to the file of their day", func(t *testing.T) {
+		day2 := day1.AddDate(0, 0, 1)
+		if err := a.Write("b", []Entry{entry("b", "late.org", day1.Add(time.Hour))}, day1, day2); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		want := []string{
+			"cursors.json", "querylog-2025-01-01.1.jsonl.gz", "querylog-2025-01-01.2.jsonl", "querylog-2025-01-01.jsonl.gz",
+			"querylog-2025-01-02.jsonl",
+		}
+		if got := files(t, dir); !slices.Equal(got, want) {
+			t.Errorf("files = %v, want %v", got, want)
+		}
+	})
+	t.Run("should remove the files of the days older than the retention", func(t *testing.T) {
+		day4 := day1.AddDate(0, 0, 3)
+		entries := []Entry{entry("a", "expired.org", day1), entry("a", "four.org", day4)}
+		if err := a.Write("a", entries, day4, day4); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		want := []string{"cursors.json", "querylog-2025-01-02.jsonl.gz", "querylog-2025-01-04.jsonl"}
+		if got := files(t, dir); !slices.Equal(got, want) {
+			t.Errorf("files = %v, want %v", got, want)
+		}
+		b, err := os.ReadFile(filepath.Join(dir, "querylog-2025-01-04.jsonl"))
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		if strings.Contains(string(b), "expired.org") || !strings.Contains(string(b), "four.org") {
+			t.Errorf("content = %q, want only the entry within the retention", b)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+var (
+	transports   = make(map[bool]*http.Transport)
+	transportsMu sync.Mutex
+)
+
+// sharedTransport returns the keep-alive transport shared by all clients with the same TLS verification setting.
+func sharedTransport(insecureSkipVerify bool) *http.Transport {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	if t, ok := transports[insecureSkipVerify]; ok {
+		return t
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	// #nosec G402 has to be explicitly enabled
+	t.TLSClientConfig = &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+	transports[insecureSkipVerify] = t
+	return t
+}
+
+// Cache reuses the clients of instances. A client is recreated when the config of its instance changes, replacing
+// the cached one. As the instances are fixed by the config, the cache holds at most one client per instance.
+type Cache struct {
+	mu      sync.Mutex
+	clients map[string]cachedClient
+	create  func(instance types.AdGuardInstance, timeout time.Duration) (Client, error)
+}
+
+type cachedClient struct {
+	fingerprint string
+	client      Client
+}
+
+// NewCache creates a new client cache.
+func NewCache() *Cache {
+	return &Cache{
+		clients: make(map[string]cachedClient),
+		create:  New,
+	}
+}
+
+// Get returns the cached client of the instance or creates a new one.
+func (c *Cache) Get(instance types.AdGuardInstance, timeout time.Duration) (Client, error) {
+	fp, err := fingerprint(instance, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := instance.Key()
+	if cc, ok := c.clients[key]; ok && cc.fingerprint == fp {
+		return cc.client, nil
+	}
+
+	cl, err := c.create(instance, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := c.clients[key]; ok {
+		l.With("host", instance.Host).Debug("Instance config changed, recreating client")
+	}
+	c.clients[key] = cachedClient{fingerprint: fp, client: cl}
+	return cl, nil
+}
+
+func fingerprint(instance types.AdGuardInstance, timeout time.Duration) (string, error) {
+	b, err := json.Marshal(struct {
+		Instance types.AdGuardInstance
+		Timeout  time.Duration
+	}{instance, timeout})
+	return string(b), err
+}
@@ -3,19 +3,29 @@ package client
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/go-resty/resty/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+
+	"github.com/bakito/adguardhome-sync/internal/tracing"
+	"github.com/bakito/adguardhome-sync/internal/versions"
 )
 
-func (cl *client) doGet(req *resty.Request, url string) error {
+func (cl *client) doGet(req *resty.Request, url string) (err error) {
+	span := cl.startSpan(req, http.MethodGet, url, 0)
+	var resp *resty.Response
+	defer func() { endSpan(span, resp, err) }()
+
 	rl := cl.log.With("method", "GET", "path", url)
 	if cl.client.UserInfo != nil {
 		rl = rl.With("username", cl.client.UserInfo.Username)
 	}
 	req.ForceContentType("application/json")
 	rl.Debug("do get")
-	resp, err := req.Get(url)
+	resp, err = req.Get(url)
 	if err != nil {
 		l := rl
 		if resp != nil {
@@ -25,6 +35,12 @@ func (cl *client) doGet(req *resty.Request, url string) error {
 					return ErrSetupNeeded
 				}
 			}
+			if resp.StatusCode() == http.StatusOK {
+				// report decoding errors caused by schema changes as schema mismatch
+				if sErr := cl.validateSchema(url, req.Result, resp.Body()); sErr != nil {
+					return sErr
+				}
+			}
 			l = l.With("status", resp.StatusCode(), "body", string(resp.Body()), "error", err)
 		}
 
@@ -42,19 +58,23 @@ func (cl *client) doGet(req *resty.Request, url string) error {
 	if resp.StatusCode() != http.StatusOK {
 		return detailedError(resp, nil)
 	}
-	return nil
+	return cl.validateSchema(url, req.Result, resp.Body())
 }
 
-func (cl *client) doPost(req *resty.Request, url string) error {
+func (cl *client) doPost(req *resty.Request, url string) (err error) {
 	rl := cl.log.With("method", "POST", "path", url)
 	if cl.client.UserInfo != nil {
 		rl = rl.With("username", cl.client.UserInfo.Username)
 	}
 	// adguard home requires content type json to be set on every request
 	req.Header.Set("Content-Type", "application/json")
+	cl.adapt(req, url)
 	b, _ := json.Marshal(req.Body)
 	rl.With("body", string(b), "content-type", req.Header.Get("Content-Type")).Debug("do post")
-	resp, err := req.Post(url)
+	span := cl.startSpan(req, http.MethodPost, url, len(b))
+	var resp *resty.Response
+	defer func() { endSpan(span, resp, err) }()
+	resp, err = req.Post(url)
 	if err != nil {
 		rl.With("status", resp.StatusCode(), "body", string(resp.Body()), "error", err).Debug("error in do post")
 		return detailedError(resp, err)
@@ -73,16 +93,20 @@ func (cl *client) doPost(req *resty.Request, url string) error {
 	return nil
 }
 
-func (cl *client) doPut(req *resty.Request, url string) error {
+func (cl *client) doPut(req *resty.Request, url string) (err error) {
 	rl := cl.log.With("method", "PUT", "path", url)
 	if cl.client.UserInfo != nil {
 		rl = rl.With("username", cl.client.UserInfo.Username)
 	}
 	// adguard home requires content type json to be set on every request
 	req.Header.Set("Content-Type", "application/json")
+	cl.adapt(req, url)
 	b, _ := json.Marshal(req.Body)
 	rl.With("body", string(b), "content-type", req.Header.Get("Content-Type")).Debug("do put")
-	resp, err := req.Put(url)
+	span := cl.startSpan(req, http.MethodPut, url, len(b))
+	var resp *resty.Response
+	defer func() { endSpan(span, resp, err) }()
+	resp, err = req.Put(url)
 	if err != nil {
 		rl.With("status", resp.StatusCode(), "body", string(resp.Body()), "error", err).Debug("error in do put")
 		return detailedError(resp, err)
@@ -101,6 +125,66 @@ func (cl *client) doPut(req *resty.Request, url string) error {
 	return nil
 }
 
+// startSpan starts the span of a request to the instance and sends the request with the span context.
+// The span is named by the path, the query is recorded as attribute to keep the span names bounded.
+func (cl *client) startSpan(req *resty.Request, method, url string, requestSize int) trace.Span {
+	path, query := splitURL(url)
+	ctx, span := tracing.Tracer().Start(cl.context(), method+" "+path,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", method),
+			attribute.String("server.address", cl.host),
+			attribute.String("url.path", path),
+		),
+	)
+	if query != "" {
+		span.SetAttributes(attribute.String("url.query", query))
+	}
+	if requestSize > 0 {
+		span.SetAttributes(attribute.Int("http.request.body.size", requestSize))
+	}
+	req.SetContext(ctx)
+	return span
+}
+
+// splitURL splits the relative request url into the absolute path and the query.
+func splitURL(url string) (path, query string) {
+	path, query, _ = strings.Cut(url, "?")
+	return "/" + strings.TrimPrefix(path, "/"), query
+}
+
+// endSpan records the response status and size and ends the span.
+func endSpan(span trace.Span, resp *resty.Response, err error) {
+	if resp != nil && resp.RawResponse != nil {
+		span.SetAttributes(
+			attribute.Int("http.response.status_code", resp.StatusCode()),
+			attribute.Int("http.response.body.size", len(resp.Body())),
+		)
+	}
+	tracing.End(span, err)
+}
+
+// adapt strips or converts request fields not supported by the version of the instance.
+func (cl *client) adapt(req *resty.Request, url string) {
+	version := cl.instanceVersion()
+	if version == "" || req.Body == nil {
+		return
+	}
+	b, err := json.Marshal(req.Body)
+	if err != nil {
+		return
+	}
+	payload := make(map[string]any)
+	if err := json.Unmarshal(b, &payload); err != nil {
+		// not an object
+		return
+	}
+	if adapted := versions.Default.Adapt(url, version, payload); len(adapted) > 0 {
+		cl.log.With("path", url, "version", version, "fields", adapted).Info("Adapted request to instance version")
+		req.SetBody(payload)
+	}
+}
+
 func checkAuthenticationIssue(resp *resty.Response, rl *zap.SugaredLogger) {
 	if resp != nil && (resp.StatusCode() == http.StatusUnauthorized || resp.StatusCode() == http.StatusForbidden) {
 		rl.With("status", resp.StatusCode()).Error("there seems to be an authentication issue - " +
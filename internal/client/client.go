@@ -1,7 +1,7 @@
 package client
 
 import (
-	"crypto/tls"
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -10,6 +10,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/bakito/adguardhome-sync/internal/client/model"
 	"github.com/bakito/adguardhome-sync/internal/log"
+	"github.com/bakito/adguardhome-sync/internal/recording"
 	"github.com/bakito/adguardhome-sync/internal/types"
 )
 
@@ -68,15 +70,13 @@ func New(config types.AdGuardInstance, timeout time.Duration) (Client, error) {
 		return nil, err
 	}
 	u.Path = path.Clean(u.Path)
-	cl := resty.New().SetBaseURL(u.String()).SetDisableWarn(true).SetHeaders(config.RequestHeaders)
+	cl := resty.New().SetBaseURL(u.String()).SetDisableWarn(true).SetHeaders(config.RequestHeaders).
+		SetTransport(sharedTransport(config.InsecureSkipVerify))
 
 	if timeout > 0 {
 		cl = cl.SetTimeout(timeout)
 	}
 
-	// #nosec G402 has to be explicitly enabled
-	cl.SetTLSClientConfig(&tls.Config{InsecureSkipVerify: config.InsecureSkipVerify})
-
 	cookieParts := strings.Split(config.Cookie, "=")
 	if len(cookieParts) == 2 {
 		cl.SetCookie(&http.Cookie{
@@ -98,10 +98,20 @@ func New(config types.AdGuardInstance, timeout time.Duration) (Client, error) {
 		cl.SetRedirectPolicy(resty.NoRedirectPolicy())
 	}
 
+	if config.RecordFile != "" {
+		rec, err := recording.Open(config.RecordFile)
+		if err != nil {
+			return nil, fmt.Errorf("error opening record file %q: %w", config.RecordFile, err)
+		}
+		rec.Attach(cl)
+	}
+
 	return &client{
-		host:   config.Host,
-		client: cl,
-		log:    l.With("host", config.Host),
+		host:             config.Host,
+		client:           cl,
+		log:              l.With("host", config.Host),
+		version:          new(atomic.Pointer[string]),
+		schemaValidation: config.SchemaValidation,
 	}, nil
 }
 
@@ -112,7 +122,7 @@ type Client interface {
 	Host() string
 	Status() (*model.ServerStatus, error)
 	Stats() (*model.Stats, error)
-	QueryLog(limit int) (*model.QueryLog, error)
+	QueryLog(query QueryLogQuery) (*model.QueryLog, error)
 	ToggleProtection(enable bool) error
 	RewriteEntries() (*model.RewriteEntries, error)
 	AddRewriteEntries(e ...model.RewriteEntry) error
@@ -159,10 +169,48 @@ type Client interface {
 }
 
 type client struct {
-	client  *resty.Client
-	log     *zap.SugaredLogger
-	host    string
-	version string
+	client           *resty.Client
+	log              *zap.SugaredLogger
+	host             string
+	version          *atomic.Pointer[string]
+	schemaValidation string
+	schemaSet        *schemaSet
+	ctx              context.Context
+}
+
+// WithContext returns a client sending its requests with the context, e.g. to trace them as part of a sync.
+// Clients not supporting a context are returned unchanged.
+func WithContext(ctx context.Context, c Client) Client {
+	if cl, ok := c.(*client); ok {
+		cc := *cl
+		cc.ctx = ctx
+		return &cc
+	}
+	return c
+}
+
+// context returns the context of the requests.
+func (cl *client) context() context.Context {
+	if cl.ctx == nil {
+		return context.Background()
+	}
+	return cl.ctx
+}
+
+// instanceVersion returns the version of the instance reported by the last status call.
+func (cl *client) instanceVersion() string {
+	if v := cl.version.Load(); v != nil {
+		return *v
+	}
+	return ""
+}
+
+// schemas returns the openapi documents the responses are validated against, the embedded ones by default.
+func (cl *client) schemas() *schemaSet {
+	if cl.schemaSet != nil {
+		return cl.schemaSet
+	}
+	return embeddedSchemas()
 }
 
 func (cl *client) Host() string {
@@ -182,7 +230,7 @@ func contentType(resp *resty.Response) string {
 func (cl *client) Status() (*model.ServerStatus, error) {
 	status := &model.ServerStatus{}
 	err := cl.doGet(cl.client.R().EnableTrace().SetResult(status), "status")
-	cl.version = status.Version
+	cl.version.Store(&status.Version)
 	return status, err
 }
 
@@ -192,12 +240,33 @@ func (cl *client) Stats() (*model.Stats, error) {
 	return stats, err
 }
 
-func (cl *client) QueryLog(limit int) (*model.QueryLog, error) {
+// QueryLogQuery the page and filter of a query log request.
+type QueryLogQuery struct {
+	// Limit the maximum number of entries
+	Limit int
+	// OlderThan only return entries older than the time (RFC3339Nano), e.g. the oldest entry of the previous page
+	OlderThan string
+	// Search only return entries with a domain or client containing the value
+	Search string
+	// ResponseStatus only return entries with the response status, all if empty
+	ResponseStatus model.QueryLogParamsResponseStatus
+}
+
+func (cl *client) QueryLog(query QueryLogQuery) (*model.QueryLog, error) {
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(query.Limit))
+	params.Set("response_status", string(model.All))
+	if query.ResponseStatus != "" {
+		params.Set("response_status", string(query.ResponseStatus))
+	}
+	if query.OlderThan != "" {
+		params.Set("older_than", query.OlderThan)
+	}
+	if query.Search != "" {
+		params.Set("search", query.Search)
+	}
 	ql := &model.QueryLog{}
-	err := cl.doGet(
-		cl.client.R().EnableTrace().SetResult(ql),
-		fmt.Sprintf(`querylog?limit=%d&response_status="all"`, limit),
-	)
+	err := cl.doGet(cl.client.R().EnableTrace().SetResult(ql), "querylog?"+params.Encode())
 	return ql, err
 }
 
@@ -210,7 +279,7 @@ func (cl *client) RewriteEntries() (*model.RewriteEntries, error) {
 func (cl *client) AddRewriteEntries(entries ...model.RewriteEntry) error {
 	for _, e := range entries {
 		cl.log.With("domain", e.Domain, "answer", e.Answer, "enabled", e.Enabled).Info("Add DNS rewrite entry")
-		err := cl.doPost(cl.client.R().EnableTrace().SetBody(&e), "/rewrite/add")
+		err := cl.doPost(cl.client.R().EnableTrace().SetBody(&e), EndpointRewriteAdd)
 		if err != nil {
 			return err
 		}
@@ -221,7 +290,7 @@ func (cl *client) AddRewriteEntries(entries ...model.RewriteEntry) error {
 func (cl *client) DeleteRewriteEntries(entries ...model.RewriteEntry) error {
 	for _, e := range entries {
 		cl.log.With("domain", e.Domain, "answer", e.Answer, "enabled", e.Enabled).Info("Delete DNS rewrite entry")
-		err := cl.doPost(cl.client.R().EnableTrace().SetBody(&e), "/rewrite/delete")
+		err := cl.doPost(cl.client.R().EnableTrace().SetBody(&e), EndpointRewriteDelete)
 		if err != nil {
 			return err
 		}
@@ -233,7 +302,7 @@ func (cl *client) UpdateRewriteEntries(entries ...model.RewriteUpdate) error {
 	for _, e := range entries {
 		cl.log.With("domain", e.Update.Domain, "answer", e.Update.Answer, "enabled", e.Update.Enabled).
 			Info("Update DNS rewrite entry")
-		err := cl.doPut(cl.client.R().EnableTrace().SetBody(&e), "/rewrite/update")
+		err := cl.doPut(cl.client.R().EnableTrace().SetBody(&e), EndpointRewriteUpdate)
 		if err != nil {
 			return err
 		}
@@ -249,7 +318,7 @@ func (cl *client) RewriteSettings() (*model.RewriteSettings, error) {
 
 func (cl *client) SetRewriteSettings(settings *model.RewriteSettings) error {
 	cl.log.With("enabled", settings.Enabled).Info("Set rewrite settings")
-	return cl.doPut(cl.client.R().EnableTrace().SetBody(settings), "/rewrite/settings/update")
+	return cl.doPut(cl.client.R().EnableTrace().SetBody(settings), EndpointRewriteSettingsUpdate)
 }
 
 func (cl *client) SafeBrowsing() (bool, error) {
@@ -257,7 +326,7 @@ func (cl *client) SafeBrowsing() (bool, error) {
 }
 
 func (cl *client) ToggleSafeBrowsing(enable bool) error {
-	return cl.toggleBool("safebrowsing", enable)
+	return cl.toggleBool("safebrowsing", enable, EndpointSafeBrowsingEnable, EndpointSafeBrowsingDisable)
 }
 
 func (cl *client) Parental() (bool, error) {
@@ -265,7 +334,7 @@ func (cl *client) Parental() (bool, error) {
 }
 
 func (cl *client) ToggleParental(enable bool) error {
-	return cl.toggleBool("parental", enable)
+	return cl.toggleBool("parental", enable, EndpointParentalEnable, EndpointParentalDisable)
 }
 
 func (cl *client) toggleStatus(mode string) (bool, error) {
@@ -274,15 +343,13 @@ func (cl *client) toggleStatus(mode string) (bool, error) {
 	return fs.Enabled, err
 }
 
-func (cl *client) toggleBool(mode string, enable bool) error {
+func (cl *client) toggleBool(mode string, enable bool, enableEndpoint, disableEndpoint string) error {
 	cl.log.With("enable", enable).Info("Toggle " + mode)
-	var target string
+	target := disableEndpoint
 	if enable {
-		target = "enable"
-	} else {
-		target = "disable"
+		target = enableEndpoint
 	}
-	return cl.doPost(cl.client.R().EnableTrace(), fmt.Sprintf("/%s/%s", mode, target))
+	return cl.doPost(cl.client.R().EnableTrace(), target)
 }
 
 func (cl *client) Filtering() (*model.FilterStatus, error) {
@@ -294,13 +361,13 @@ func (cl *client) Filtering() (*model.FilterStatus, error) {
 func (cl *client) AddFilter(whitelist bool, f model.Filter) error {
 	cl.log.With("url", f.Url, "whitelist", whitelist, "enabled", f.Enabled).Info("Add filter")
 	ff := &model.AddUrlRequest{Name: new(f.Name), Url: new(f.Url), Whitelist: new(whitelist)}
-	return cl.doPost(cl.client.R().EnableTrace().SetBody(ff), "/filtering/add_url")
+	return cl.doPost(cl.client.R().EnableTrace().SetBody(ff), EndpointFilteringAddURL)
 }
 
 func (cl *client) DeleteFilter(whitelist bool, f model.Filter) error {
 	cl.log.With("url", f.Url, "whitelist", whitelist, "enabled", f.Enabled).Info("Delete filter")
 	ff := &model.RemoveUrlRequest{Url: new(f.Url), Whitelist: new(whitelist)}
-	return cl.doPost(cl.client.R().EnableTrace().SetBody(ff), "/filtering/remove_url")
+	return cl.doPost(cl.client.R().EnableTrace().SetBody(ff), EndpointFilteringRemoveURL)
 }
 
 func (cl *client) UpdateFilter(whitelist bool, f model.Filter) error {
@@ -309,20 +376,20 @@ func (cl *client) UpdateFilter(whitelist bool, f model.Filter) error {
 		Whitelist: new(whitelist), Url: new(f.Url),
 		Data: &model.FilterSetUrlData{Name: f.Name, Url: f.Url, Enabled: f.Enabled},
 	}
-	return cl.doPost(cl.client.R().EnableTrace().SetBody(fu), "/filtering/set_url")
+	return cl.doPost(cl.client.R().EnableTrace().SetBody(fu), EndpointFilteringSetURL)
 }
 
 func (cl *client) RefreshFilters(whitelist bool) error {
 	cl.log.With("whitelist", whitelist).Info("Refresh filter")
 	return cl.doPost(
 		cl.client.R().EnableTrace().SetBody(&model.FilterRefreshRequest{Whitelist: new(whitelist)}),
-		"/filtering/refresh",
+		EndpointFilteringRefresh,
 	)
 }
 
 func (cl *client) ToggleProtection(enable bool) error {
 	cl.log.With("enable", enable).Info("Toggle protection")
-	return cl.doPost(cl.client.R().EnableTrace().SetBody(&types.Protection{ProtectionEnabled: enable}), "/dns_config")
+	return cl.doPost(cl.client.R().EnableTrace().SetBody(&types.Protection{ProtectionEnabled: enable}), EndpointDNSConfig)
 }
 
 func (cl *client) SetCustomRules(rules *[]string) error {
@@ -331,7 +398,7 @@ func (cl *client) SetCustomRules(rules *[]string) error {
 		l = len(*rules)
 	}
 	cl.log.With("rules", l).Info("Set user rules")
-	return cl.doPost(cl.client.R().EnableTrace().SetBody(&model.SetRulesRequest{Rules: rules}), "/filtering/set_rules")
+	return cl.doPost(cl.client.R().EnableTrace().SetBody(&model.SetRulesRequest{Rules: rules}), EndpointFilteringSetRules)
 }
 
 func (cl *client) ToggleFiltering(enabled bool, interval int) error {
@@ -339,7 +406,7 @@ func (cl *client) ToggleFiltering(enabled bool, interval int) error {
 	return cl.doPost(cl.client.R().EnableTrace().SetBody(&model.FilterConfig{
 		Enabled:  new(enabled),
 		Interval: new(interval),
-	}), "/filtering/config")
+	}), EndpointFilteringConfig)
 }
 
 func (cl *client) BlockedServicesSchedule() (*model.BlockedServicesSchedule, error) {
@@ -351,7 +418,7 @@ func (cl *client) BlockedServicesSchedule() (*model.BlockedServicesSchedule, err
 func (cl *client) SetBlockedServicesSchedule(schedule *model.BlockedServicesSchedule) error {
 	cl.log.With("services", schedule.ServicesString(), "timezone", schedule.Schedule.TimeZone).
 		Info("Set blocked services schedule")
-	return cl.doPut(cl.client.R().EnableTrace().SetBody(schedule), "/blocked_services/update")
+	return cl.doPut(cl.client.R().EnableTrace().SetBody(schedule), EndpointBlockedServicesUpdate)
 }
 
 func (cl *client) Clients() (*model.Clients, error) {
@@ -362,20 +429,20 @@ func (cl *client) Clients() (*model.Clients, error) {
 
 func (cl *client) AddClient(client *model.Client) error {
 	cl.log.With("name", *client.Name).Info("Add client settings")
-	return cl.doPost(cl.client.R().EnableTrace().SetBody(client), "/clients/add")
+	return cl.doPost(cl.client.R().EnableTrace().SetBody(client), EndpointClientsAdd)
 }
 
 func (cl *client) UpdateClient(client *model.Client) error {
 	cl.log.With("name", *client.Name).Info("Update client settings")
 	return cl.doPost(
 		cl.client.R().EnableTrace().SetBody(&model.ClientUpdate{Name: client.Name, Data: client}),
-		"/clients/update",
+		EndpointClientsUpdate,
 	)
 }
 
 func (cl *client) DeleteClient(client *model.Client) error {
 	cl.log.With("name", *client.Name).Info("Delete client settings")
-	return cl.doPost(cl.client.R().EnableTrace().SetBody(client), "/clients/delete")
+	return cl.doPost(cl.client.R().EnableTrace().SetBody(client), EndpointClientsDelete)
 }
 
 func (cl *client) QueryLogConfig() (*model.QueryLogConfigWithIgnored, error) {
@@ -387,7 +454,7 @@ func (cl *client) QueryLogConfig() (*model.QueryLogConfigWithIgnored, error) {
 func (cl *client) SetQueryLogConfig(qlc *model.QueryLogConfigWithIgnored) error {
 	cl.log.With("enabled", *qlc.Enabled, "interval", *qlc.Interval, "anonymizeClientIP", *qlc.AnonymizeClientIp).
 		Info("Set query log config")
-	return cl.doPut(cl.client.R().EnableTrace().SetBody(qlc), "/querylog/config/update")
+	return cl.doPut(cl.client.R().EnableTrace().SetBody(qlc), EndpointQueryLogConfigUpdate)
 }
 
 func (cl *client) StatsConfig() (*model.GetStatsConfigResponse, error) {
@@ -398,7 +465,7 @@ func (cl *client) StatsConfig() (*model.GetStatsConfigResponse, error) {
 
 func (cl *client) SetStatsConfig(sc *model.PutStatsConfigUpdateRequest) error {
 	cl.log.With("interval", sc.Interval).Info("Set stats config")
-	return cl.doPut(cl.client.R().EnableTrace().SetBody(sc), "/stats/config/update")
+	return cl.doPut(cl.client.R().EnableTrace().SetBody(sc), EndpointStatsConfigUpdate)
 }
 
 func (cl *client) Setup() error {
@@ -435,7 +502,7 @@ func (cl *client) AccessList() (*model.AccessList, error) {
 
 func (cl *client) SetAccessList(list *model.AccessList) error {
 	cl.log.Info("Set access list")
-	return cl.doPost(cl.client.R().EnableTrace().SetBody(list), "/access/set")
+	return cl.doPost(cl.client.R().EnableTrace().SetBody(list), EndpointAccessSet)
 }
 
 func (cl *client) DNSConfig() (*model.DNSConfig, error) {
@@ -446,7 +513,7 @@ func (cl *client) DNSConfig() (*model.DNSConfig, error) {
 
 func (cl *client) SetDNSConfig(config *model.DNSConfig) error {
 	cl.log.With("upstream-dns", config.UpstreamDns).Info("Set dns config list")
-	return cl.doPost(cl.client.R().EnableTrace().SetBody(config), "/dns_config")
+	return cl.doPost(cl.client.R().EnableTrace().SetBody(config), EndpointDNSConfig)
 }
 
 func (cl *client) DhcpConfig() (*model.DhcpStatus, error) {
@@ -457,12 +524,12 @@ func (cl *client) DhcpConfig() (*model.DhcpStatus, error) {
 
 func (cl *client) SetDhcpConfig(config *model.DhcpStatus) error {
 	cl.log.Info("Set dhcp server config")
-	return cl.doPost(cl.client.R().EnableTrace().SetBody(config), "/dhcp/set_config")
+	return cl.doPost(cl.client.R().EnableTrace().SetBody(config), EndpointDhcpSetConfig)
 }
 
 func (cl *client) AddDHCPStaticLease(l model.DhcpStaticLease) error {
 	cl.log.With("mac", l.Mac, "ip", l.Ip, "hostname", l.Hostname).Info("Add static dhcp lease")
-	err := cl.doPost(cl.client.R().EnableTrace().SetBody(l), "/dhcp/add_static_lease")
+	err := cl.doPost(cl.client.R().EnableTrace().SetBody(l), EndpointDhcpAddStaticLease)
 	if err != nil {
 		return err
 	}
@@ -471,7 +538,7 @@ func (cl *client) AddDHCPStaticLease(l model.DhcpStaticLease) error {
 
 func (cl *client) DeleteDHCPStaticLease(l model.DhcpStaticLease) error {
 	cl.log.With("mac", l.Mac, "ip", l.Ip, "hostname", l.Hostname).Info("Delete static dhcp lease")
-	err := cl.doPost(cl.client.R().EnableTrace().SetBody(l), "/dhcp/remove_static_lease")
+	err := cl.doPost(cl.client.R().EnableTrace().SetBody(l), EndpointDhcpRemoveStaticLease)
 	if err != nil {
 		return err
 	}
@@ -486,7 +553,7 @@ func (cl *client) SafeSearchConfig() (*model.SafeSearchConfig, error) {
 
 func (cl *client) SetSafeSearchConfig(settings *model.SafeSearchConfig) error {
 	cl.log.With("enabled", *settings.Enabled).Info("Set safesearch settings")
-	return cl.doPut(cl.client.R().EnableTrace().SetBody(settings), "/safesearch/settings")
+	return cl.doPut(cl.client.R().EnableTrace().SetBody(settings), EndpointSafeSearchSettings)
 }
 
 func (cl *client) ProfileInfo() (*model.ProfileInfo, error) {
@@ -497,7 +564,7 @@ func (cl *client) ProfileInfo() (*model.ProfileInfo, error) {
 
 func (cl *client) SetProfileInfo(profile *model.ProfileInfo) error {
 	cl.log.With("language", profile.Language, "theme", profile.Theme).Info("Set profile")
-	return cl.doPut(cl.client.R().EnableTrace().SetBody(profile), "/profile/update")
+	return cl.doPut(cl.client.R().EnableTrace().SetBody(profile), EndpointProfileUpdate)
 }
 
 func (cl *client) TLSConfig() (*model.TlsConfig, error) {
@@ -508,5 +575,5 @@ func (cl *client) TLSConfig() (*model.TlsConfig, error) {
 
 func (cl *client) SetTLSConfig(tlsc *model.TlsConfig) error {
 	cl.log.With("enabled", tlsc.Enabled).Info("Set TLS config")
-	return cl.doPost(cl.client.R().EnableTrace().SetBody(tlsc), "/tls/configure")
+	return cl.doPost(cl.client.R().EnableTrace().SetBody(tlsc), EndpointTLSConfigure)
 }
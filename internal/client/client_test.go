@@ -4,16 +4,24 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"github.com/bakito/adguardhome-sync/internal/client"
 	"github.com/bakito/adguardhome-sync/internal/client/model"
+	"github.com/bakito/adguardhome-sync/internal/fakeagh"
 	"github.com/bakito/adguardhome-sync/internal/types"
 )
 
@@ -506,6 +514,79 @@ func TestClient_HelperFunctions(t *testing.T) {
 	})
 }
 
+func TestWithContext(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	before := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(before)
+
+	ts := httptest.NewServer(fakeagh.New())
+	defer ts.Close()
+	inst := types.AdGuardInstance{URL: ts.URL}
+	if err := inst.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	cl, err := client.New(inst, 0)
+	if err != nil {
+		t.Fatalf("client.New error = %v", err)
+	}
+
+	ctx, parent := tp.Tracer("test").Start(t.Context(), "sync")
+	traced := client.WithContext(ctx, cl)
+	if _, err := traced.Status(); err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if err := traced.SetProfileInfo(&model.ProfileInfo{Language: "en", Theme: "auto"}); err != nil {
+		t.Fatalf("SetProfileInfo() error = %v", err)
+	}
+	parent.End()
+
+	spans := sr.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("spans = %d, want 3", len(spans))
+	}
+	for _, span := range spans[:2] {
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %s is not a child of the sync span", span.Name())
+		}
+		attrs := make(map[string]string)
+		for _, a := range span.Attributes() {
+			attrs[string(a.Key)] = a.Value.Emit()
+		}
+		if attrs["server.address"] != inst.Host || attrs["http.response.status_code"] != "200" {
+			t.Errorf("span %s attributes = %v, want the host and status", span.Name(), attrs)
+		}
+	}
+	if name := spans[1].Name(); name != "PUT /profile/update" {
+		t.Errorf("span name = %s, want PUT /profile/update", name)
+	}
+	t.Run("should not trace the original client with a context", func(t *testing.T) {
+		if _, err := cl.Status(); err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		if span := sr.Ended()[3]; span.Parent().IsValid() {
+			t.Errorf("span %s has parent %v, want a root span", span.Name(), span.Parent())
+		}
+	})
+	t.Run("should name the span by the path and record the query as attribute", func(t *testing.T) {
+		if _, err := cl.QueryLog(client.QueryLogQuery{Limit: 10, Search: "example.com"}); err != nil {
+			t.Fatalf("QueryLog() error = %v", err)
+		}
+		span := sr.Ended()[4]
+		if span.Name() != "GET /querylog" {
+			t.Errorf("span name = %s, want GET /querylog", span.Name())
+		}
+		attrs := make(map[string]string)
+		for _, a := range span.Attributes() {
+			attrs[string(a.Key)] = a.Value.Emit()
+		}
+		if attrs["url.path"] != "/querylog" || !strings.Contains(attrs["url.query"], "search=example.com") {
+			t.Errorf("span attributes = %v, want the path and the query", attrs)
+		}
+	})
+}
+
 func ClientGet(t *testing.T, file, path string) (*httptest.Server, client.Client) {
 	t.Helper()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -567,3 +648,97 @@ func ClientPut(t *testing.T, path string, content ...string) (*httptest.Server,
 	}
 	return ts, cl
 }
+
+func TestClient_AdaptRequests(t *testing.T) {
+	var body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == types.DefaultAPIPath+"/status" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"version":"v0.107.67"}`))
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer ts.Close()
+	cl, err := client.New(types.AdGuardInstance{URL: ts.URL}, 0)
+	if err != nil {
+		t.Fatalf("client.New error = %v", err)
+	}
+	if _, err := cl.Status(); err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	err = cl.AddRewriteEntries(model.RewriteEntry{Domain: new("a.lan"), Answer: new("1.2.3.4"), Enabled: new(true)})
+	if err != nil {
+		t.Fatalf("AddRewriteEntries() error = %v", err)
+	}
+	if want := `{"answer":"1.2.3.4","domain":"a.lan"}`; body != want {
+		t.Errorf("Body = %s, want %s", body, want)
+	}
+}
+
+func TestCache(t *testing.T) {
+	var connections atomic.Int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"version":"v0.107.78"}`))
+	}))
+	ts.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	cache := client.NewCache()
+	inst := types.AdGuardInstance{URL: ts.URL, Username: "user", Password: "pass"}
+
+	cl1, err := cache.Get(inst, time.Second)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	t.Run("should reuse the client of the same instance", func(t *testing.T) {
+		cl2, err := cache.Get(inst, time.Second)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if cl1 != cl2 {
+			t.Error("Get() returned a new client for an unchanged instance")
+		}
+	})
+	t.Run("should recreate the client when the config changes", func(t *testing.T) {
+		changed := inst
+		changed.Password = "changed"
+		cl2, err := cache.Get(changed, time.Second)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if cl1 == cl2 {
+			t.Error("Get() returned the cached client for a changed instance")
+		}
+		cl3, err := cache.Get(changed, time.Minute)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if cl2 == cl3 {
+			t.Error("Get() returned the cached client for a changed timeout")
+		}
+	})
+	t.Run("should share keep-alive connections", func(t *testing.T) {
+		cl2, err := cache.Get(inst, 0)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		for range 3 {
+			for _, cl := range []client.Client{cl1, cl2} {
+				if _, err := cl.Status(); err != nil {
+					t.Fatalf("Status() error = %v", err)
+				}
+			}
+		}
+		if n := connections.Load(); n != 1 {
+			t.Errorf("connections = %d, want 1", n)
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package client
+
+// Endpoints of the AdGuard Home API written to by the client, relative to the API path.
+// The sync actions declare the endpoints they write to with these constants.
+const (
+	EndpointRewriteAdd            = "/rewrite/add"
+	EndpointRewriteDelete         = "/rewrite/delete"
+	EndpointRewriteUpdate         = "/rewrite/update"
+	EndpointRewriteSettingsUpdate = "/rewrite/settings/update"
+	EndpointSafeBrowsingEnable    = "/safebrowsing/enable"
+	EndpointSafeBrowsingDisable   = "/safebrowsing/disable"
+	EndpointParentalEnable        = "/parental/enable"
+	EndpointParentalDisable       = "/parental/disable"
+	EndpointFilteringAddURL       = "/filtering/add_url"
+	EndpointFilteringRemoveURL    = "/filtering/remove_url"
+	EndpointFilteringSetURL       = "/filtering/set_url"
+	EndpointFilteringRefresh      = "/filtering/refresh"
+	EndpointFilteringSetRules     = "/filtering/set_rules"
+	EndpointFilteringConfig       = "/filtering/config"
+	EndpointDNSConfig             = "/dns_config"
+	EndpointBlockedServicesUpdate = "/blocked_services/update"
+	EndpointClientsAdd            = "/clients/add"
+	EndpointClientsUpdate         = "/clients/update"
+	EndpointClientsDelete         = "/clients/delete"
+	EndpointQueryLogConfigUpdate  = "/querylog/config/update"
+	EndpointStatsConfigUpdate     = "/stats/config/update"
+	EndpointAccessSet             = "/access/set"
+	EndpointDhcpSetConfig         = "/dhcp/set_config"
+	EndpointDhcpAddStaticLease    = "/dhcp/add_static_lease"
+	EndpointDhcpRemoveStaticLease = "/dhcp/remove_static_lease"
+	EndpointSafeSearchSettings    = "/safesearch/settings"
+	EndpointProfileUpdate         = "/profile/update"
+	EndpointTLSConfigure          = "/tls/configure"
+)
@@ -0,0 +1,5 @@
+package model
+
+// SchemaVersion the AdGuard Home version of the openapi schema the model is generated from.
+// Keep in sync with ADGUARD_HOME_VERSION in the Makefile.
+const SchemaVersion = "v0.107.78"
@@ -0,0 +1,8 @@
+package model
+
+import "embed"
+
+// Schemas the embedded gzip compressed openapi documents 'schema/<version>.yaml.gz' of the AdGuard Home versions.
+//
+//go:embed schema
+var Schemas embed.FS
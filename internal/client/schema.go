@@ -0,0 +1,309 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"golang.org/x/mod/semver"
+
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+	"github.com/bakito/adguardhome-sync/internal/types"
+	"github.com/bakito/adguardhome-sync/internal/versions"
+)
+
+const schemaSuffix = ".yaml.gz"
+
+var (
+	// ErrSchemaMismatch is returned in schema validation mode 'fail' if a response does not match the schema.
+	ErrSchemaMismatch = errors.New("schema mismatch")
+	// ErrNoSchemas is returned if the schema validation is configured, but no schema is embedded.
+	ErrNoSchemas = errors.New("no AdGuardHome schema embedded, build with 'make schemas' to validate the responses")
+
+	// embeddedSchemas the openapi documents embedded into the model package, loaded on first use.
+	embeddedSchemas = sync.OnceValue(func() *schemaSet {
+		s, err := loadSchemas(model.Schemas)
+		if err != nil {
+			l.With("error", err).Error("Error loading the embedded AdGuardHome schemas")
+		}
+		return s
+	})
+)
+
+// mismatch a difference between a response and the schema.
+type mismatch struct {
+	field  string
+	reason string
+}
+
+// schemaSet the openapi documents of AdGuard Home versions.
+type schemaSet struct {
+	// versions sorted ascending
+	versions []string
+	docs     map[string]*openapi3.T
+	// warnOnce logs the missing documents once
+	warnOnce sync.Once
+}
+
+// loadSchemas loads the gzip compressed documents '<version>.yaml.gz' of the 'schema' directory.
+func loadSchemas(fsys fs.FS) (*schemaSet, error) {
+	s := &schemaSet{docs: make(map[string]*openapi3.T)}
+	files, err := fs.Glob(fsys, "schema/*"+schemaSuffix)
+	if err != nil {
+		return s, err
+	}
+	var errs []error
+	for _, file := range files {
+		version := strings.TrimSuffix(path.Base(file), schemaSuffix)
+		doc, err := loadSchema(fsys, file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("schema %s: %w", version, err))
+			continue
+		}
+		s.docs[version] = doc
+		s.versions = append(s.versions, version)
+	}
+	slices.SortFunc(s.versions, semver.Compare)
+	return s, errors.Join(errs...)
+}
+
+func loadSchema(fsys fs.FS, file string) (*openapi3.T, error) {
+	b, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+	return openapi3.NewLoader().LoadFromData(data)
+}
+
+// forVersion returns the document of the newest version not newer than the given one. Older versions get the
+// oldest and unknown versions the newest document.
+func (s *schemaSet) forVersion(version string) (*openapi3.T, string) {
+	if s == nil || len(s.versions) == 0 {
+		return nil, ""
+	}
+	selected := s.versions[len(s.versions)-1]
+	if semver.IsValid(version) {
+		selected = s.versions[0]
+		for _, v := range s.versions {
+			if semver.Compare(v, version) <= 0 {
+				selected = v
+			}
+		}
+	}
+	return s.docs[selected], selected
+}
+
+// CheckSchemas returns ErrNoSchemas if the instance validates the responses, but no schema is embedded.
+func CheckSchemas(inst types.AdGuardInstance) error {
+	return checkSchemas(inst, embeddedSchemas())
+}
+
+func checkSchemas(inst types.AdGuardInstance, s *schemaSet) error {
+	if inst.SchemaValidation == "" {
+		return nil
+	}
+	if s == nil || len(s.versions) == 0 {
+		return fmt.Errorf("%w: schemaValidation of %s", ErrNoSchemas, inst.URL)
+	}
+	return nil
+}
+
+// validateSchema validates the response body against the openapi document of the instance version.
+func (cl *client) validateSchema(url string, result any, body []byte) error {
+	if cl.schemaValidation == "" || result == nil {
+		return nil
+	}
+	path, _ := splitURL(url)
+
+	version := cl.instanceVersion()
+	doc, schemaVersion := cl.schemas().forVersion(version)
+	if doc == nil {
+		cl.schemas().warnOnce.Do(func() {
+			cl.log.Warn("No AdGuardHome schema embedded, the responses are not validated (see 'make schemas')")
+		})
+		return nil
+	}
+	schema := responseSchema(doc, path)
+	if schema == nil {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil //nolint:nilerr // decoding errors are reported by the caller
+	}
+	mismatches := compareSchema(value, schema, "", schemaVersion)
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, m := range mismatches {
+		err := fmt.Errorf("%w on %s field %s: %s", ErrSchemaMismatch, path, m.field, m.reason)
+		cl.log.With(
+			"path", path,
+			"field", m.field,
+			"version", version,
+			"schemaVersion", schemaVersion,
+			"newerThanSchema", version != "" && versions.IsNewerThan(version, schemaVersion),
+		).Warn(err.Error())
+		errs = append(errs, err)
+	}
+	if cl.schemaValidation == types.SchemaValidationFail {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// responseSchema returns the schema of the json response of a successful GET of the path, nil if not defined.
+func responseSchema(doc *openapi3.T, path string) *openapi3.SchemaRef {
+	item := doc.Paths.Find(path)
+	if item == nil || item.Get == nil || item.Get.Responses == nil {
+		return nil
+	}
+	resp := item.Get.Responses.Status(http.StatusOK)
+	if resp == nil || resp.Value == nil {
+		return nil
+	}
+	mt := resp.Value.Content.Get("application/json")
+	if mt == nil {
+		return nil
+	}
+	return mt.Schema
+}
+
+// compareSchema walks the decoded json value along the schema and returns unknown, missing or mistyped fields.
+func compareSchema(value any, ref *openapi3.SchemaRef, field, version string) []mismatch {
+	if value == nil || ref == nil || ref.Value == nil {
+		return nil
+	}
+	s := ref.Value
+	if len(s.AllOf) > 0 {
+		s = mergeAllOf(s)
+	}
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		// alternatives are not compared
+		return nil
+	}
+
+	switch {
+	case s.Type.Is(openapi3.TypeObject) || (s.Type == nil && len(s.Properties) > 0):
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return []mismatch{{field: fieldName(field), reason: "expected an object"}}
+		}
+		return compareObject(obj, s, field, version)
+	case s.Type.Is(openapi3.TypeArray):
+		arr, ok := value.([]any)
+		if !ok {
+			return []mismatch{{field: fieldName(field), reason: "expected an array"}}
+		}
+		var mm []mismatch
+		for _, v := range arr {
+			for _, m := range compareSchema(v, s.Items, field+"[]", version) {
+				if !slices.Contains(mm, m) {
+					mm = append(mm, m)
+				}
+			}
+		}
+		return mm
+	case s.Type.Is(openapi3.TypeString):
+		if _, ok := value.(string); !ok {
+			return []mismatch{{field: fieldName(field), reason: "expected a string"}}
+		}
+	case s.Type.Is(openapi3.TypeBoolean):
+		if _, ok := value.(bool); !ok {
+			return []mismatch{{field: fieldName(field), reason: "expected a boolean"}}
+		}
+	case s.Type.Is(openapi3.TypeInteger), s.Type.Is(openapi3.TypeNumber):
+		if _, ok := value.(float64); !ok {
+			return []mismatch{{field: fieldName(field), reason: "expected a number"}}
+		}
+	default:
+	}
+	return nil
+}
+
+func compareObject(obj map[string]any, s *openapi3.Schema, field, version string) []mismatch {
+	var mm []mismatch
+	for _, name := range sortedKeys(obj) {
+		prop, ok := s.Properties[name]
+		switch {
+		case ok:
+			mm = append(mm, compareSchema(obj[name], prop, join(field, name), version)...)
+		case s.AdditionalProperties.Schema != nil:
+			mm = append(mm, compareSchema(obj[name], s.AdditionalProperties.Schema, join(field, name), version)...)
+		case len(s.Properties) > 0 && (s.AdditionalProperties.Has == nil || !*s.AdditionalProperties.Has):
+			mm = append(mm, mismatch{field: join(field, name), reason: "not defined in schema " + version})
+		}
+	}
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			mm = append(mm, mismatch{field: join(field, name), reason: "missing in response"})
+		}
+	}
+	return mm
+}
+
+// mergeAllOf merges the properties of the allOf schemas into one object schema.
+func mergeAllOf(s *openapi3.Schema) *openapi3.Schema {
+	merged := &openapi3.Schema{
+		Type:       &openapi3.Types{openapi3.TypeObject},
+		Properties: make(openapi3.Schemas),
+	}
+	for _, ref := range append([]*openapi3.SchemaRef{{Value: s}}, s.AllOf...) {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		part := ref.Value
+		if len(part.AllOf) > 0 && part != s {
+			part = mergeAllOf(part)
+		}
+		for name, prop := range part.Properties {
+			merged.Properties[name] = prop
+		}
+		merged.Required = append(merged.Required, part.Required...)
+	}
+	return merged
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func fieldName(field string) string {
+	if field == "" {
+		return "<root>"
+	}
+	return field
+}
@@ -0,0 +1,194 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/bakito/adguardhome-sync/internal/fakeagh"
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+// testSchemas loads the openapi documents of 'testdata/schema' like the embedded ones.
+func testSchemas(t *testing.T) *schemaSet {
+	t.Helper()
+	files, err := filepath.Glob("testdata/schema/*.yaml")
+	if err != nil {
+		t.Fatalf("Glob error = %v", err)
+	}
+	fsys := fstest.MapFS{}
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile error = %v", err)
+		}
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write(b)
+		_ = zw.Close()
+		fsys["schema/"+filepath.Base(file)+".gz"] = &fstest.MapFile{Data: buf.Bytes()}
+	}
+	s, err := loadSchemas(fsys)
+	if err != nil {
+		t.Fatalf("loadSchemas error = %v", err)
+	}
+	return s
+}
+
+func TestSchemaSet_ForVersion(t *testing.T) {
+	s := testSchemas(t)
+	for _, tt := range []struct {
+		version string
+		want    string
+	}{
+		{version: "v0.107.70", want: "v0.107.70"},
+		{version: "v0.107.75", want: "v0.107.70"},
+		{version: "v0.107.78", want: "v0.107.78"},
+		{version: "v0.108.0-b.1", want: "v0.107.78"},
+		{version: "v0.107.68", want: "v0.107.70"},
+		{version: "", want: "v0.107.78"},
+	} {
+		t.Run(tt.version, func(t *testing.T) {
+			if doc, got := s.forVersion(tt.version); doc == nil || got != tt.want {
+				t.Errorf("forVersion(%q) = %q, want %q", tt.version, got, tt.want)
+			}
+		})
+	}
+	t.Run("should return no document without schemas", func(t *testing.T) {
+		if doc, got := (&schemaSet{}).forVersion("v0.107.78"); doc != nil || got != "" {
+			t.Errorf("forVersion() = %q, want no document", got)
+		}
+	})
+}
+
+func TestCheckSchemas(t *testing.T) {
+	inst := types.AdGuardInstance{URL: "http://origin:3000", SchemaValidation: types.SchemaValidationFail}
+	t.Run("should fail without schemas", func(t *testing.T) {
+		if err := checkSchemas(inst, &schemaSet{}); !errors.Is(err, ErrNoSchemas) {
+			t.Errorf("checkSchemas() error = %v, want %v", err, ErrNoSchemas)
+		}
+	})
+	t.Run("should pass with schemas", func(t *testing.T) {
+		if err := checkSchemas(inst, testSchemas(t)); err != nil {
+			t.Errorf("checkSchemas() error = %v", err)
+		}
+	})
+	t.Run("should pass without schema validation", func(t *testing.T) {
+		if err := checkSchemas(types.AdGuardInstance{URL: inst.URL}, &schemaSet{}); err != nil {
+			t.Errorf("checkSchemas() error = %v", err)
+		}
+	})
+}
+
+func TestClient_SchemaValidation(t *testing.T) {
+	schemas := testSchemas(t)
+	newClient := func(t *testing.T, url, mode string) *client {
+		t.Helper()
+		cl, err := New(types.AdGuardInstance{URL: url, SchemaValidation: mode}, 0)
+		if err != nil {
+			t.Fatalf("New error = %v", err)
+		}
+		c := cl.(*client)
+		c.schemaSet = schemas
+		return c
+	}
+	dnsInfo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"upstream_dns":["1.1.1.1"],"upstream_magic":true,"protection_enabled":"yes"}`))
+	}))
+	defer dnsInfo.Close()
+
+	t.Run("should accept responses matching the schema", func(t *testing.T) {
+		ts := httptest.NewServer(fakeagh.New(fakeagh.WithState(fakeagh.SampleState())))
+		defer ts.Close()
+		cl := newClient(t, ts.URL, types.SchemaValidationFail)
+
+		if _, err := cl.Status(); err != nil {
+			t.Errorf("Status() error = %v", err)
+		}
+		if _, err := cl.DNSConfig(); err != nil {
+			t.Errorf("DNSConfig() error = %v", err)
+		}
+		if _, err := cl.Filtering(); err != nil {
+			t.Errorf("Filtering() error = %v", err)
+		}
+	})
+	t.Run("should fail on unknown and mistyped fields", func(t *testing.T) {
+		_, err := newClient(t, dnsInfo.URL, types.SchemaValidationFail).DNSConfig()
+		if !errors.Is(err, ErrSchemaMismatch) {
+			t.Fatalf("DNSConfig() error = %v, want %v", err, ErrSchemaMismatch)
+		}
+		for _, want := range []string{
+			"schema mismatch on /dns_info field upstream_magic: not defined in schema v0.107.78",
+			"schema mismatch on /dns_info field protection_enabled: expected a boolean",
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("error = %v, want to contain %q", err, want)
+			}
+		}
+	})
+	t.Run("should only log in log mode", func(t *testing.T) {
+		if _, err := newClient(t, dnsInfo.URL, types.SchemaValidationLog).DNSConfig(); err == nil ||
+			errors.Is(err, ErrSchemaMismatch) {
+			t.Errorf("DNSConfig() error = %v, want only the decoding error", err)
+		}
+	})
+	t.Run("should report missing required fields", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"running":true,"dns_addresses":[],"dns_port":53,"http_port":80,"language":"en"}`))
+		}))
+		defer ts.Close()
+		_, err := newClient(t, ts.URL, types.SchemaValidationFail).Status()
+		if err == nil || !strings.Contains(err.Error(), "field version: missing in response") {
+			t.Errorf("Status() error = %v, want missing version", err)
+		}
+	})
+	t.Run("should validate against the document of the instance version", func(t *testing.T) {
+		for _, tt := range []struct {
+			version string
+			wantErr bool
+		}{
+			{version: "v0.107.70", wantErr: true},
+			{version: "v0.107.74", wantErr: true},
+			{version: "v0.107.78", wantErr: false},
+		} {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Path == types.DefaultAPIPath+"/status" {
+					_, _ = w.Write([]byte(`{"running":true,"dns_addresses":[],"dns_port":53,"http_port":80,` +
+						`"language":"en","protection_enabled":true,"version":"` + tt.version + `"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"upstream_dns":["1.1.1.1"],"blocked_response_ttl":10}`))
+			}))
+			cl := newClient(t, ts.URL, types.SchemaValidationFail)
+			if _, err := cl.Status(); err != nil {
+				t.Errorf("Status() error = %v", err)
+			}
+			_, err := cl.DNSConfig()
+			if tt.wantErr && (err == nil ||
+				!strings.Contains(err.Error(), "field blocked_response_ttl: not defined in schema v0.107.70")) {
+				t.Errorf("DNSConfig() of %s error = %v, want blocked_response_ttl not defined", tt.version, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("DNSConfig() of %s error = %v, want no error", tt.version, err)
+			}
+			ts.Close()
+		}
+	})
+	t.Run("should skip the validation without documents", func(t *testing.T) {
+		cl := newClient(t, dnsInfo.URL, types.SchemaValidationFail)
+		cl.schemaSet = &schemaSet{}
+		if _, err := cl.DNSConfig(); errors.Is(err, ErrSchemaMismatch) {
+			t.Errorf("DNSConfig() error = %v, want no schema mismatch", err)
+		}
+	})
+}
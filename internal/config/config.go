@@ -73,7 +73,7 @@ func Get(configFile string, flags Flags) (*AppConfig, error) {
 	cfg.Origin = nil
 
 	// overwrite from env vars
-	if err := env.Parse(cfg); err != nil {
+	if err := env.ParseWithOptions(cfg, env.Options{FuncMap: envParsers}); err != nil {
 		return nil, err
 	}
 	if err := env.ParseWithOptions(origin, env.Options{Prefix: "ORIGIN_"}); err != nil {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/bakito/adguardhome-sync/internal/config"
 	flagsmock "github.com/bakito/adguardhome-sync/internal/mocks/flags"
+	"github.com/bakito/adguardhome-sync/internal/types"
 )
 
 type configTestHelper struct {
@@ -245,6 +246,56 @@ func TestConfigGet_APIPort(t *testing.T) {
 	})
 }
 
+func TestConfigGet_APIUsersAndTokens(t *testing.T) {
+	t.Run("from config env var", func(t *testing.T) {
+		h := newConfigTestHelper(t)
+		defer h.finish()
+		h.setEnv(t, "API_USERS", "ops:operator:$2a$10$hash,monitor:viewer:$2a$10$other")
+		h.setEnv(t, "API_TOKENS", "grafana:viewer:abc123")
+		h.flags.EXPECT().Changed(gm.Any()).Return(false).AnyTimes()
+
+		cfg, err := config.Get("../../testdata/config_test_replicas.yaml", h.flags)
+		if err != nil {
+			t.Fatalf("config.Get error = %v, want nil", err)
+		}
+		users := cfg.Get().API.Users
+		if len(users) != 2 || users[0] != (types.APIUser{Username: "ops", Role: "operator", Password: "$2a$10$hash"}) {
+			t.Errorf("API Users = %v", users)
+		}
+		tokens := cfg.Get().API.Tokens
+		if len(tokens) != 1 || tokens[0] != (types.APIToken{Name: "grafana", Role: "viewer", Hash: "abc123"}) {
+			t.Errorf("API Tokens = %v", tokens)
+		}
+	})
+
+	t.Run("hooks from config env var", func(t *testing.T) {
+		h := newConfigTestHelper(t)
+		defer h.finish()
+		h.setEnv(t, "API_HOOKS", "dns-repo:s3cr:et")
+		h.flags.EXPECT().Changed(gm.Any()).Return(false).AnyTimes()
+
+		cfg, err := config.Get("../../testdata/config_test_replicas.yaml", h.flags)
+		if err != nil {
+			t.Fatalf("config.Get error = %v, want nil", err)
+		}
+		hooks := cfg.Get().API.Hooks
+		if len(hooks) != 1 || hooks[0].Name != "dns-repo" || hooks[0].Secret != "s3cr:et" {
+			t.Errorf("API Hooks = %v", hooks)
+		}
+	})
+
+	t.Run("invalid env var", func(t *testing.T) {
+		h := newConfigTestHelper(t)
+		defer h.finish()
+		h.setEnv(t, "API_TOKENS", "grafana")
+		h.flags.EXPECT().Changed(gm.Any()).Return(false).AnyTimes()
+
+		if _, err := config.Get("../../testdata/config_test_replicas.yaml", h.flags); err == nil {
+			t.Error("config.Get error = nil, want error")
+		}
+	})
+}
+
 func TestConfigGet_ReplicaDHCPServerEnabled(t *testing.T) {
 	h := newConfigTestHelper(t)
 	defer h.finish()
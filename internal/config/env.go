@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/caarlos0/env/v11"
 
@@ -55,3 +57,30 @@ func enrichReplicasFromEnv(initialReplicas []types.AdGuardInstance) ([]types.AdG
 
 	return replicas, nil
 }
+
+// envParsers parse the API users and tokens from 'name:role:hash' and the hooks from 'name:secret' env values.
+var envParsers = map[reflect.Type]env.ParserFunc{
+	reflect.TypeFor[types.APIUser](): func(v string) (any, error) {
+		name, role, hash, err := splitCredential(v)
+		return types.APIUser{Username: name, Role: role, Password: hash}, err
+	},
+	reflect.TypeFor[types.APIToken](): func(v string) (any, error) {
+		name, role, hash, err := splitCredential(v)
+		return types.APIToken{Name: name, Role: role, Hash: hash}, err
+	},
+	reflect.TypeFor[types.APIHook](): func(v string) (any, error) {
+		name, secret, ok := strings.Cut(v, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid hook %q: expected 'name:secret'", v)
+		}
+		return types.APIHook{Name: name, Secret: secret}, nil
+	},
+}
+
+func splitCredential(v string) (name, role, hash string, err error) {
+	parts := strings.SplitN(v, ":", 3)
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf("invalid credential %q: expected 'name:role:hash'", v)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
@@ -5,6 +5,8 @@ const (
 	FlagRunOnStart      = "runOnStart"
 	FlagPrintConfigOnly = "printConfigOnly"
 	FlagContinueOnError = "continueOnError"
+	FlagSyncFeatures    = "features"
+	FlagSyncReplicas    = "replicas"
 
 	FlagAPIPort     = "api-port"
 	FlagAPIUsername = "api-username"
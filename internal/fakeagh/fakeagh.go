@@ -0,0 +1,214 @@
+package fakeagh
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+	"github.com/bakito/adguardhome-sync/internal/log"
+	"github.com/bakito/adguardhome-sync/internal/utils"
+)
+
+// DefaultVersion the AdGuard Home version reported by a simulated instance.
+const DefaultVersion = model.SchemaVersion
+
+var l = log.GetLogger("fakeagh")
+
+// State the in memory state of a simulated AdGuard Home instance.
+type State struct {
+	Status          model.ServerStatus              `json:"status"`
+	Stats           model.Stats                     `json:"stats"`
+	QueryLog        []model.QueryLogItem            `json:"queryLog"`
+	QueryLogConfig  model.QueryLogConfigWithIgnored `json:"queryLogConfig"`
+	StatsConfig     model.GetStatsConfigResponse    `json:"statsConfig"`
+	RewriteEntries  model.RewriteEntries            `json:"rewriteEntries"`
+	RewriteSettings model.RewriteSettings           `json:"rewriteSettings"`
+	SafeBrowsing    bool                            `json:"safeBrowsing"`
+	Parental        bool                            `json:"parental"`
+	SafeSearch      model.SafeSearchConfig          `json:"safeSearch"`
+	Profile         model.ProfileInfo               `json:"profile"`
+	Filtering       model.FilterStatus              `json:"filtering"`
+	BlockedServices model.BlockedServicesSchedule   `json:"blockedServices"`
+	Clients         model.Clients                   `json:"clients"`
+	AccessList      model.AccessList                `json:"accessList"`
+	DNSConfig       model.DNSConfig                 `json:"dnsConfig"`
+	DHCP            model.DhcpStatus                `json:"dhcp"`
+	TLS             model.TlsConfig                 `json:"tls"`
+}
+
+// Server an in memory AdGuard Home instance serving the /control API used by the sync client.
+type Server struct {
+	mu         sync.Mutex
+	state      State
+	username   string
+	password   string
+	configured bool
+	nextFilter int64
+	mux        *http.ServeMux
+}
+
+// Option configures a simulated instance.
+type Option func(s *Server)
+
+// WithCredentials requires basic auth with the given credentials.
+func WithCredentials(username, password string) Option {
+	return func(s *Server) {
+		s.username = username
+		s.password = password
+	}
+}
+
+// WithSetupNeeded simulates a fresh instance which has to be configured via /install/configure first.
+func WithSetupNeeded() Option {
+	return func(s *Server) {
+		s.configured = false
+	}
+}
+
+// WithVersion sets the reported AdGuard Home version.
+func WithVersion(version string) Option {
+	return func(s *Server) {
+		s.state.Status.Version = version
+	}
+}
+
+// WithState sets the initial state.
+func WithState(state State) Option {
+	return func(s *Server) {
+		version := s.state.Status.Version
+		s.state = *utils.Clone(&state, &State{})
+		if s.state.Status.Version == "" {
+			s.state.Status.Version = version
+		}
+	}
+}
+
+// New creates a new simulated instance.
+func New(opts ...Option) *Server {
+	s := &Server{
+		state:      defaultState(),
+		configured: true,
+		nextFilter: 1,
+		mux:        http.NewServeMux(),
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	s.nextFilter += int64(len(filters(s.state.Filtering.Filters)) + len(filters(s.state.Filtering.WhitelistFilters)))
+	s.routes()
+	return s
+}
+
+// State returns a copy of the current state.
+func (s *Server) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *utils.Clone(&s.state, &State{})
+}
+
+// Update modifies the state.
+func (s *Server) Update(fn func(state *State)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.state)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	configured, username, password := s.configured, s.username, s.password
+	s.mu.Unlock()
+
+	if !configured && r.URL.Path != "/control/install/configure" {
+		w.Header().Set("Location", "/install.html")
+		w.WriteHeader(http.StatusFound)
+		return
+	}
+	if configured && username != "" {
+		if u, p, ok := r.BasicAuth(); !ok || u != username || p != password {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+	l.With("method", r.Method, "path", r.URL.Path).Debug("Handle request")
+	s.mux.ServeHTTP(w, r)
+}
+
+func defaultState() State {
+	stats := model.NewStats()
+	stats.TimeUnits = new(model.Hours)
+	stats.AvgProcessingTime = new(float32(0))
+	return State{
+		Status: model.ServerStatus{
+			Version:           DefaultVersion,
+			Running:           true,
+			ProtectionEnabled: true,
+			DhcpAvailable:     new(true),
+			DnsAddresses:      []string{"127.0.0.1"},
+			DnsPort:           53,
+			HttpPort:          3000,
+			Language:          "en",
+		},
+		Stats: *stats,
+		QueryLogConfig: model.QueryLogConfigWithIgnored{
+			QueryLogConfig: model.QueryLogConfig{
+				Enabled:           new(true),
+				Interval:          new(model.QueryLogConfigInterval(90 * 24 * 3600 * 1000)),
+				AnonymizeClientIp: new(false),
+			},
+			Ignored: []string{},
+		},
+		StatsConfig: model.GetStatsConfigResponse{
+			Enabled:  true,
+			Interval: 24 * 3600 * 1000,
+			Ignored:  []string{},
+		},
+		RewriteEntries:  model.RewriteEntries{},
+		RewriteSettings: model.RewriteSettings{Enabled: true},
+		SafeSearch:      model.SafeSearchConfig{Enabled: new(false)},
+		Profile:         model.ProfileInfo{Language: "en", Theme: model.Auto, Name: "admin"},
+		Filtering: model.FilterStatus{
+			Enabled:          new(true),
+			Interval:         new(24),
+			Filters:          new([]model.Filter{}),
+			WhitelistFilters: new([]model.Filter{}),
+			UserRules:        new([]string{}),
+		},
+		BlockedServices: model.BlockedServicesSchedule{
+			Ids:      new([]string{}),
+			Schedule: &model.Schedule{TimeZone: new("Local")},
+		},
+		Clients: model.Clients{Clients: new(model.ClientsArray{})},
+		AccessList: model.AccessList{
+			AllowedClients:    new([]string{}),
+			DisallowedClients: new([]string{}),
+			BlockedHosts:      new([]string{}),
+		},
+		DNSConfig: model.DNSConfig{
+			ProtectionEnabled: new(true),
+			UpstreamDns:       new([]string{"https://dns10.quad9.net/dns-query"}),
+			BootstrapDns:      new([]string{"9.9.9.10"}),
+			LocalPtrUpstreams: new([]string{}),
+		},
+		DHCP: model.DhcpStatus{
+			Enabled:      new(false),
+			Leases:       []model.DhcpLease{},
+			StaticLeases: new([]model.DhcpStaticLease{}),
+		},
+		TLS: model.TlsConfig{Enabled: new(false)},
+	}
+}
+
+func filters(f *[]model.Filter) []model.Filter {
+	if f == nil {
+		return nil
+	}
+	return *f
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		l.With("error", err).Error("Error writing response")
+	}
+}
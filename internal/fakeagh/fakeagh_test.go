@@ -0,0 +1,254 @@
+package fakeagh_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bakito/adguardhome-sync/internal/client"
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+	"github.com/bakito/adguardhome-sync/internal/fakeagh"
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+func newClient(t *testing.T, url, username, password string) client.Client {
+	t.Helper()
+	inst := types.AdGuardInstance{URL: url, Username: username, Password: password}
+	if err := inst.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	cl, err := client.New(inst, 0)
+	if err != nil {
+		t.Fatalf("client.New error = %v", err)
+	}
+	return cl
+}
+
+func TestServer(t *testing.T) {
+	t.Run("should require authentication", func(t *testing.T) {
+		ts := httptest.NewServer(fakeagh.New(fakeagh.WithCredentials("user", "pass")))
+		defer ts.Close()
+
+		if _, err := newClient(t, ts.URL, "user", "wrong").Status(); err == nil {
+			t.Error("Status() error = nil, want authentication error")
+		}
+		status, err := newClient(t, ts.URL, "user", "pass").Status()
+		if err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		if status.Version != fakeagh.DefaultVersion {
+			t.Errorf("Version = %s, want %s", status.Version, fakeagh.DefaultVersion)
+		}
+	})
+
+	t.Run("should require setup", func(t *testing.T) {
+		srv := fakeagh.New(fakeagh.WithSetupNeeded())
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+
+		cl := newClient(t, ts.URL, "user", "pass")
+		if _, err := cl.Status(); !errors.Is(err, client.ErrSetupNeeded) {
+			t.Fatalf("Status() error = %v, want %v", err, client.ErrSetupNeeded)
+		}
+		if err := cl.Setup(); err != nil {
+			t.Fatalf("Setup() error = %v", err)
+		}
+		if _, err := cl.Status(); err != nil {
+			t.Errorf("Status() error = %v", err)
+		}
+		if _, err := newClient(t, ts.URL, "", "").Status(); err == nil {
+			t.Error("Status() error = nil, want authentication error after setup with credentials")
+		}
+	})
+
+	t.Run("should authenticate requests during a setup", func(t *testing.T) {
+		srv := fakeagh.New(fakeagh.WithCredentials("user", "pass"))
+		serve := func(method, path, body string) int {
+			req := httptest.NewRequest(method, path, strings.NewReader(body))
+			req.SetBasicAuth("user", "pass")
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+			return rec.Code
+		}
+
+		// a repeated setup sets the credentials while other requests are authenticated
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Go(func() {
+				for range 20 {
+					_ = serve(http.MethodGet, "/control/status", "")
+				}
+			})
+		}
+		for range 20 {
+			if code := serve(http.MethodPost, "/control/install/configure", `{"username":"user","password":"pass"}`); code != http.StatusOK {
+				t.Errorf("setup status = %d, want %d", code, http.StatusOK)
+			}
+		}
+		wg.Wait()
+	})
+
+	t.Run("should manage rewrites", func(t *testing.T) {
+		srv := fakeagh.New()
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+		cl := newClient(t, ts.URL, "", "")
+
+		e := model.RewriteEntry{Domain: new("a.lan"), Answer: new("1.2.3.4")}
+		if err := cl.AddRewriteEntries(e); err != nil {
+			t.Fatalf("AddRewriteEntries() error = %v", err)
+		}
+		if err := cl.AddRewriteEntries(e); err == nil {
+			t.Error("AddRewriteEntries() error = nil, want error for duplicate entry")
+		}
+		u := model.RewriteEntry{Domain: new("a.lan"), Answer: new("5.6.7.8"), Enabled: new(true)}
+		if err := cl.UpdateRewriteEntries(model.RewriteUpdate{Target: &e, Update: &u}); err != nil {
+			t.Fatalf("UpdateRewriteEntries() error = %v", err)
+		}
+		rewrites, err := cl.RewriteEntries()
+		if err != nil {
+			t.Fatalf("RewriteEntries() error = %v", err)
+		}
+		if len(*rewrites) != 1 || *(*rewrites)[0].Answer != "5.6.7.8" {
+			t.Errorf("RewriteEntries() = %v, want the updated entry", *rewrites)
+		}
+		if err := cl.DeleteRewriteEntries(u); err != nil {
+			t.Fatalf("DeleteRewriteEntries() error = %v", err)
+		}
+		if n := len(srv.State().RewriteEntries); n != 0 {
+			t.Errorf("len(RewriteEntries) = %d, want 0", n)
+		}
+	})
+
+	t.Run("should manage filters", func(t *testing.T) {
+		srv := fakeagh.New()
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+		cl := newClient(t, ts.URL, "", "")
+
+		f := model.Filter{Name: "list", Url: "https://example.com/list.txt"}
+		if err := cl.AddFilter(true, f); err != nil {
+			t.Fatalf("AddFilter() error = %v", err)
+		}
+		f.Enabled = false
+		f.Name = "renamed"
+		if err := cl.UpdateFilter(true, f); err != nil {
+			t.Fatalf("UpdateFilter() error = %v", err)
+		}
+		fs, err := cl.Filtering()
+		if err != nil {
+			t.Fatalf("Filtering() error = %v", err)
+		}
+		if len(*fs.Filters) != 0 || len(*fs.WhitelistFilters) != 1 {
+			t.Fatalf("Filtering() = %d/%d, want 0/1 filters", len(*fs.Filters), len(*fs.WhitelistFilters))
+		}
+		if got := (*fs.WhitelistFilters)[0]; got.Name != "renamed" || got.Enabled {
+			t.Errorf("filter = %v, want renamed and disabled", got)
+		}
+		if err := cl.DeleteFilter(true, f); err != nil {
+			t.Fatalf("DeleteFilter() error = %v", err)
+		}
+	})
+
+	t.Run("should merge dns config and protection", func(t *testing.T) {
+		srv := fakeagh.New()
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+		cl := newClient(t, ts.URL, "", "")
+
+		if err := cl.ToggleProtection(false); err != nil {
+			t.Fatalf("ToggleProtection() error = %v", err)
+		}
+		status, err := cl.Status()
+		if err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		if status.ProtectionEnabled {
+			t.Error("ProtectionEnabled = true, want false")
+		}
+		cfg, err := cl.DNSConfig()
+		if err != nil {
+			t.Fatalf("DNSConfig() error = %v", err)
+		}
+		if cfg.UpstreamDns == nil || len(*cfg.UpstreamDns) == 0 {
+			t.Error("UpstreamDns should be kept when toggling protection")
+		}
+	})
+
+	t.Run("should record queries", func(t *testing.T) {
+		srv := fakeagh.New()
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+		cl := newClient(t, ts.URL, "", "")
+
+		srv.RecordQuery(fakeagh.Query{Domain: "example.com", Client: "10.0.0.1"})
+		srv.RecordQuery(fakeagh.Query{Domain: "ads.com", Client: "10.0.0.1", Reason: model.FilteredBlackList})
+
+		stats, err := cl.Stats()
+		if err != nil {
+			t.Fatalf("Stats() error = %v", err)
+		}
+		if *stats.NumDnsQueries != 2 || *stats.NumBlockedFiltering != 1 {
+			t.Errorf("stats = %d/%d, want 2/1", *stats.NumDnsQueries, *stats.NumBlockedFiltering)
+		}
+		ql, err := cl.QueryLog(client.QueryLogQuery{Limit: 1})
+		if err != nil {
+			t.Fatalf("QueryLog() error = %v", err)
+		}
+		if len(*ql.Data) != 1 || *(*ql.Data)[0].Question.Name != "ads.com" {
+			t.Errorf("QueryLog() = %v, want the newest entry only", *ql.Data)
+		}
+	})
+	t.Run("should filter the query log by response status", func(t *testing.T) {
+		srv := fakeagh.New()
+		for domain, reason := range map[string]model.FilteringReason{
+			"example.com":    model.NotFilteredNotFound,
+			"ads.com":        model.FilteredBlackList,
+			"adult.com":      model.FilteredParental,
+			"allowed.com":    model.NotFilteredWhiteList,
+			"rewritten.com":  model.RewriteRule,
+			"malware.com":    model.FilteredSafeBrowsing,
+			"safesearch.com": model.FilteredSafeSearch,
+		} {
+			srv.RecordQuery(fakeagh.Query{Domain: domain, Reason: reason})
+		}
+		domains := func(status string) ([]string, int) {
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/control/querylog?response_status="+status, nil))
+			var ql model.QueryLog
+			_ = json.NewDecoder(rec.Body).Decode(&ql)
+			var names []string
+			if ql.Data != nil {
+				for _, item := range *ql.Data {
+					names = append(names, *item.Question.Name)
+				}
+			}
+			slices.Sort(names)
+			return names, rec.Code
+		}
+
+		for status, want := range map[string][]string{
+			"all":                  {"ads.com", "adult.com", "allowed.com", "example.com", "malware.com", "rewritten.com", "safesearch.com"},
+			"filtered":             {"ads.com", "adult.com", "allowed.com", "malware.com", "rewritten.com", "safesearch.com"},
+			"blocked":              {"ads.com"},
+			"blocked_safebrowsing": {"malware.com"},
+			"blocked_parental":     {"adult.com"},
+			"safe_search":          {"safesearch.com"},
+			"whitelisted":          {"allowed.com"},
+			"rewritten":            {"rewritten.com"},
+			"processed":            {"adult.com", "example.com", "malware.com", "rewritten.com", "safesearch.com"},
+		} {
+			if got, code := domains(status); code != http.StatusOK || !slices.Equal(got, want) {
+				t.Errorf("%s = %d %v, want %v", status, code, got, want)
+			}
+		}
+		if _, code := domains("unknown"); code != http.StatusBadRequest {
+			t.Errorf("unknown status code = %d, want %d", code, http.StatusBadRequest)
+		}
+	})
+}
@@ -0,0 +1,388 @@
+package fakeagh
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+var (
+	errRewriteExists   = errors.New("rewrite entry already exists")
+	errRewriteNotFound = errors.New("rewrite entry not found")
+	errFilterExists    = errors.New("filter url already added")
+	errFilterNotFound  = errors.New("filter url not found")
+	errClientExists    = errors.New("client already exists")
+	errClientNotFound  = errors.New("client not found")
+	errLeaseExists     = errors.New("static lease already exists")
+	errLeaseNotFound   = errors.New("static lease not found")
+
+	// responseStatusReasons the filtering reasons of the query log response status filters as of AdGuard Home.
+	responseStatusReasons = map[model.QueryLogParamsResponseStatus][]model.FilteringReason{
+		model.Filtered: {
+			model.FilteredBlackList, model.FilteredBlockedService, model.FilteredParental, model.FilteredSafeBrowsing,
+			model.FilteredSafeSearch, model.NotFilteredWhiteList, model.Rewrite, model.RewriteEtcHosts, model.RewriteRule,
+		},
+		model.Blocked:             {model.FilteredBlackList, model.FilteredBlockedService},
+		model.BlockedSafebrowsing: {model.FilteredSafeBrowsing},
+		model.BlockedParental:     {model.FilteredParental},
+		model.SafeSearch:          {model.FilteredSafeSearch},
+		model.Whitelisted:         {model.NotFilteredWhiteList},
+		model.Rewritten:           {model.Rewrite, model.RewriteEtcHosts, model.RewriteRule},
+	}
+)
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /control/status", s.get(func(st *State) any { return st.Status }))
+	s.mux.HandleFunc("GET /control/stats", s.get(func(st *State) any { return st.Stats }))
+	s.mux.HandleFunc("GET /control/querylog", s.handleQueryLog)
+	s.mux.HandleFunc("GET /control/querylog/config", s.get(func(st *State) any { return st.QueryLogConfig }))
+	s.mux.HandleFunc("PUT /control/querylog/config/update", s.merge(func(st *State) any { return &st.QueryLogConfig }))
+	s.mux.HandleFunc("GET /control/stats/config", s.get(func(st *State) any { return st.StatsConfig }))
+	s.mux.HandleFunc("PUT /control/stats/config/update", s.merge(func(st *State) any { return &st.StatsConfig }))
+
+	s.mux.HandleFunc("GET /control/rewrite/list", s.get(func(st *State) any { return st.RewriteEntries }))
+	s.mux.HandleFunc("POST /control/rewrite/add", update(s, addRewrite))
+	s.mux.HandleFunc("POST /control/rewrite/delete", update(s, deleteRewrite))
+	s.mux.HandleFunc("PUT /control/rewrite/update", update(s, updateRewrite))
+	s.mux.HandleFunc("GET /control/rewrite/settings", s.get(func(st *State) any { return st.RewriteSettings }))
+	s.mux.HandleFunc("PUT /control/rewrite/settings/update", s.merge(func(st *State) any { return &st.RewriteSettings }))
+
+	s.mux.HandleFunc("GET /control/safebrowsing/status", s.get(func(st *State) any {
+		return model.EnableConfig{Enabled: st.SafeBrowsing}
+	}))
+	s.mux.HandleFunc("POST /control/safebrowsing/enable", s.toggle(func(st *State) { st.SafeBrowsing = true }))
+	s.mux.HandleFunc("POST /control/safebrowsing/disable", s.toggle(func(st *State) { st.SafeBrowsing = false }))
+	s.mux.HandleFunc("GET /control/parental/status", s.get(func(st *State) any {
+		return model.EnableConfig{Enabled: st.Parental}
+	}))
+	s.mux.HandleFunc("POST /control/parental/enable", s.toggle(func(st *State) { st.Parental = true }))
+	s.mux.HandleFunc("POST /control/parental/disable", s.toggle(func(st *State) { st.Parental = false }))
+	s.mux.HandleFunc("GET /control/safesearch/status", s.get(func(st *State) any { return st.SafeSearch }))
+	s.mux.HandleFunc("PUT /control/safesearch/settings", s.merge(func(st *State) any { return &st.SafeSearch }))
+	s.mux.HandleFunc("GET /control/profile", s.get(func(st *State) any { return st.Profile }))
+	s.mux.HandleFunc("PUT /control/profile/update", s.merge(func(st *State) any { return &st.Profile }))
+
+	s.mux.HandleFunc("GET /control/filtering/status", s.get(func(st *State) any { return st.Filtering }))
+	s.mux.HandleFunc("POST /control/filtering/add_url", update(s, s.addFilter))
+	s.mux.HandleFunc("POST /control/filtering/remove_url", update(s, removeFilter))
+	s.mux.HandleFunc("POST /control/filtering/set_url", update(s, setFilter))
+	s.mux.HandleFunc("POST /control/filtering/refresh", s.get(func(*State) any {
+		return model.FilterRefreshResponse{Updated: new(0)}
+	}))
+	s.mux.HandleFunc("POST /control/filtering/set_rules", update(s, func(st *State, r *model.SetRulesRequest) error {
+		st.Filtering.UserRules = r.Rules
+		return nil
+	}))
+	s.mux.HandleFunc("POST /control/filtering/config", s.merge(func(st *State) any { return &st.Filtering }))
+
+	s.mux.HandleFunc("GET /control/blocked_services/get", s.get(func(st *State) any { return st.BlockedServices }))
+	s.mux.HandleFunc("PUT /control/blocked_services/update", update(s,
+		func(st *State, bss *model.BlockedServicesSchedule) error {
+			st.BlockedServices = *bss
+			return nil
+		}))
+
+	s.mux.HandleFunc("GET /control/clients", s.get(func(st *State) any { return st.Clients }))
+	s.mux.HandleFunc("POST /control/clients/add", update(s, addClient))
+	s.mux.HandleFunc("POST /control/clients/update", update(s, updateClient))
+	s.mux.HandleFunc("POST /control/clients/delete", update(s, deleteClient))
+
+	s.mux.HandleFunc("GET /control/access/list", s.get(func(st *State) any { return st.AccessList }))
+	s.mux.HandleFunc("POST /control/access/set", update(s, func(st *State, al *model.AccessList) error {
+		st.AccessList = *al
+		return nil
+	}))
+
+	s.mux.HandleFunc("GET /control/dns_info", s.get(func(st *State) any { return st.DNSConfig }))
+	s.mux.HandleFunc("POST /control/dns_config", s.merge(func(st *State) any { return &st.DNSConfig }))
+
+	s.mux.HandleFunc("GET /control/dhcp/status", s.get(func(st *State) any { return st.DHCP }))
+	s.mux.HandleFunc("POST /control/dhcp/set_config", s.merge(func(st *State) any { return &st.DHCP }))
+	s.mux.HandleFunc("POST /control/dhcp/add_static_lease", update(s, addStaticLease))
+	s.mux.HandleFunc("POST /control/dhcp/remove_static_lease", update(s, removeStaticLease))
+
+	s.mux.HandleFunc("GET /control/tls/status", s.get(func(st *State) any { return st.TLS }))
+	s.mux.HandleFunc("POST /control/tls/configure", s.merge(func(st *State) any { return &st.TLS }))
+
+	s.mux.HandleFunc("POST /control/install/configure", s.handleInstall)
+}
+
+// get writes the value returned by fn as json.
+func (s *Server) get(fn func(st *State) any) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		s.mu.Lock()
+		b, err := json.Marshal(fn(&s.state))
+		s.mu.Unlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(b)
+	}
+}
+
+// merge applies the fields present in the request body onto the value returned by fn.
+func (s *Server) merge(fn func(st *State) any) http.HandlerFunc {
+	return update(s, func(st *State, body *json.RawMessage) error {
+		if err := json.Unmarshal(*body, fn(st)); err != nil {
+			return err
+		}
+		st.Status.ProtectionEnabled = st.DNSConfig.ProtectionEnabled == nil || *st.DNSConfig.ProtectionEnabled
+		return nil
+	})
+}
+
+func (s *Server) toggle(fn func(st *State)) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		s.mu.Lock()
+		fn(&s.state)
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// update decodes the request body and applies it to the state with fn.
+func update[T any](s *Server, fn func(st *State, body *T) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body := new(T)
+		if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		err := fn(&s.state, body)
+		s.mu.Unlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (s *Server) handleInstall(w http.ResponseWriter, r *http.Request) {
+	cfg := &types.InstallConfig{}
+	if err := json.NewDecoder(r.Body).Decode(cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configured = true
+	if cfg.Username != "" {
+		s.username = cfg.Username
+		s.password = cfg.Password
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleQueryLog(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	search := q.Get("search")
+	status := model.QueryLogParamsResponseStatus(strings.Trim(q.Get("response_status"), `"`))
+	if status != "" && !status.Valid() {
+		http.Error(w, "unsupported response_status "+string(status), http.StatusBadRequest)
+		return
+	}
+	var olderThan time.Time
+	if ot := q.Get("older_than"); ot != "" {
+		olderThan, _ = time.Parse(time.RFC3339Nano, ot)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data := []model.QueryLogItem{}
+	for _, item := range s.state.QueryLog {
+		if limit > 0 && len(data) >= limit {
+			break
+		}
+		if !olderThan.IsZero() && !itemTime(item).Before(olderThan) {
+			continue
+		}
+		if search != "" && !strings.Contains(itemDomain(item), search) && !strings.Contains(deref(item.Client), search) {
+			continue
+		}
+		if !matchesStatus(status, item.Reason) {
+			continue
+		}
+		data = append(data, item)
+	}
+	ql := model.QueryLog{Data: &data}
+	if len(data) > 0 {
+		ql.Oldest = data[len(data)-1].Time
+	}
+	writeJSON(w, ql)
+}
+
+// matchesStatus returns true if an entry with the filtering reason is listed with the response status.
+func matchesStatus(status model.QueryLogParamsResponseStatus, reason *model.FilteringReason) bool {
+	switch status {
+	case "", model.All:
+		return true
+	case model.Processed:
+		return !slices.Contains(
+			[]model.FilteringReason{model.FilteredBlackList, model.FilteredBlockedService, model.NotFilteredWhiteList},
+			deref(reason),
+		)
+	default:
+		return slices.Contains(responseStatusReasons[status], deref(reason))
+	}
+}
+
+func addRewrite(st *State, e *model.RewriteEntry) error {
+	if slices.ContainsFunc(st.RewriteEntries, func(re model.RewriteEntry) bool { return re.Key() == e.Key() }) {
+		return errRewriteExists
+	}
+	if e.Enabled == nil {
+		e.Enabled = new(true)
+	}
+	st.RewriteEntries = append(st.RewriteEntries, *e)
+	return nil
+}
+
+func deleteRewrite(st *State, e *model.RewriteEntry) error {
+	l := len(st.RewriteEntries)
+	st.RewriteEntries = slices.DeleteFunc(st.RewriteEntries, func(re model.RewriteEntry) bool { return re.Key() == e.Key() })
+	if len(st.RewriteEntries) == l {
+		return errRewriteNotFound
+	}
+	return nil
+}
+
+func updateRewrite(st *State, u *model.RewriteUpdate) error {
+	if u.Target == nil || u.Update == nil {
+		return errRewriteNotFound
+	}
+	i := slices.IndexFunc(st.RewriteEntries, func(re model.RewriteEntry) bool { return re.Key() == u.Target.Key() })
+	if i < 0 {
+		return errRewriteNotFound
+	}
+	st.RewriteEntries[i] = *u.Update
+	return nil
+}
+
+func filterList(st *State, whitelist *bool) *[]model.Filter {
+	if whitelist != nil && *whitelist {
+		if st.Filtering.WhitelistFilters == nil {
+			st.Filtering.WhitelistFilters = new([]model.Filter{})
+		}
+		return st.Filtering.WhitelistFilters
+	}
+	if st.Filtering.Filters == nil {
+		st.Filtering.Filters = new([]model.Filter{})
+	}
+	return st.Filtering.Filters
+}
+
+func (s *Server) addFilter(st *State, r *model.AddUrlRequest) error {
+	list := filterList(st, r.Whitelist)
+	url := deref(r.Url)
+	if slices.ContainsFunc(*list, func(f model.Filter) bool { return f.Url == url }) {
+		return errFilterExists
+	}
+	*list = append(*list, model.Filter{
+		Enabled:     true,
+		Id:          s.nextFilter,
+		Name:        deref(r.Name),
+		Url:         url,
+		LastUpdated: new(time.Now()),
+	})
+	s.nextFilter++
+	return nil
+}
+
+func removeFilter(st *State, r *model.RemoveUrlRequest) error {
+	list := filterList(st, r.Whitelist)
+	l := len(*list)
+	*list = slices.DeleteFunc(*list, func(f model.Filter) bool { return f.Url == deref(r.Url) })
+	if len(*list) == l {
+		return errFilterNotFound
+	}
+	return nil
+}
+
+func setFilter(st *State, r *model.FilterSetUrl) error {
+	list := filterList(st, r.Whitelist)
+	i := slices.IndexFunc(*list, func(f model.Filter) bool { return f.Url == deref(r.Url) })
+	if i < 0 || r.Data == nil {
+		return errFilterNotFound
+	}
+	(*list)[i].Name = r.Data.Name
+	(*list)[i].Url = r.Data.Url
+	(*list)[i].Enabled = r.Data.Enabled
+	return nil
+}
+
+func clientIndex(st *State, name string) int {
+	if st.Clients.Clients == nil {
+		st.Clients.Clients = new(model.ClientsArray{})
+	}
+	return slices.IndexFunc(*st.Clients.Clients, func(c model.Client) bool { return deref(c.Name) == name })
+}
+
+func addClient(st *State, c *model.Client) error {
+	if clientIndex(st, deref(c.Name)) >= 0 {
+		return errClientExists
+	}
+	*st.Clients.Clients = append(*st.Clients.Clients, *c)
+	return nil
+}
+
+func updateClient(st *State, u *model.ClientUpdate) error {
+	i := clientIndex(st, deref(u.Name))
+	if i < 0 || u.Data == nil {
+		return errClientNotFound
+	}
+	(*st.Clients.Clients)[i] = *u.Data
+	return nil
+}
+
+func deleteClient(st *State, c *model.ClientDelete) error {
+	i := clientIndex(st, deref(c.Name))
+	if i < 0 {
+		return errClientNotFound
+	}
+	*st.Clients.Clients = slices.Delete(*st.Clients.Clients, i, i+1)
+	return nil
+}
+
+func leaseIndex(st *State, mac string) int {
+	if st.DHCP.StaticLeases == nil {
+		st.DHCP.StaticLeases = new([]model.DhcpStaticLease{})
+	}
+	return slices.IndexFunc(*st.DHCP.StaticLeases, func(sl model.DhcpStaticLease) bool { return sl.Mac == mac })
+}
+
+func addStaticLease(st *State, sl *model.DhcpStaticLease) error {
+	if leaseIndex(st, sl.Mac) >= 0 {
+		return errLeaseExists
+	}
+	*st.DHCP.StaticLeases = append(*st.DHCP.StaticLeases, *sl)
+	return nil
+}
+
+func removeStaticLease(st *State, sl *model.DhcpStaticLease) error {
+	i := leaseIndex(st, sl.Mac)
+	if i < 0 {
+		return errLeaseNotFound
+	}
+	*st.DHCP.StaticLeases = slices.Delete(*st.DHCP.StaticLeases, i, i+1)
+	return nil
+}
+
+func deref[T any](v *T) T {
+	var t T
+	if v != nil {
+		t = *v
+	}
+	return t
+}
@@ -0,0 +1,44 @@
+package fakeagh
+
+import (
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+)
+
+// SampleState returns a state populated with some example configuration, suitable to be used for an origin instance.
+func SampleState() State {
+	st := defaultState()
+	st.RewriteEntries = model.RewriteEntries{
+		{Domain: new("nas.lan"), Answer: new("192.168.1.10"), Enabled: new(true)},
+		{Domain: new("*.example.lan"), Answer: new("192.168.1.20"), Enabled: new(true)},
+	}
+	st.Filtering.Filters = new([]model.Filter{
+		{
+			Enabled:    true,
+			Id:         1,
+			Name:       "AdGuard DNS filter",
+			Url:        "https://adguardteam.github.io/HostlistsRegistry/assets/filter_1.txt",
+			RulesCount: 57000,
+		},
+	})
+	st.Filtering.WhitelistFilters = new([]model.Filter{
+		{Enabled: true, Id: 2, Name: "Allow list", Url: "https://example.com/allowlist.txt", RulesCount: 10},
+	})
+	st.Filtering.UserRules = new([]string{"||ads.example.com^", "@@||example.org^"})
+	st.Clients.Clients = new(model.ClientsArray{
+		{
+			Name:                     new("laptop"),
+			Ids:                      new([]string{"192.168.1.100"}),
+			FilteringEnabled:         new(true),
+			UseGlobalSettings:        new(true),
+			UseGlobalBlockedServices: new(true),
+		},
+	})
+	st.DHCP.StaticLeases = new([]model.DhcpStaticLease{
+		{Mac: "00:11:22:33:44:55", Ip: "192.168.1.10", Hostname: "nas"},
+	})
+	st.BlockedServices.Ids = new([]string{"tiktok"})
+	st.DNSConfig.UpstreamDns = new([]string{"https://dns.cloudflare.com/dns-query", "https://dns10.quad9.net/dns-query"})
+	st.SafeBrowsing = true
+	st.Profile.Theme = model.Dark
+	return st
+}
@@ -0,0 +1,120 @@
+package fakeagh
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+)
+
+// Query a simulated DNS query.
+type Query struct {
+	Time     time.Time
+	Domain   string
+	Client   string
+	Upstream string
+	Reason   model.FilteringReason
+	Cached   bool
+	Elapsed  time.Duration
+}
+
+// RecordQuery adds the query to the query log and updates the statistics.
+func (s *Server) RecordQuery(q Query) {
+	if q.Time.IsZero() {
+		q.Time = time.Now()
+	}
+	if q.Reason == "" {
+		q.Reason = model.NotFilteredNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := model.QueryLogItem{
+		Time:      new(q.Time.Format(time.RFC3339Nano)),
+		Client:    new(q.Client),
+		Question:  &model.DnsQuestion{Name: new(q.Domain), Type: new("A"), Class: new("IN")},
+		Reason:    new(q.Reason),
+		Cached:    new(q.Cached),
+		ElapsedMs: new(fmt.Sprintf("%.3f", float64(q.Elapsed.Microseconds())/1000)),
+		Status:    new("NOERROR"),
+	}
+	if q.Upstream != "" {
+		item.Upstream = new(q.Upstream)
+	}
+	// the query log is sorted newest first
+	s.state.QueryLog = slices.Insert(s.state.QueryLog, 0, item)
+
+	st := &s.state.Stats
+	st.NumDnsQueries = inc(st.NumDnsQueries)
+	incLast(st.DnsQueries)
+	st.TopQueriedDomains = incTop(st.TopQueriedDomains, q.Domain)
+	st.TopClients = incTop(st.TopClients, q.Client)
+	if q.Upstream != "" {
+		st.TopUpstreamsResponses = incTop(st.TopUpstreamsResponses, q.Upstream)
+	}
+	switch q.Reason {
+	case model.FilteredBlackList, model.FilteredBlockedService:
+		st.NumBlockedFiltering = inc(st.NumBlockedFiltering)
+		incLast(st.BlockedFiltering)
+		st.TopBlockedDomains = incTop(st.TopBlockedDomains, q.Domain)
+	case model.FilteredParental:
+		st.NumReplacedParental = inc(st.NumReplacedParental)
+		incLast(st.ReplacedParental)
+	case model.FilteredSafeBrowsing:
+		st.NumReplacedSafebrowsing = inc(st.NumReplacedSafebrowsing)
+		incLast(st.ReplacedSafebrowsing)
+	case model.FilteredSafeSearch:
+		st.NumReplacedSafesearch = inc(st.NumReplacedSafesearch)
+	default:
+	}
+}
+
+func inc(v *int) *int {
+	return new(deref(v) + 1)
+}
+
+func incLast(v *[]int) {
+	if v != nil && len(*v) > 0 {
+		(*v)[len(*v)-1]++
+	}
+}
+
+func incTop(entries *[]model.TopArrayEntry, key string) *[]model.TopArrayEntry {
+	if entries == nil {
+		entries = new([]model.TopArrayEntry{})
+	}
+	for i := range *entries {
+		if v, ok := (*entries)[i].Get(key); ok {
+			(*entries)[i].Set(key, v+1)
+			return entries
+		}
+	}
+	e := model.TopArrayEntry{}
+	e.Set(key, 1)
+	*entries = append(*entries, e)
+	return entries
+}
+
+func itemTime(item model.QueryLogItem) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, deref(item.Time))
+	return t
+}
+
+func itemDomain(item model.QueryLogItem) string {
+	if item.Question == nil {
+		return ""
+	}
+	return deref(item.Question.Name)
+}
+
+func isBlocked(reason *model.FilteringReason) bool {
+	switch deref(reason) {
+	case model.FilteredBlackList, model.FilteredBlockedService, model.FilteredParental,
+		model.FilteredSafeBrowsing, model.FilteredSafeSearch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,115 @@
+package log
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// hostFields the fields referring to the host of an AdGuard Home instance.
+var hostFields = []string{"host", "from", "to"}
+
+// Filter selects log entries. Empty criteria match all entries.
+type Filter struct {
+	// Level the minimum level of the entries
+	Level zapcore.LevelEnabler
+	// Logger the name of the logger, including its child loggers
+	Logger string
+	// Host the host of the instance the entries refer to
+	Host string
+	// Since the time of the oldest entries
+	Since time.Time
+}
+
+// Matches returns true if the entry matches all criteria of the filter.
+func (f Filter) Matches(e Entry) bool {
+	if f.Level != nil && !f.Level.Enabled(e.Level) {
+		return false
+	}
+	if f.Logger != "" && e.Logger != f.Logger && !strings.HasPrefix(e.Logger, f.Logger+".") {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	return f.Host == "" || e.refersTo(f.Host)
+}
+
+func (e Entry) refersTo(host string) bool {
+	for _, field := range hostFields {
+		if v, ok := e.Fields[field].(string); ok && v == host {
+			return true
+		}
+	}
+	return false
+}
+
+// ringBuffer a fixed size, concurrency safe buffer keeping the latest entries.
+type ringBuffer struct {
+	mu      sync.RWMutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	return &ringBuffer{entries: make([]Entry, size)}
+}
+
+func (b *ringBuffer) add(e Entry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.entries) == 0 {
+		return
+	}
+	b.entries[b.next] = e
+	b.next = (b.next + 1) % len(b.entries)
+	b.full = b.full || b.next == 0
+}
+
+// list returns the entries matching the filter, oldest first.
+func (b *ringBuffer) list(filter Filter) []Entry {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var entries []Entry
+	for _, e := range b.ordered() {
+		if filter.Matches(e) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// ordered returns the entries oldest first, the caller must hold the lock.
+func (b *ringBuffer) ordered() []Entry {
+	ordered := b.entries[:b.next]
+	if b.full {
+		ordered = append(b.entries[b.next:len(b.entries):len(b.entries)], ordered...)
+	}
+	return ordered
+}
+
+// resize changes the size of the buffer, keeping the latest entries.
+func (b *ringBuffer) resize(size int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	latest := b.ordered()
+	latest = latest[max(0, len(latest)-size):]
+	b.entries = make([]Entry, size)
+	copy(b.entries, latest)
+	b.next = len(latest)
+	b.full = size > 0 && len(latest) == size
+	if b.full {
+		b.next = 0
+	}
+}
+
+func (b *ringBuffer) clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	clear(b.entries)
+	b.next = 0
+	b.full = false
+}
@@ -0,0 +1,97 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRingBuffer(t *testing.T) {
+	t.Run("should keep the latest entries in order", func(t *testing.T) {
+		b := newRingBuffer(3)
+		for _, m := range []string{"1", "2", "3", "4", "5"} {
+			b.add(Entry{Message: m})
+		}
+		got := b.list(Filter{})
+		if len(got) != 3 || got[0].Message != "3" || got[2].Message != "5" {
+			t.Errorf("list() = %v, want [3 4 5]", got)
+		}
+	})
+	t.Run("should return the entries before the buffer is full", func(t *testing.T) {
+		b := newRingBuffer(3)
+		b.add(Entry{Message: "1"})
+		if got := b.list(Filter{}); len(got) != 1 {
+			t.Errorf("list() = %v, want [1]", got)
+		}
+	})
+	t.Run("should clear the entries", func(t *testing.T) {
+		b := newRingBuffer(3)
+		b.add(Entry{Message: "1"})
+		b.clear()
+		b.add(Entry{Message: "2"})
+		if got := b.list(Filter{}); len(got) != 1 || got[0].Message != "2" {
+			t.Errorf("list() = %v, want [2]", got)
+		}
+	})
+	t.Run("should keep the latest entries when resized", func(t *testing.T) {
+		b := newRingBuffer(3)
+		for _, m := range []string{"1", "2", "3", "4"} {
+			b.add(Entry{Message: m})
+		}
+		b.resize(2)
+		b.add(Entry{Message: "5"})
+		if got := b.list(Filter{}); len(got) != 2 || got[0].Message != "4" || got[1].Message != "5" {
+			t.Errorf("list() = %v, want [4 5]", got)
+		}
+		b.resize(4)
+		b.add(Entry{Message: "6"})
+		if got := b.list(Filter{}); len(got) != 3 || got[0].Message != "4" || got[2].Message != "6" {
+			t.Errorf("list() = %v, want [4 5 6]", got)
+		}
+		b.resize(0)
+		if got := b.list(Filter{}); len(got) != 0 {
+			t.Errorf("list() = %v, want none", got)
+		}
+	})
+	t.Run("should not keep entries without size", func(t *testing.T) {
+		b := newRingBuffer(0)
+		b.add(Entry{Message: "1"})
+		if got := b.list(Filter{}); len(got) != 0 {
+			t.Errorf("list() = %v, want none", got)
+		}
+	})
+}
+
+func TestFilter_Matches(t *testing.T) {
+	now := time.Now()
+	e := Entry{
+		Time:   now,
+		Level:  zapcore.WarnLevel,
+		Logger: "sync.client",
+		Fields: map[string]any{"to": "replica:3000"},
+	}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{name: "should match without criteria", want: true},
+		{name: "should match the minimum level", filter: Filter{Level: zapcore.WarnLevel}, want: true},
+		{name: "should not match a higher level", filter: Filter{Level: zapcore.ErrorLevel}},
+		{name: "should match the logger", filter: Filter{Logger: "sync.client"}, want: true},
+		{name: "should match the parent logger", filter: Filter{Logger: "sync"}, want: true},
+		{name: "should not match a logger prefix", filter: Filter{Logger: "syn"}},
+		{name: "should match the host", filter: Filter{Host: "replica:3000"}, want: true},
+		{name: "should not match other hosts", filter: Filter{Host: "origin:3000"}},
+		{name: "should match entries since", filter: Filter{Since: now.Add(-time.Second)}, want: true},
+		{name: "should not match older entries", filter: Filter{Since: now.Add(time.Second)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(e); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
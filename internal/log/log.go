@@ -1,23 +1,70 @@
 package log
 
 import (
+	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+
+	"github.com/bakito/adguardhome-sync/internal/utils"
 )
 
 const (
-	logHistorySize = 50
-	envLogLevel    = "LOG_LEVEL"
-	envLogFormat   = "LOG_FORMAT"
+	defaultHistorySize = 500
+	envLogLevel        = "LOG_LEVEL"
+	envLogFormat       = "LOG_FORMAT"
+	envLogHistorySize  = "LOG_HISTORY_SIZE"
 )
 
 var (
-	rootLogger *zap.Logger
-	logs       []string
+	rootLogger  *zap.Logger
+	history     = newRingBuffer(defaultHistorySize)
+	subscribers utils.Broadcaster[Entry]
 )
 
+// Entry a structured log entry.
+type Entry struct {
+	Time    time.Time      `json:"time"`
+	Level   zapcore.Level  `json:"level"`
+	Logger  string         `json:"logger,omitempty"`
+	Message string         `json:"message"`
+	Fields  map[string]any `json:"fields,omitempty"`
+
+	line string
+}
+
+// String returns the console encoded log line. Entries decoded from JSON are encoded on demand.
+func (e Entry) String() string {
+	if e.line != "" {
+		return e.line
+	}
+	fields := make([]zapcore.Field, 0, len(e.Fields))
+	for _, k := range slices.Sorted(maps.Keys(e.Fields)) {
+		fields = append(fields, zap.Any(k, e.Fields[k]))
+	}
+	buf, err := zapcore.NewConsoleEncoder(encoderConfig()).EncodeEntry(zapcore.Entry{
+		Time:       e.Time,
+		Level:      e.Level,
+		LoggerName: e.Logger,
+		Message:    e.Message,
+	}, fields)
+	if err != nil {
+		return e.Message + "\n"
+	}
+	defer buf.Free()
+	return buf.String()
+}
+
+// Subscribe returns a channel receiving all log entries from now on and a function to unsubscribe.
+func Subscribe(buffer int) (<-chan Entry, func()) {
+	return subscribers.Subscribe(buffer)
+}
+
 // GetLogger returns a named logger.
 func GetLogger(name string) *zap.SugaredLogger {
 	return rootLogger.Named(name).Sugar()
@@ -41,47 +88,66 @@ func initRootLogger() (*zap.Logger, error) {
 	}
 
 	format := "console"
-	if fmt, ok := os.LookupEnv(envLogFormat); ok {
-		format = fmt
+	if f, ok := os.LookupEnv(envLogFormat); ok {
+		format = f
+	}
+
+	if size, ok := os.LookupEnv(envLogHistorySize); ok {
+		s, err := strconv.Atoi(size)
+		if err != nil || s < 0 {
+			return nil, fmt.Errorf("invalid %s %q: must be zero or a positive number", envLogHistorySize, size)
+		}
+		history = newRingBuffer(s)
 	}
 
 	cfg := zap.Config{
 		Level:            zap.NewAtomicLevelAt(level),
 		Development:      false,
 		Encoding:         format,
-		EncoderConfig:    zap.NewProductionEncoderConfig(),
+		EncoderConfig:    encoderConfig(),
 		OutputPaths:      []string{"stdout"},
 		ErrorOutputPaths: []string{"stderr"},
 	}
-	cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-	cfg.EncoderConfig.EncodeDuration = zapcore.StringDurationEncoder
-	cfg.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
 
 	opt := zap.WrapCore(func(c zapcore.Core) zapcore.Core {
 		return zapcore.NewTee(c, &logList{
 			enc:          zapcore.NewConsoleEncoder(cfg.EncoderConfig),
 			LevelEnabler: cfg.Level,
+			history:      history,
 		})
 	})
 
 	return cfg.Build(opt)
 }
 
+func encoderConfig() zapcore.EncoderConfig {
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	cfg.EncodeDuration = zapcore.StringDurationEncoder
+	cfg.EncodeLevel = zapcore.CapitalLevelEncoder
+	return cfg
+}
+
 type logList struct {
 	zapcore.LevelEnabler
-	enc zapcore.Encoder
+	enc     zapcore.Encoder
+	fields  []zapcore.Field
+	history *ringBuffer
 }
 
 func (l *logList) clone() *logList {
 	return &logList{
 		LevelEnabler: l.LevelEnabler,
 		enc:          l.enc.Clone(),
+		fields:       l.fields,
+		history:      l.history,
 	}
 }
 
 func (l *logList) With(fields []zapcore.Field) zapcore.Core {
 	clone := l.clone()
 	addFields(clone.enc, fields)
+	clone.fields = append(clone.fields[:len(clone.fields):len(clone.fields)], fields...)
 	return clone
 }
 
@@ -97,26 +163,55 @@ func (l *logList) Write(ent zapcore.Entry, fields []zapcore.Field) error {
 	if err != nil {
 		return err
 	}
-	logs = append(logs, buf.String())
+	e := l.entry(ent, fields)
+	e.line = buf.String()
+	buf.Free()
 
-	if len(logs) > logHistorySize {
-		logs = logs[len(logs)-logHistorySize:]
-	}
+	l.history.add(e)
+	subscribers.Publish(e)
 	return nil
 }
 
+// entry creates the structured entry including the fields of the logger.
+func (l *logList) entry(ent zapcore.Entry, fields []zapcore.Field) Entry {
+	enc := zapcore.NewMapObjectEncoder()
+	addFields(enc, l.fields)
+	addFields(enc, fields)
+	return Entry{
+		Time:    ent.Time,
+		Level:   ent.Level,
+		Logger:  ent.LoggerName,
+		Message: ent.Message,
+		Fields:  enc.Fields,
+	}
+}
+
 func (*logList) Sync() error {
 	return nil
 }
 
-// Logs get the current logs.
+// Logs get the current console encoded logs.
 func Logs() []string {
-	return logs
+	var lines []string
+	for _, e := range history.list(Filter{}) {
+		lines = append(lines, e.String())
+	}
+	return lines
+}
+
+// Entries get the current log entries matching the filter, oldest first.
+func Entries(filter Filter) []Entry {
+	return history.list(filter)
+}
+
+// SetHistorySize changes the number of log entries kept in the history, 0 disables the history.
+func SetHistorySize(size int) {
+	history.resize(size)
 }
 
 // Clear  the current logs.
 func Clear() {
-	logs = nil
+	history.clear()
 }
 
 func addFields(enc zapcore.ObjectEncoder, fields []zapcore.Field) {
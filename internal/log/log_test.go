@@ -1,6 +1,7 @@
 package log
 
 import (
+	"encoding/json"
 	"testing"
 
 	"go.uber.org/zap"
@@ -98,13 +99,13 @@ func TestLogListWrite(t *testing.T) {
 			ll := &logList{
 				LevelEnabler: zapcore.DebugLevel,
 				enc:          zapcore.NewConsoleEncoder(zap.NewProductionEncoderConfig()),
+				history:      newRingBuffer(10),
 			}
-			logs = nil
 			err := ll.Write(zapcore.Entry{Message: "test"}, tt.fields)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("Write() error = %v, wantErr %v", err, tt.wantErr)
 			}
-			if len(logs) == 0 || logs[0] == "" {
+			if entries := ll.history.list(Filter{}); len(entries) == 0 || entries[0].String() == "" {
 				t.Error("Write() did not append logs")
 			}
 		})
@@ -128,25 +129,69 @@ func TestGetLogger(t *testing.T) {
 	}
 }
 
+func TestSubscribe(t *testing.T) {
+	entries, unsubscribe := Subscribe(10)
+	GetLogger("test").With("to", "replica").Warnw("message", "count", 3)
+	unsubscribe()
+
+	e, ok := <-entries
+	if !ok {
+		t.Fatal("no entry received")
+	}
+	if e.Level != zapcore.WarnLevel || e.Logger != "test" || e.Message != "message" {
+		t.Errorf("entry = %v", e)
+	}
+	if e.Fields["to"] != "replica" || e.Fields["count"] != int64(3) {
+		t.Errorf("entry fields = %v", e.Fields)
+	}
+	if _, ok := <-entries; ok {
+		t.Error("channel should be closed after unsubscribe")
+	}
+}
+
+func TestEntry_String(t *testing.T) {
+	entries, unsubscribe := Subscribe(10)
+	GetLogger("test").With("to", "replica").Warnw("message", "count", 3)
+	unsubscribe()
+	e := <-entries
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded Entry
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	want := e.Time.Format("2006-01-02T15:04:05.000Z0700") + "\tWARN\ttest\tmessage\t{\"count\": 3, \"to\": \"replica\"}\n"
+	if got := decoded.String(); got != want {
+		t.Errorf("String() of the decoded entry = %q, want %q", got, want)
+	}
+}
+
 func TestLogs(t *testing.T) {
-	logs = []string{"log1", "log2"}
+	history = newRingBuffer(10)
+	history.add(Entry{line: "log1"})
+	history.add(Entry{line: "log2"})
 	retrieved := Logs()
-	if len(retrieved) != 2 {
-		t.Errorf("Logs() = %v, want %v", len(retrieved), 2)
+	if len(retrieved) != 2 || retrieved[1] != "log2" {
+		t.Errorf("Logs() = %v, want [log1 log2]", retrieved)
 	}
 }
 
 func TestClear(t *testing.T) {
-	logs = []string{"log1", "log2"}
+	history = newRingBuffer(10)
+	history.add(Entry{line: "log1"})
 	Clear()
-	if len(logs) != 0 {
-		t.Errorf("Clear() left logs with length %d", len(logs))
+	if len(Logs()) != 0 {
+		t.Errorf("Clear() left logs with length %d", len(Logs()))
 	}
 }
 
 func TestInit(t *testing.T) {
 	t.Setenv(envLogLevel, "debug")
 	t.Setenv(envLogFormat, "json")
+	t.Setenv(envLogHistorySize, "10")
 
 	logger, err := initRootLogger()
 	if err != nil {
@@ -156,3 +201,11 @@ func TestInit(t *testing.T) {
 		t.Fatal("initRootLogger() returned nil")
 	}
 }
+
+func TestInit_InvalidHistorySize(t *testing.T) {
+	t.Setenv(envLogHistorySize, "foo")
+
+	if _, err := initRootLogger(); err == nil {
+		t.Error("initRootLogger() error = nil, want error")
+	}
+}
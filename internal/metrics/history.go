@@ -0,0 +1,315 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+)
+
+const (
+	// DefaultHistoryRetention the default time the stats history is kept.
+	DefaultHistoryRetention = 31 * 24 * time.Hour
+
+	// HistoryResolutionHour hourly history points.
+	HistoryResolutionHour = "hour"
+	// HistoryResolutionDay daily history points.
+	HistoryResolutionDay = "day"
+
+	// maxHourlyRange the longest range reported with hourly points.
+	maxHourlyRange = 7 * 24 * time.Hour
+	// historySaveRetry the interval the history isn't saved after an error, e.g. of a read-only home directory.
+	historySaveRetry = time.Hour
+)
+
+// HistoryPoint the stats of an instance, or the sum of the instances, in an hour or day.
+type HistoryPoint struct {
+	Time                 time.Time `json:"time"`
+	DNSQueries           int       `json:"dnsQueries"`
+	BlockedFiltering     int       `json:"blockedFiltering"`
+	ReplacedSafebrowsing int       `json:"replacedSafebrowsing"`
+	ReplacedParental     int       `json:"replacedParental"`
+}
+
+func (p *HistoryPoint) add(o HistoryPoint) {
+	p.DNSQueries += o.DNSQueries
+	p.BlockedFiltering += o.BlockedFiltering
+	p.ReplacedSafebrowsing += o.ReplacedSafebrowsing
+	p.ReplacedParental += o.ReplacedParental
+}
+
+// HistorySeries the history points of an instance.
+type HistorySeries struct {
+	Instance string         `json:"instance"`
+	Points   []HistoryPoint `json:"points"`
+}
+
+// HistoryRange the aggregated history of a time range.
+type HistoryRange struct {
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	Resolution string          `json:"resolution"`
+	Total      []HistoryPoint  `json:"total"`
+	Instances  []HistorySeries `json:"instances"`
+}
+
+// History the hourly stats of the instances, kept beyond the stats interval of AdGuard Home.
+// If a file is configured, the history is persisted and survives restarts.
+type History struct {
+	mux       sync.Mutex
+	file      string
+	retention time.Duration
+	// points by instance and unix time of the hour
+	points map[string]map[int64]HistoryPoint
+	// recorded the time of the last record by instance
+	recorded map[string]time.Time
+	// nextSave the time to save the history again after an error
+	nextSave time.Time
+	saveErr  bool
+}
+
+// historyFile the persisted history.
+type historyFile struct {
+	Instances map[string][]HistoryPoint `json:"instances"`
+}
+
+// DefaultHistoryFile returns the file the history is persisted to if none is configured,
+// '.adguardhome-sync-history.json' in the home directory, or empty if there is no home directory.
+func DefaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".adguardhome-sync-history.json")
+}
+
+// NewHistory creates the history and loads the points persisted in the file, if any.
+func NewHistory(file string, retention time.Duration) (*History, error) {
+	if retention <= 0 {
+		retention = DefaultHistoryRetention
+	}
+	h := &History{
+		file:      file,
+		retention: retention,
+		points:    make(map[string]map[int64]HistoryPoint),
+		recorded:  make(map[string]time.Time),
+	}
+	if file == "" {
+		return h, nil
+	}
+
+	b, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var hf historyFile
+	if err := json.Unmarshal(b, &hf); err != nil {
+		return nil, err
+	}
+	for inst, points := range hf.Instances {
+		h.points[inst] = make(map[int64]HistoryPoint, len(points))
+		for _, p := range points {
+			h.points[inst][p.Time.Unix()] = p
+		}
+	}
+	l.With("file", file, "instances", len(h.points)).Info("Loaded stats history")
+	return h, nil
+}
+
+// Record stores the hourly stats of the successfully scraped instances and persists the history.
+// Only the hours still counting at the previous record are replaced with the current values of AdGuard Home,
+// the other ones are added if missing, so a reset of the stats of AdGuard Home doesn't erase the recorded hours.
+// Instances reporting daily stats are recorded at the start of the day.
+func (h *History) Record(iml InstanceMetricsList, now time.Time) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	for _, im := range iml.Metrics {
+		if im.Err != nil || im.Stats == nil {
+			continue
+		}
+		points := h.points[im.HostName]
+		if points == nil {
+			points = make(map[int64]HistoryPoint)
+			h.points[im.HostName] = points
+		}
+		recordStats(points, im.Stats, now, h.recorded[im.HostName])
+		h.recorded[im.HostName] = now
+	}
+	h.prune(now)
+	h.trySave(now)
+}
+
+// trySave saves the history. An error is logged once, then the history is kept in memory only
+// and saving is retried hourly.
+func (h *History) trySave(now time.Time) {
+	if now.Before(h.nextSave) {
+		return
+	}
+	if err := h.save(); err != nil {
+		if !h.saveErr {
+			l.With("error", err, "file", h.file, "retry", historySaveRetry).
+				Warn("Error saving the stats history, keeping it in memory only")
+		}
+		h.saveErr = true
+		h.nextSave = now.Add(historySaveRetry)
+		return
+	}
+	if h.saveErr {
+		l.With("file", h.file).Info("Saved the stats history again")
+	}
+	h.saveErr = false
+	h.nextSave = time.Time{}
+}
+
+// recordStats records the stats. Recorded points are kept if they were already closed at the previous record,
+// or, without a previous record, if they are not the current one.
+func recordStats(points map[int64]HistoryPoint, s *model.Stats, now, previous time.Time) {
+	last := now.Truncate(time.Hour)
+	step := func(i int) time.Time { return last.Add(-time.Duration(i) * time.Hour) }
+	open := previous.Truncate(time.Hour)
+	if s.TimeUnits != nil && *s.TimeUnits == model.Days {
+		last = startOfDay(now)
+		step = func(i int) time.Time { return last.AddDate(0, 0, -i) }
+		open = startOfDay(previous)
+	}
+	if previous.IsZero() {
+		open = last
+	}
+	closed := make(map[int64]bool)
+	for t, p := range points {
+		if p.Time.Before(open) {
+			closed[t] = true
+		}
+	}
+
+	set := func(values *[]int, field func(p *HistoryPoint) *int) {
+		data := safeStats(values)
+		for i, v := range data {
+			t := step(len(data) - 1 - i)
+			if closed[t.Unix()] {
+				continue
+			}
+			p := points[t.Unix()]
+			p.Time = t
+			*field(&p) = v
+			points[t.Unix()] = p
+		}
+	}
+	set(s.DnsQueries, func(p *HistoryPoint) *int { return &p.DNSQueries })
+	set(s.BlockedFiltering, func(p *HistoryPoint) *int { return &p.BlockedFiltering })
+	set(s.ReplacedSafebrowsing, func(p *HistoryPoint) *int { return &p.ReplacedSafebrowsing })
+	set(s.ReplacedParental, func(p *HistoryPoint) *int { return &p.ReplacedParental })
+}
+
+// prune removes the points older than the retention.
+func (h *History) prune(now time.Time) {
+	oldest := now.Add(-h.retention).Unix()
+	for _, points := range h.points {
+		maps.DeleteFunc(points, func(t int64, _ HistoryPoint) bool { return t < oldest })
+	}
+}
+
+// save writes the history to a temporary file and replaces the file with it.
+func (h *History) save() error {
+	if h.file == "" {
+		return nil
+	}
+	hf := historyFile{Instances: make(map[string][]HistoryPoint, len(h.points))}
+	for inst, points := range h.points {
+		hf.Instances[inst] = slices.SortedFunc(maps.Values(points), func(a, b HistoryPoint) int {
+			return a.Time.Compare(b.Time)
+		})
+	}
+	b, err := json.Marshal(hf)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(h.file), filepath.Base(h.file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), h.file)
+}
+
+// Query aggregates the history from to to, of all instances or of the given one. Ranges up to 7 days
+// are reported hourly, longer ones daily. The range is limited to the retention.
+func (h *History) Query(from, to time.Time, instance string) HistoryRange {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if to.Sub(from) > h.retention {
+		from = to.Add(-h.retention)
+	}
+	hr := HistoryRange{From: from, To: to, Resolution: HistoryResolutionHour, Instances: []HistorySeries{}}
+	start := from.Truncate(time.Hour)
+	next := func(t time.Time) time.Time { return t.Add(time.Hour) }
+	if to.Sub(from) > maxHourlyRange {
+		hr.Resolution = HistoryResolutionDay
+		start = startOfDay(from)
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	}
+
+	// the start of the buckets; the points of an instance are summed up in the bucket containing their time
+	var buckets []time.Time
+	for t := start; !t.After(to); t = next(t) {
+		buckets = append(buckets, t)
+	}
+	newPoints := func() []HistoryPoint {
+		points := make([]HistoryPoint, len(buckets))
+		for i, t := range buckets {
+			points[i].Time = t
+		}
+		return points
+	}
+
+	hr.Total = newPoints()
+	for _, inst := range slices.Sorted(maps.Keys(h.points)) {
+		if instance != "" && inst != instance {
+			continue
+		}
+		series := HistorySeries{Instance: inst, Points: newPoints()}
+		for _, p := range h.points[inst] {
+			if p.Time.Before(start) || p.Time.After(to) {
+				continue
+			}
+			i, ok := slices.BinarySearchFunc(buckets, p.Time, func(b, t time.Time) int {
+				switch {
+				case b.After(t):
+					return 1
+				case next(b).After(t):
+					return 0
+				default:
+					return -1
+				}
+			})
+			if ok {
+				series.Points[i].add(p)
+				hr.Total[i].add(p)
+			}
+		}
+		hr.Instances = append(hr.Instances, series)
+	}
+	return hr
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,168 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+)
+
+func TestHistory(t *testing.T) {
+	now := time.Date(2026, 10, 19, 14, 30, 0, 0, time.UTC)
+	hour := now.Truncate(time.Hour)
+	file := filepath.Join(t.TempDir(), "history.json")
+
+	h, err := NewHistory(file, 0)
+	if err != nil {
+		t.Fatalf("NewHistory() error = %v", err)
+	}
+	h.Record(InstanceMetricsList{Metrics: []InstanceMetrics{
+		{HostName: "a", Stats: &model.Stats{DnsQueries: &[]int{1, 2, 3}, BlockedFiltering: &[]int{0, 1, 1}}},
+		{HostName: "b", Stats: &model.Stats{DnsQueries: &[]int{10}}},
+		{HostName: "failed", Err: os.ErrDeadlineExceeded},
+	}}, now)
+	// a later scrape replaces the values of the current hour only
+	h.Record(InstanceMetricsList{Metrics: []InstanceMetrics{
+		{HostName: "a", Stats: &model.Stats{DnsQueries: &[]int{0, 0, 5}, BlockedFiltering: &[]int{0, 0, 2}}},
+	}}, now.Add(10*time.Minute))
+
+	t.Run("should report hourly points", func(t *testing.T) {
+		hr := h.Query(now.Add(-3*time.Hour), now, "")
+		if hr.Resolution != HistoryResolutionHour || len(hr.Total) != 4 || len(hr.Instances) != 2 {
+			t.Fatalf("Query() = %+v, want 4 hourly points of 2 instances", hr)
+		}
+		want := []HistoryPoint{
+			{Time: hour.Add(-3 * time.Hour)},
+			{Time: hour.Add(-2 * time.Hour), DNSQueries: 1},
+			{Time: hour.Add(-time.Hour), DNSQueries: 2, BlockedFiltering: 1},
+			{Time: hour, DNSQueries: 15, BlockedFiltering: 2},
+		}
+		for i, p := range hr.Total {
+			if !p.Time.Equal(want[i].Time) || p.DNSQueries != want[i].DNSQueries || p.BlockedFiltering != want[i].BlockedFiltering {
+				t.Errorf("Total[%d] = %+v, want %+v", i, p, want[i])
+			}
+		}
+	})
+	t.Run("should report the selected instance", func(t *testing.T) {
+		hr := h.Query(now.Add(-3*time.Hour), now, "b")
+		if len(hr.Instances) != 1 || hr.Instances[0].Instance != "b" || hr.Total[3].DNSQueries != 10 {
+			t.Errorf("Query() = %+v, want the points of b", hr)
+		}
+	})
+	t.Run("should report daily points of long ranges", func(t *testing.T) {
+		hr := h.Query(now.Add(-10*24*time.Hour), now, "")
+		if hr.Resolution != HistoryResolutionDay || len(hr.Total) != 11 {
+			t.Fatalf("Query() = %+v, want 11 daily points", hr)
+		}
+		if last := hr.Total[10]; !last.Time.Equal(startOfDay(now)) || last.DNSQueries != 18 {
+			t.Errorf("Total[10] = %+v, want the sum of the day", last)
+		}
+	})
+	t.Run("should load the persisted history", func(t *testing.T) {
+		loaded, err := NewHistory(file, 0)
+		if err != nil {
+			t.Fatalf("NewHistory() error = %v", err)
+		}
+		if hr := loaded.Query(now.Add(-3*time.Hour), now, ""); hr.Total[3].DNSQueries != 15 {
+			t.Errorf("loaded Total[3] = %+v, want 15 DNS queries", hr.Total[3])
+		}
+	})
+	t.Run("should replace the hour still counting at the previous record", func(t *testing.T) {
+		h.Record(InstanceMetricsList{Metrics: []InstanceMetrics{
+			{HostName: "a", Stats: &model.Stats{DnsQueries: &[]int{0, 0, 7, 1}}},
+		}}, now.Add(40*time.Minute))
+		hr := h.Query(now.Add(-3*time.Hour), now.Add(time.Hour), "a")
+		want := []int{0, 1, 2, 7, 1}
+		for i, p := range hr.Total {
+			if p.DNSQueries != want[i] {
+				t.Errorf("Total[%d] = %d DNS queries, want %d", i, p.DNSQueries, want[i])
+			}
+		}
+	})
+	t.Run("should not replace the recorded hours after a restart", func(t *testing.T) {
+		loaded, err := NewHistory(file, 0)
+		if err != nil {
+			t.Fatalf("NewHistory() error = %v", err)
+		}
+		loaded.Record(InstanceMetricsList{Metrics: []InstanceMetrics{
+			{HostName: "a", Stats: &model.Stats{DnsQueries: &[]int{0, 0, 0, 0, 3}}},
+		}}, now.Add(time.Hour))
+		hr := loaded.Query(now.Add(-3*time.Hour), now.Add(time.Hour), "a")
+		want := []int{0, 1, 2, 7, 3}
+		for i, p := range hr.Total {
+			if p.DNSQueries != want[i] {
+				t.Errorf("Total[%d] = %d DNS queries, want %d", i, p.DNSQueries, want[i])
+			}
+		}
+	})
+	t.Run("should remove points older than the retention", func(t *testing.T) {
+		h.Record(InstanceMetricsList{}, now.Add(DefaultHistoryRetention))
+		if hr := h.Query(now.Add(-3*time.Hour), now, ""); hr.Total[3].DNSQueries != 0 {
+			t.Errorf("Total[3] = %+v, want the points removed", hr.Total[3])
+		}
+	})
+}
+
+func TestHistory_daily(t *testing.T) {
+	now := time.Date(2026, 10, 19, 14, 30, 0, 0, time.UTC)
+	h, err := NewHistory("", 0)
+	if err != nil {
+		t.Fatalf("NewHistory() error = %v", err)
+	}
+	h.Record(InstanceMetricsList{Metrics: []InstanceMetrics{
+		{HostName: "a", Stats: &model.Stats{TimeUnits: new(model.Days), DnsQueries: &[]int{7, 8}}},
+	}}, now)
+
+	hr := h.Query(now.Add(-10*24*time.Hour), now, "")
+	if got := hr.Total[9]; !got.Time.Equal(startOfDay(now).AddDate(0, 0, -1)) || got.DNSQueries != 7 {
+		t.Errorf("Total[9] = %+v, want 7 DNS queries of yesterday", got)
+	}
+	if got := hr.Total[10]; got.DNSQueries != 8 {
+		t.Errorf("Total[10] = %+v, want 8 DNS queries of today", got)
+	}
+}
+
+func TestHistory_saveError(t *testing.T) {
+	now := time.Date(2026, 10, 19, 14, 30, 0, 0, time.UTC)
+	dir := filepath.Join(t.TempDir(), "missing")
+	file := filepath.Join(dir, "history.json")
+	h, err := NewHistory(file, 0)
+	if err != nil {
+		t.Fatalf("NewHistory() error = %v", err)
+	}
+	iml := InstanceMetricsList{Metrics: []InstanceMetrics{{HostName: "a", Stats: &model.Stats{DnsQueries: &[]int{1}}}}}
+
+	h.Record(iml, now)
+	if !h.saveErr || !h.nextSave.Equal(now.Add(historySaveRetry)) {
+		t.Fatalf("saveErr = %t, nextSave = %v, want a retry in %v", h.saveErr, h.nextSave, historySaveRetry)
+	}
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatalf("Mkdir() error = %v", err)
+	}
+
+	t.Run("should not save until the retry", func(t *testing.T) {
+		h.Record(iml, now.Add(time.Minute))
+		if _, err := os.Stat(file); !os.IsNotExist(err) {
+			t.Errorf("Stat() error = %v, want the file not to exist", err)
+		}
+	})
+	t.Run("should save again after the retry", func(t *testing.T) {
+		h.Record(iml, now.Add(historySaveRetry))
+		if _, err := os.Stat(file); err != nil {
+			t.Errorf("Stat() error = %v, want the file to exist", err)
+		}
+		if h.saveErr || !h.nextSave.IsZero() {
+			t.Errorf("saveErr = %t, nextSave = %v, want reset", h.saveErr, h.nextSave)
+		}
+	})
+}
+
+func TestDefaultHistoryFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if got, want := DefaultHistoryFile(), filepath.Join(home, ".adguardhome-sync-history.json"); got != want {
+		t.Errorf("DefaultHistoryFile() = %q, want %q", got, want)
+	}
+}
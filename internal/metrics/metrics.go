@@ -1,13 +1,32 @@
 package metrics
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/net/publicsuffix"
 
 	"github.com/bakito/adguardhome-sync/internal/client/model"
 	"github.com/bakito/adguardhome-sync/internal/log"
+	"github.com/bakito/adguardhome-sync/internal/types"
 )
 
-const StatsTotal = "total"
+const (
+	StatsTotal = "total"
+	// otherLabel the label value counting the query log clients and upstreams beyond the label limit.
+	otherLabel = "other"
+	// defaultQueryLogLabelLimit the maximum number of query log clients and upstreams with their own series per
+	// instance, as the counters are never reset.
+	defaultQueryLogLabelLimit = 100
+)
 
 var (
 	l = log.GetLogger("metrics")
@@ -102,16 +121,70 @@ var (
 		[]string{"hostname", "client"},
 	)
 
-	// queryTypes - The type of DNS Queries (A, AAAA...)
-	queryTypes = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name:      "query_types",
+	// queryTypes - The answer types of the DNS queries (A, AAAA...)
+	queryTypes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:      "querylog_answer_types_total",
 			Namespace: "adguard",
-			Help:      "This represent the DNS query types",
+			Help:      "This represent the number of DNS answers by type in the query log",
 		},
 		[]string{"hostname", "type"},
 	)
 
+	// queryLogQueries - The queries by client.
+	queryLogQueries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:      "querylog_queries_total",
+			Namespace: "adguard",
+			Help:      "This represent the number of queries by client in the query log",
+		},
+		[]string{"hostname", "client"},
+	)
+
+	// queryLogBlocked - The blocked queries by reason.
+	queryLogBlocked = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:      "querylog_blocked_total",
+			Namespace: "adguard",
+			Help:      "This represent the number of blocked queries by reason in the query log",
+		},
+		[]string{"hostname", "reason"},
+	)
+
+	// queryLogUpstreams - The responses by upstream.
+	queryLogUpstreams = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:      "querylog_upstream_responses_total",
+			Namespace: "adguard",
+			Help:      "This represent the number of responses by upstream in the query log",
+		},
+		[]string{"hostname", "upstream"},
+	)
+
+	// queryLogCached - The responses served from the cache.
+	queryLogCached = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:      "querylog_cached_total",
+			Namespace: "adguard",
+			Help:      "This represent the number of responses served from the cache in the query log",
+		},
+		[]string{"hostname"},
+	)
+
+	// queryLogElapsed - The distribution of the query processing times.
+	queryLogElapsed = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:      "querylog_elapsed_seconds",
+			Namespace: "adguard",
+			Help:      "This represent the distribution of the query processing times in the query log in s",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 4, 8),
+		},
+		[]string{"hostname"},
+	)
+
+	// queryLogMetrics the counters of the query log entries, kept while an instance is scraped.
+	queryLogMetrics = []deletable{queryTypes, queryLogQueries, queryLogBlocked, queryLogUpstreams, queryLogCached, queryLogElapsed}
+
 	// running - If Adguard is running.
 	running = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -131,6 +204,154 @@ var (
 		},
 		[]string{"hostname"},
 	)
+	// upstreamResponses - Number of responses by upstream.
+	upstreamResponses = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "upstream_responses",
+			Namespace: "adguard",
+			Help:      "This represent the number of responses from each upstream",
+		},
+		[]string{"hostname", "upstream"},
+	)
+
+	// upstreamAvgTime - Average response time by upstream.
+	upstreamAvgTime = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "upstream_avg_response_time_seconds",
+			Namespace: "adguard",
+			Help:      "This represent the average response time of each upstream in s",
+		},
+		[]string{"hostname", "upstream"},
+	)
+
+	// filteringEnabled - If filtering is enabled.
+	filteringEnabled = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "filtering_enabled",
+			Namespace: "adguard",
+			Help:      "This represent if filtering is enabled",
+		},
+		[]string{"hostname"},
+	)
+
+	// filterListEnabled - If a filter list is enabled.
+	filterListEnabled = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "filter_list_enabled",
+			Namespace: "adguard",
+			Help:      "This represent if a filter list is enabled",
+		},
+		[]string{"hostname", "list", "name", "url"},
+	)
+
+	// filterListRules - Number of rules by filter list.
+	filterListRules = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "filter_list_rules",
+			Namespace: "adguard",
+			Help:      "This represent the number of rules of a filter list",
+		},
+		[]string{"hostname", "list", "name", "url"},
+	)
+
+	// filterListUpdateAge - Age of the last update by filter list.
+	filterListUpdateAge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "filter_list_last_update_age_seconds",
+			Namespace: "adguard",
+			Help:      "This represent the time since the last update of a filter list in s",
+		},
+		[]string{"hostname", "list", "name", "url"},
+	)
+
+	// persistentClients - Number of persistent clients.
+	persistentClients = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "persistent_clients",
+			Namespace: "adguard",
+			Help:      "This represent the number of persistent clients",
+		},
+		[]string{"hostname"},
+	)
+
+	// rewrites - Number of DNS rewrites.
+	rewrites = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "rewrites",
+			Namespace: "adguard",
+			Help:      "This represent the number of DNS rewrites",
+		},
+		[]string{"hostname"},
+	)
+
+	// dhcpEnabled - If the DHCP server is enabled.
+	dhcpEnabled = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "dhcp_enabled",
+			Namespace: "adguard",
+			Help:      "This represent if the DHCP server is enabled",
+		},
+		[]string{"hostname"},
+	)
+
+	// dhcpStaticLeases - Number of DHCP static leases.
+	dhcpStaticLeases = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "dhcp_static_leases",
+			Namespace: "adguard",
+			Help:      "This represent the number of DHCP static leases",
+		},
+		[]string{"hostname"},
+	)
+
+	// dhcpLeases - Number of DHCP leases.
+	dhcpLeases = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "dhcp_leases",
+			Namespace: "adguard",
+			Help:      "This represent the number of dynamic DHCP leases",
+		},
+		[]string{"hostname"},
+	)
+
+	// scrapeSuccess - If the last scrape of the instance was successful.
+	scrapeSuccess = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "scrape_success",
+			Namespace: "adguard",
+			Help:      "This represent if the last scrape of the instance was successful",
+		},
+		[]string{"hostname"},
+	)
+
+	// scrapeDuration - The duration of the last scrape.
+	scrapeDuration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "scrape_duration_seconds",
+			Namespace: "adguard",
+			Help:      "This represent the duration of the last scrape of the instance in seconds",
+		},
+		[]string{"hostname"},
+	)
+
+	// lastScrape - The time of the last successful scrape.
+	lastScrape = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "last_scrape_timestamp_seconds",
+			Namespace: "adguard",
+			Help:      "This represent the unix time of the last successful scrape of the instance",
+		},
+		[]string{"hostname"},
+	)
+
+	// instanceMetrics the metrics updated from the scraped values of an instance.
+	instanceMetrics = []*prometheus.GaugeVec{
+		avgProcessingTime, dnsQueries, blockedFiltering, parentalFiltering, safeBrowsingFiltering,
+		safeSearchFiltering, topQueries, topBlocked, topClients, running, protectionEnabled,
+		upstreamResponses, upstreamAvgTime, filteringEnabled, filterListEnabled, filterListRules, filterListUpdateAge,
+		persistentClients, rewrites, dhcpEnabled, dhcpStaticLeases, dhcpLeases,
+	}
+
 	// aghsSyncDuration - the sync curation in seconds.
 	aghsSyncDuration = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -149,41 +370,259 @@ var (
 		},
 		[]string{"hostname"},
 	)
-	stats = OverallStats{}
+	// aghsSyncRuns - the number of syncs to a replica.
+	aghsSyncRuns = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:      "sync_runs_total",
+			Namespace: "adguard_home_sync",
+			Help:      "This represents the number of syncs to a replica",
+		},
+		[]string{"hostname"},
+	)
+	// aghsSyncFailures - the number of failed syncs to a replica.
+	aghsSyncFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:      "sync_failures_total",
+			Namespace: "adguard_home_sync",
+			Help:      "This represents the number of failed syncs to a replica",
+		},
+		[]string{"hostname"},
+	)
+	// aghsSyncRunDuration - the distribution of the sync durations.
+	aghsSyncRunDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:      "sync_run_duration_seconds",
+			Namespace: "adguard_home_sync",
+			Help:      "This represents the distribution of the sync durations to a replica in seconds",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 10),
+		},
+		[]string{"hostname"},
+	)
+	// aghsSyncLastSuccess - the time of the last successful sync.
+	aghsSyncLastSuccess = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      "sync_last_success_timestamp_seconds",
+			Namespace: "adguard_home_sync",
+			Help:      "This represents the unix time of the last successful sync to a replica",
+		},
+		[]string{"hostname"},
+	)
+	// aghsActionRuns - the number of sync action runs.
+	aghsActionRuns = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:      "sync_action_runs_total",
+			Namespace: "adguard_home_sync",
+			Help:      "This represents the number of runs of a sync action",
+		},
+		[]string{"hostname", "action"},
+	)
+	// aghsActionFailures - the number of failed sync action runs.
+	aghsActionFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:      "sync_action_failures_total",
+			Namespace: "adguard_home_sync",
+			Help:      "This represents the number of failed runs of a sync action",
+		},
+		[]string{"hostname", "action"},
+	)
+	// aghsActionDuration - the distribution of the sync action durations.
+	aghsActionDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:      "sync_action_duration_seconds",
+			Namespace: "adguard_home_sync",
+			Help:      "This represents the distribution of the sync action durations in seconds",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"hostname", "action"},
+	)
+	// aghsEntityChanges - the number of entities changed by the sync actions.
+	aghsEntityChanges = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:      "sync_entity_changes_total",
+			Namespace: "adguard_home_sync",
+			Help:      "This represents the number of entities added, updated or removed by a sync action",
+		},
+		[]string{"hostname", "action", "operation"},
+	)
+	// aghsNextSync - the time of the next cron sync.
+	aghsNextSync = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name:      "sync_next_run_timestamp_seconds",
+			Namespace: "adguard_home_sync",
+			Help:      "This represents the unix time of the next sync scheduled by the cron expression",
+		},
+	)
+	errUnknownMetric = errors.New("unknown metric")
+
+	// renamedMetrics the former names of renamed metrics, still accepted as disabled metric.
+	renamedMetrics = map[string]string{
+		"query_types": "querylog_answer_types_total",
+	}
+
+	// topLimit the maximum number of top domains and clients per instance, all if 0.
+	topLimit int
+	// domainLabel the label value of the top domains.
+	domainLabel string
+	// queryLogLabelLimit the maximum number of query log clients and upstreams per instance.
+	queryLogLabelLimit = defaultQueryLogLabelLimit
+	// queryLogClients and queryLogUpstreamNames the label values of the query log counters by instance.
+	queryLogClients       = labelLimit{}
+	queryLogUpstreamNames = labelLimit{}
+
+	stats        = OverallStats{}
+	statsMux     sync.RWMutex
+	scrapedHosts = make(map[string]bool)
 )
 
-// Init initializes all Prometheus metrics made available by AdGuard  exporter.
-func Init() {
-	initMetric("avg_processing_time", avgProcessingTime)
-	initMetric("num_dns_queries", dnsQueries)
-	initMetric("num_blocked_filtering", blockedFiltering)
-	initMetric("num_replaced_parental", parentalFiltering)
-	initMetric("num_replaced_safebrowsing", safeBrowsingFiltering)
-	initMetric("num_replaced_safesearch", safeSearchFiltering)
-	initMetric("top_queried_domains", topQueries)
-	initMetric("top_blocked_domains", topBlocked)
-	initMetric("top_clients", topClients)
-	initMetric("query_types", queryTypes)
-	initMetric("running", running)
-	initMetric("protection_enabled", protectionEnabled)
-	initMetric("sync_duration_seconds", aghsSyncDuration)
-	initMetric("sync_successful", aghsSyncSuccessful)
+// deletable a metric vector whose series can be deleted.
+type deletable interface {
+	DeletePartialMatch(labels prometheus.Labels) int
 }
 
-func initMetric(name string, metric *prometheus.GaugeVec) {
+// family a metric family that can be disabled by its name.
+type family struct {
+	name   string
+	metric prometheus.Collector
+}
+
+// families all Prometheus metrics made available by the AdGuard exporter and the sync.
+func families() []family {
+	return []family{
+		{"avg_processing_time", avgProcessingTime},
+		{"num_dns_queries", dnsQueries},
+		{"num_blocked_filtering", blockedFiltering},
+		{"num_replaced_parental", parentalFiltering},
+		{"num_replaced_safebrowsing", safeBrowsingFiltering},
+		{"num_replaced_safesearch", safeSearchFiltering},
+		{"top_queried_domains", topQueries},
+		{"top_blocked_domains", topBlocked},
+		{"top_clients", topClients},
+		{"querylog_answer_types_total", queryTypes},
+		{"querylog_queries_total", queryLogQueries},
+		{"querylog_blocked_total", queryLogBlocked},
+		{"querylog_upstream_responses_total", queryLogUpstreams},
+		{"querylog_cached_total", queryLogCached},
+		{"querylog_elapsed_seconds", queryLogElapsed},
+		{"running", running},
+		{"protection_enabled", protectionEnabled},
+		{"upstream_responses", upstreamResponses},
+		{"upstream_avg_response_time_seconds", upstreamAvgTime},
+		{"filtering_enabled", filteringEnabled},
+		{"filter_list_enabled", filterListEnabled},
+		{"filter_list_rules", filterListRules},
+		{"filter_list_last_update_age_seconds", filterListUpdateAge},
+		{"persistent_clients", persistentClients},
+		{"rewrites", rewrites},
+		{"dhcp_enabled", dhcpEnabled},
+		{"dhcp_static_leases", dhcpStaticLeases},
+		{"dhcp_leases", dhcpLeases},
+		{"scrape_success", scrapeSuccess},
+		{"scrape_duration_seconds", scrapeDuration},
+		{"last_scrape_timestamp_seconds", lastScrape},
+		{"sync_duration_seconds", aghsSyncDuration},
+		{"sync_successful", aghsSyncSuccessful},
+		{"sync_runs_total", aghsSyncRuns},
+		{"sync_failures_total", aghsSyncFailures},
+		{"sync_run_duration_seconds", aghsSyncRunDuration},
+		{"sync_last_success_timestamp_seconds", aghsSyncLastSuccess},
+		{"sync_action_runs_total", aghsActionRuns},
+		{"sync_action_failures_total", aghsActionFailures},
+		{"sync_action_duration_seconds", aghsActionDuration},
+		{"sync_entity_changes_total", aghsEntityChanges},
+		{"sync_next_run_timestamp_seconds", aghsNextSync},
+	}
+}
+
+// Init validates the metrics config, registers the metrics that are not disabled and sets up the push exporters.
+func Init(cfg types.Metrics) error {
+	all := families()
+	disabled, err := disabledMetrics(all, cfg.Disabled)
+	if err != nil {
+		return err
+	}
+	topLimit = cfg.TopLimit
+	domainLabel = cfg.DomainLabel
+	pushers = newPushers(cfg)
+
+	for _, f := range all {
+		if disabled[f.name] {
+			l.With("name", f.name).Info("Prometheus metric disabled")
+			continue
+		}
+		initMetric(f.name, f.metric)
+	}
+	return nil
+}
+
+// disabledMetrics returns the names of the disabled metrics; former names are mapped to the current ones.
+func disabledMetrics(all []family, names []string) (map[string]bool, error) {
+	disabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		if renamed, ok := renamedMetrics[name]; ok {
+			l.With("name", name, "newName", renamed).Warn("Disabled Prometheus metric was renamed")
+			name = renamed
+		}
+		if !slices.ContainsFunc(all, func(f family) bool { return f.name == name }) {
+			return nil, fmt.Errorf("%w %q", errUnknownMetric, name)
+		}
+		disabled[name] = true
+	}
+	return disabled, nil
+}
+
+func initMetric(name string, metric prometheus.Collector) {
 	prometheus.MustRegister(metric)
 	l.With("name", name).Info("New Prometheus metric registered")
 }
 
+// UpdateInstances updates the metrics of the scraped instances. The series of failed scrapes are removed
+// instead of keeping the last values, as are all series of instances no longer scraped.
 func UpdateInstances(iml InstanceMetricsList) {
+	statsMux.Lock()
+	defer statsMux.Unlock()
+
+	hosts := make(map[string]bool, len(iml.Metrics))
 	for _, im := range iml.Metrics {
+		hosts[im.HostName] = true
+		scrapeDuration.WithLabelValues(im.HostName).Set(im.Duration)
+		if im.Err != nil {
+			scrapeSuccess.WithLabelValues(im.HostName).Set(0)
+			removeInstance(im.HostName)
+			running.WithLabelValues(im.HostName).Set(0)
+			continue
+		}
+		scrapeSuccess.WithLabelValues(im.HostName).Set(1)
+		lastScrape.WithLabelValues(im.HostName).SetToCurrentTime()
 		updateMetrics(im)
 		stats[im.HostName] = im.Stats
 	}
 
+	for host := range scrapedHosts {
+		if !hosts[host] {
+			removeInstance(host)
+			for _, m := range []*prometheus.GaugeVec{scrapeSuccess, scrapeDuration, lastScrape} {
+				m.DeleteLabelValues(host)
+			}
+			for _, m := range queryLogMetrics {
+				m.DeletePartialMatch(prometheus.Labels{"hostname": host})
+			}
+			delete(queryLogClients, host)
+			delete(queryLogUpstreamNames, host)
+		}
+	}
+	scrapedHosts = hosts
+
 	l.Debug("updated")
 }
 
+// removeInstance removes the series and stats of the instance.
+func removeInstance(host string) {
+	for _, m := range instanceMetrics {
+		m.DeletePartialMatch(prometheus.Labels{"hostname": host})
+	}
+	delete(stats, host)
+}
+
 func UpdateResult(host string, ok bool, duration float64) {
 	if ok {
 		aghsSyncSuccessful.WithLabelValues(host).Set(1)
@@ -191,6 +630,37 @@ func UpdateResult(host string, ok bool, duration float64) {
 		aghsSyncSuccessful.WithLabelValues(host).Set(0)
 	}
 	aghsSyncDuration.WithLabelValues(host).Set(duration)
+	aghsSyncRuns.WithLabelValues(host).Inc()
+	aghsSyncRunDuration.WithLabelValues(host).Observe(duration)
+	if ok {
+		aghsSyncLastSuccess.WithLabelValues(host).SetToCurrentTime()
+	} else {
+		aghsSyncFailures.WithLabelValues(host).Inc()
+	}
+}
+
+// EntityChanges the number of entities a sync action changed on a replica.
+type EntityChanges struct {
+	Added   int
+	Updated int
+	Removed int
+}
+
+// UpdateActionResult records a run of a sync action to a replica.
+func UpdateActionResult(host, action string, ok bool, duration float64, changes EntityChanges) {
+	aghsActionRuns.WithLabelValues(host, action).Inc()
+	aghsActionDuration.WithLabelValues(host, action).Observe(duration)
+	if !ok {
+		aghsActionFailures.WithLabelValues(host, action).Inc()
+	}
+	aghsEntityChanges.WithLabelValues(host, action, "added").Add(float64(changes.Added))
+	aghsEntityChanges.WithLabelValues(host, action, "updated").Add(float64(changes.Updated))
+	aghsEntityChanges.WithLabelValues(host, action, "removed").Add(float64(changes.Removed))
+}
+
+// UpdateNextSync records the time of the next sync scheduled by the cron expression.
+func UpdateNextSync(next time.Time) {
+	aghsNextSync.Set(float64(next.Unix()))
 }
 
 func updateMetrics(im InstanceMetrics) {
@@ -215,48 +685,193 @@ func updateMetrics(im InstanceMetrics) {
 	safeBrowsingFiltering.WithLabelValues(im.HostName).Set(safeMetric(im.Stats.NumReplacedSafebrowsing))
 	safeSearchFiltering.WithLabelValues(im.HostName).Set(safeMetric(im.Stats.NumReplacedSafesearch))
 
-	if im.Stats.TopQueriedDomains != nil {
-		for _, tq := range *im.Stats.TopQueriedDomains {
-			for domain, value := range tq.AdditionalProperties {
-				topQueries.WithLabelValues(im.HostName, domain).Set(float64(value))
+	setTopValues(topQueries, im.HostName, im.Stats.TopQueriedDomains, domainLabelValue)
+	setTopValues(topBlocked, im.HostName, im.Stats.TopBlockedDomains, domainLabelValue)
+	setTopValues(topClients, im.HostName, im.Stats.TopClients, func(client string) string { return client })
+
+	updateQueryLogMetrics(im)
+	updateUpstreamMetrics(im)
+	updateFilteringMetrics(im)
+	updateSettingsMetrics(im)
+}
+
+// updateQueryLogMetrics counts the new query log entries.
+func updateQueryLogMetrics(im InstanceMetrics) {
+	for _, e := range im.QueryLog {
+		if e.Answer != nil {
+			for _, a := range *e.Answer {
+				if a.Type != nil {
+					queryTypes.WithLabelValues(im.HostName, *a.Type).Inc()
+				}
 			}
 		}
-	}
-	if im.Stats.TopBlockedDomains != nil {
-		for _, tb := range *im.Stats.TopBlockedDomains {
-			for domain, value := range tb.AdditionalProperties {
-				topBlocked.WithLabelValues(im.HostName, domain).Set(float64(value))
+		if e.Client != nil {
+			queryLogQueries.WithLabelValues(im.HostName, queryLogClients.value(im.HostName, *e.Client)).Inc()
+		}
+		if e.Reason != nil && strings.HasPrefix(string(*e.Reason), "Filtered") {
+			queryLogBlocked.WithLabelValues(im.HostName, string(*e.Reason)).Inc()
+		}
+		if e.Upstream != nil && *e.Upstream != "" {
+			queryLogUpstreams.WithLabelValues(im.HostName, queryLogUpstreamNames.value(im.HostName, *e.Upstream)).Inc()
+		}
+		if e.Cached != nil && *e.Cached {
+			queryLogCached.WithLabelValues(im.HostName).Inc()
+		}
+		if e.ElapsedMs != nil {
+			if ms, err := strconv.ParseFloat(*e.ElapsedMs, 64); err == nil {
+				queryLogElapsed.WithLabelValues(im.HostName).Observe(ms / 1000)
 			}
 		}
 	}
-	if im.Stats.TopClients != nil {
-		for _, tc := range *im.Stats.TopClients {
-			for source, value := range tc.AdditionalProperties {
-				topClients.WithLabelValues(im.HostName, source).Set(float64(value))
-			}
+}
+
+// labelLimit the label values of a counter by instance. Counters can't be replaced like the top lists,
+// so the first values up to the label limit keep their own series and the later ones are counted as 'other'.
+type labelLimit map[string]map[string]bool
+
+// value returns the label value to count the value of the instance with.
+func (ll labelLimit) value(host, value string) string {
+	values := ll[host]
+	if values == nil {
+		values = make(map[string]bool)
+		ll[host] = values
+	}
+	if values[value] {
+		return value
+	}
+	if len(values) >= labelLimitValue() {
+		return otherLabel
+	}
+	values[value] = true
+	return value
+}
+
+// labelLimitValue returns the top limit if lower than the query log label limit, which applies even if all top
+// values are reported.
+func labelLimitValue() int {
+	if topLimit > 0 && topLimit < queryLogLabelLimit {
+		return topLimit
+	}
+	return queryLogLabelLimit
+}
+
+// updateUpstreamMetrics replaces the series of the upstreams reported in the stats.
+func updateUpstreamMetrics(im InstanceMetrics) {
+	labels := prometheus.Labels{"hostname": im.HostName}
+	upstreamResponses.DeletePartialMatch(labels)
+	upstreamAvgTime.DeletePartialMatch(labels)
+	for _, e := range safeEntries(im.Stats.TopUpstreamsResponses) {
+		for upstream, value := range e.AdditionalProperties {
+			upstreamResponses.WithLabelValues(im.HostName, upstream).Set(float64(value))
 		}
 	}
+	for _, e := range safeEntries(im.Stats.TopUpstreamsAvgTime) {
+		for upstream, value := range e.AdditionalProperties {
+			upstreamAvgTime.WithLabelValues(im.HostName, upstream).Set(float64(value))
+		}
+	}
+}
 
-	// LogQuery
-	m := make(map[string]int)
-	if im.QueryLog != nil && im.QueryLog.Data != nil {
-		logdata := *im.QueryLog.Data
-		for _, ld := range logdata {
-			if ld.Answer != nil {
-				dnsanswer := *ld.Answer
-				if len(dnsanswer) > 0 {
-					for _, dnsa := range dnsanswer {
-						dnsType := *dnsa.Type
-						m[dnsType]++
-					}
-				}
+// updateFilteringMetrics replaces the series of the filter lists; they are removed if the filtering is unknown.
+func updateFilteringMetrics(im InstanceMetrics) {
+	labels := prometheus.Labels{"hostname": im.HostName}
+	for _, m := range []*prometheus.GaugeVec{filteringEnabled, filterListEnabled, filterListRules, filterListUpdateAge} {
+		m.DeletePartialMatch(labels)
+	}
+	if im.Filtering == nil {
+		return
+	}
+	filteringEnabled.WithLabelValues(im.HostName).Set(boolMetric(im.Filtering.Enabled != nil && *im.Filtering.Enabled))
+	for list, filters := range map[string]*[]model.Filter{
+		"blocklist": im.Filtering.Filters,
+		"allowlist": im.Filtering.WhitelistFilters,
+	} {
+		if filters == nil {
+			continue
+		}
+		for _, f := range *filters {
+			filterListEnabled.WithLabelValues(im.HostName, list, f.Name, f.Url).Set(boolMetric(f.Enabled))
+			filterListRules.WithLabelValues(im.HostName, list, f.Name, f.Url).Set(float64(f.RulesCount))
+			if f.LastUpdated != nil {
+				filterListUpdateAge.WithLabelValues(im.HostName, list, f.Name, f.Url).
+					Set(time.Since(*f.LastUpdated).Seconds())
 			}
 		}
 	}
+}
+
+// updateSettingsMetrics updates the counts of the configured entities; unknown counts are removed.
+func updateSettingsMetrics(im InstanceMetrics) {
+	for _, m := range []*prometheus.GaugeVec{persistentClients, rewrites, dhcpEnabled, dhcpStaticLeases, dhcpLeases} {
+		m.DeleteLabelValues(im.HostName)
+	}
+	if im.Clients != nil {
+		n := 0
+		if im.Clients.Clients != nil {
+			n = len(*im.Clients.Clients)
+		}
+		persistentClients.WithLabelValues(im.HostName).Set(float64(n))
+	}
+	if im.RewriteEntries != nil {
+		rewrites.WithLabelValues(im.HostName).Set(float64(len(*im.RewriteEntries)))
+	}
+	if im.Dhcp != nil {
+		dhcpEnabled.WithLabelValues(im.HostName).Set(boolMetric(im.Dhcp.Enabled != nil && *im.Dhcp.Enabled))
+		n := 0
+		if im.Dhcp.StaticLeases != nil {
+			n = len(*im.Dhcp.StaticLeases)
+		}
+		dhcpStaticLeases.WithLabelValues(im.HostName).Set(float64(n))
+		dhcpLeases.WithLabelValues(im.HostName).Set(float64(len(im.Dhcp.Leases)))
+	}
+}
+
+// setTopValues replaces the series of the instance with the first entries up to the top limit.
+// Entries with the same label value are summed up.
+func setTopValues(m *prometheus.GaugeVec, host string, entries *[]model.TopArrayEntry, label func(string) string) {
+	values := make(map[string]float64)
+	for i, e := range safeEntries(entries) {
+		if topLimit > 0 && i >= topLimit {
+			break
+		}
+		for key, value := range e.AdditionalProperties {
+			values[label(key)] += float64(value)
+		}
+	}
+	m.DeletePartialMatch(prometheus.Labels{"hostname": host})
+	for key, value := range values {
+		m.WithLabelValues(host, key).Set(value)
+	}
+}
+
+// domainLabelValue returns the label value of the domain for the configured domain label.
+func domainLabelValue(domain string) string {
+	switch domainLabel {
+	case types.MetricsDomainLabelETLD1:
+		if d, err := publicsuffix.EffectiveTLDPlusOne(domain); err == nil {
+			return d
+		}
+		return domain
+	case types.MetricsDomainLabelHash:
+		sum := sha256.Sum256([]byte(domain))
+		return hex.EncodeToString(sum[:8])
+	default:
+		return domain
+	}
+}
+
+func boolMetric(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
 
-	for key, value := range m {
-		queryTypes.WithLabelValues(im.HostName, key).Set(float64(value))
+func safeEntries(entries *[]model.TopArrayEntry) []model.TopArrayEntry {
+	if entries == nil {
+		return nil
 	}
+	return *entries
 }
 
 type InstanceMetricsList struct {
@@ -267,7 +882,17 @@ type InstanceMetrics struct {
 	HostName string
 	Status   *model.ServerStatus
 	Stats    *model.Stats
-	QueryLog *model.QueryLog
+	// QueryLog the query log entries added since the last scrape
+	QueryLog []model.QueryLogItem
+	// Filtering, Clients, RewriteEntries and Dhcp are optional, their metrics are removed if nil
+	Filtering      *model.FilterStatus
+	Clients        *model.Clients
+	RewriteEntries *model.RewriteEntries
+	Dhcp           *model.DhcpStatus
+	// Duration of the scrape in seconds
+	Duration float64
+	// Err the error of a failed scrape; the values are not complete.
+	Err error `faker:"-"`
 }
 
 type OverallStats map[string]*model.Stats
@@ -289,6 +914,8 @@ func safeMetric[T int | float64 | float32](v *T) float64 {
 }
 
 func getStats() OverallStats {
+	statsMux.RLock()
+	defer statsMux.RUnlock()
 	return stats.consolidate()
 }
 
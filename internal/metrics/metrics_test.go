@@ -1,12 +1,19 @@
 package metrics
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-faker/faker/v4"
 	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/bakito/adguardhome-sync/internal/client/model"
+	"github.com/bakito/adguardhome-sync/internal/types"
 )
 
 func TestUpdateInstances_getStats(t *testing.T) {
@@ -103,3 +110,376 @@ func verifyStats(t *testing.T, lines []Line) {
 		t.Errorf("sum mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestUpdateActionResult(t *testing.T) {
+	reg := registry(aghsSyncRuns, aghsSyncFailures, aghsSyncLastSuccess, aghsActionRuns, aghsActionFailures,
+		aghsActionDuration, aghsEntityChanges, aghsNextSync)
+
+	UpdateResult("replica", true, 1)
+	UpdateResult("replica", false, 2)
+	UpdateActionResult("replica", "clients", true, 0.5, EntityChanges{Added: 2, Removed: 1})
+	UpdateActionResult("replica", "clients", false, 0.1, EntityChanges{Updated: 1})
+	UpdateNextSync(time.Unix(1700000000, 0))
+
+	values := gather(t, reg)
+
+	want := map[string]float64{
+		"adguard_home_sync_sync_runs_total,replica":                           2,
+		"adguard_home_sync_sync_failures_total,replica":                       1,
+		"adguard_home_sync_sync_action_runs_total,clients,replica":            2,
+		"adguard_home_sync_sync_action_failures_total,clients,replica":        1,
+		"adguard_home_sync_sync_action_duration_seconds,clients,replica":      2,
+		"adguard_home_sync_sync_entity_changes_total,clients,replica,added":   2,
+		"adguard_home_sync_sync_entity_changes_total,clients,replica,updated": 1,
+		"adguard_home_sync_sync_entity_changes_total,clients,replica,removed": 1,
+		"adguard_home_sync_sync_next_run_timestamp_seconds":                   1700000000,
+	}
+	for name, v := range want {
+		if values[name] != v {
+			t.Errorf("%s = %v, want %v", name, values[name], v)
+		}
+	}
+	if ts := values["adguard_home_sync_sync_last_success_timestamp_seconds,replica"]; ts < float64(time.Now().Add(-time.Minute).Unix()) {
+		t.Errorf("last success timestamp = %v, want the time of the successful sync", ts)
+	}
+}
+
+func TestUpdateInstances_failedScrape(t *testing.T) {
+	reg := registry(scrapeSuccess, scrapeDuration, lastScrape, running, dnsQueries, topClients)
+	status := &model.ServerStatus{Running: true}
+	UpdateInstances(InstanceMetricsList{Metrics: []InstanceMetrics{
+		{HostName: "ok", Status: status, Stats: &model.Stats{NumDnsQueries: new(10)}, Duration: 0.5},
+		{HostName: "failing", Status: status, Stats: &model.Stats{
+			NumDnsQueries: new(20),
+			TopClients:    &[]model.TopArrayEntry{{AdditionalProperties: map[string]float32{"10.0.0.1": 5}}},
+		}},
+		{HostName: "removed", Status: status, Stats: &model.Stats{NumDnsQueries: new(30)}},
+	}})
+
+	t.Run("should skip failed scrapes", func(t *testing.T) {
+		UpdateInstances(InstanceMetricsList{Metrics: []InstanceMetrics{
+			{HostName: "ok", Status: status, Stats: &model.Stats{NumDnsQueries: new(11)}, Duration: 0.5},
+			{HostName: "failing", Err: errors.New("unreachable"), Duration: 2},
+		}})
+		values := gather(t, reg)
+		want := map[string]float64{
+			"adguard_scrape_success,ok":               1,
+			"adguard_scrape_duration_seconds,ok":      0.5,
+			"adguard_num_dns_queries,ok":              11,
+			"adguard_scrape_success,failing":          0,
+			"adguard_scrape_duration_seconds,failing": 2,
+			"adguard_running,failing":                 0,
+		}
+		for name, v := range want {
+			if got, ok := values[name]; !ok || got != v {
+				t.Errorf("%s = %v, want %v", name, got, v)
+			}
+		}
+		for _, name := range []string{"adguard_num_dns_queries,failing", "adguard_top_clients,10.0.0.1,failing"} {
+			if _, ok := values[name]; ok {
+				t.Errorf("%s is reported for the failed scrape", name)
+			}
+		}
+		if _, ok := values["adguard_last_scrape_timestamp_seconds,failing"]; !ok {
+			t.Error("the time of the last successful scrape is missing")
+		}
+		if _, ok := getStats()["failing"]; ok {
+			t.Error("the stats of the failed scrape are kept")
+		}
+	})
+	t.Run("should remove instances no longer scraped", func(t *testing.T) {
+		for name := range gather(t, reg) {
+			if strings.HasSuffix(name, ",removed") {
+				t.Errorf("%s is reported for the removed instance", name)
+			}
+		}
+		if _, ok := getStats()["removed"]; ok {
+			t.Error("the stats of the removed instance are kept")
+		}
+	})
+}
+
+func TestUpdateInstances_extended(t *testing.T) {
+	reg := registry(upstreamResponses, upstreamAvgTime, filteringEnabled, filterListEnabled, filterListRules,
+		filterListUpdateAge, persistentClients, rewrites, dhcpEnabled, dhcpStaticLeases, dhcpLeases)
+	updated := time.Now().Add(-time.Hour)
+	im := InstanceMetrics{
+		HostName: "extended",
+		Status:   &model.ServerStatus{},
+		Stats: &model.Stats{
+			TopUpstreamsResponses: &[]model.TopArrayEntry{{AdditionalProperties: map[string]float32{"1.1.1.1:53": 42}}},
+			TopUpstreamsAvgTime:   &[]model.TopArrayEntry{{AdditionalProperties: map[string]float32{"1.1.1.1:53": 0.25}}},
+		},
+		Filtering: &model.FilterStatus{
+			Enabled: new(true),
+			Filters: &[]model.Filter{
+				{Enabled: true, Name: "ads", Url: "https://ads.txt", RulesCount: 100, LastUpdated: &updated},
+			},
+			WhitelistFilters: &[]model.Filter{{Name: "allow", Url: "https://allow.txt", RulesCount: 3}},
+		},
+		Clients:        &model.Clients{Clients: &model.ClientsArray{{}, {}}},
+		RewriteEntries: &model.RewriteEntries{{}, {}, {}},
+		Dhcp: &model.DhcpStatus{
+			Enabled:      new(true),
+			StaticLeases: &[]model.DhcpStaticLease{{}},
+			Leases:       []model.DhcpLease{{}, {}},
+		},
+	}
+	UpdateInstances(InstanceMetricsList{Metrics: []InstanceMetrics{im}})
+
+	values := gather(t, reg)
+	want := map[string]float64{
+		"adguard_upstream_responses,extended,1.1.1.1:53":                         42,
+		"adguard_upstream_avg_response_time_seconds,extended,1.1.1.1:53":         0.25,
+		"adguard_filtering_enabled,extended":                                     1,
+		"adguard_filter_list_enabled,extended,blocklist,ads,https://ads.txt":     1,
+		"adguard_filter_list_rules,extended,blocklist,ads,https://ads.txt":       100,
+		"adguard_filter_list_enabled,extended,allowlist,allow,https://allow.txt": 0,
+		"adguard_filter_list_rules,extended,allowlist,allow,https://allow.txt":   3,
+		"adguard_persistent_clients,extended":                                    2,
+		"adguard_rewrites,extended":                                              3,
+		"adguard_dhcp_enabled,extended":                                          1,
+		"adguard_dhcp_static_leases,extended":                                    1,
+		"adguard_dhcp_leases,extended":                                           2,
+	}
+	for name, v := range want {
+		if got, ok := values[name]; !ok || got != v {
+			t.Errorf("%s = %v, want %v", name, got, v)
+		}
+	}
+	if age := values["adguard_filter_list_last_update_age_seconds,extended,blocklist,ads,https://ads.txt"]; age < 3600 {
+		t.Errorf("last update age = %v, want at least an hour", age)
+	}
+
+	t.Run("should remove the series of unknown values", func(t *testing.T) {
+		im.Filtering = nil
+		im.Dhcp = nil
+		im.Stats = &model.Stats{}
+		UpdateInstances(InstanceMetricsList{Metrics: []InstanceMetrics{im}})
+		for name := range gather(t, reg) {
+			if !strings.HasPrefix(name, "adguard_persistent_clients") && !strings.HasPrefix(name, "adguard_rewrites") {
+				t.Errorf("%s is still reported", name)
+			}
+		}
+	})
+}
+
+func TestUpdateInstances_queryLog(t *testing.T) {
+	reg := registry(queryTypes, queryLogQueries, queryLogBlocked, queryLogUpstreams, queryLogCached, queryLogElapsed)
+	entries := []model.QueryLogItem{
+		{
+			Client:    new("10.0.0.1"),
+			Answer:    &[]model.DnsAnswer{{Type: new("A")}, {Type: new("AAAA")}},
+			Upstream:  new("1.1.1.1:53"),
+			ElapsedMs: new("12.5"),
+			Reason:    new(model.NotFilteredNotFound),
+		},
+		{
+			Client:    new("10.0.0.1"),
+			Answer:    &[]model.DnsAnswer{{Type: new("A")}},
+			Cached:    new(true),
+			ElapsedMs: new("0.1"),
+		},
+		{Client: new("10.0.0.2"), Reason: new(model.FilteredBlackList)},
+	}
+	status := &model.ServerStatus{}
+	UpdateInstances(InstanceMetricsList{Metrics: []InstanceMetrics{
+		{HostName: "ql", Status: status, Stats: &model.Stats{}, QueryLog: entries},
+	}})
+
+	t.Run("should count the new entries", func(t *testing.T) {
+		UpdateInstances(InstanceMetricsList{Metrics: []InstanceMetrics{
+			{HostName: "ql", Status: status, Stats: &model.Stats{}, QueryLog: entries[:1]},
+		}})
+		values := gather(t, reg)
+		want := map[string]float64{
+			"adguard_querylog_answer_types_total,ql,A":                3,
+			"adguard_querylog_answer_types_total,ql,AAAA":             2,
+			"adguard_querylog_queries_total,10.0.0.1,ql":              3,
+			"adguard_querylog_queries_total,10.0.0.2,ql":              1,
+			"adguard_querylog_blocked_total,ql,FilteredBlackList":     1,
+			"adguard_querylog_upstream_responses_total,ql,1.1.1.1:53": 2,
+			"adguard_querylog_cached_total,ql":                        1,
+			"adguard_querylog_elapsed_seconds,ql":                     3,
+		}
+		if diff := cmp.Diff(want, values); diff != "" {
+			t.Errorf("values mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("should keep the counters on failed scrapes", func(t *testing.T) {
+		UpdateInstances(InstanceMetricsList{Metrics: []InstanceMetrics{
+			{HostName: "ql", Err: errors.New("unreachable")},
+		}})
+		if got := gather(t, reg)["adguard_querylog_cached_total,ql"]; got != 1 {
+			t.Errorf("cached = %v, want 1", got)
+		}
+	})
+	t.Run("should remove the counters of instances no longer scraped", func(t *testing.T) {
+		UpdateInstances(InstanceMetricsList{})
+		if values := gather(t, reg); len(values) != 0 {
+			t.Errorf("values = %v, want none", values)
+		}
+		if len(queryLogClients) != 0 || len(queryLogUpstreamNames) != 0 {
+			t.Errorf("label values = %v %v, want none", queryLogClients, queryLogUpstreamNames)
+		}
+	})
+	t.Run("should count the clients and upstreams beyond the top limit as other", func(t *testing.T) {
+		topLimit = 1
+		defer func() { topLimit = 0 }()
+		UpdateInstances(InstanceMetricsList{Metrics: []InstanceMetrics{
+			{HostName: "ql", Status: status, Stats: &model.Stats{}, QueryLog: []model.QueryLogItem{
+				{Client: new("10.0.0.1"), Upstream: new("1.1.1.1:53")},
+				{Client: new("10.0.0.2"), Upstream: new("8.8.8.8:53")},
+				{Client: new("10.0.0.3"), Upstream: new("9.9.9.9:53")},
+				{Client: new("10.0.0.1"), Upstream: new("1.1.1.1:53")},
+			}},
+		}})
+		values := gather(t, reg)
+		want := map[string]float64{
+			"adguard_querylog_queries_total,10.0.0.1,ql":              2,
+			"adguard_querylog_queries_total,other,ql":                 2,
+			"adguard_querylog_upstream_responses_total,ql,1.1.1.1:53": 2,
+			"adguard_querylog_upstream_responses_total,ql,other":      2,
+		}
+		if diff := cmp.Diff(want, values); diff != "" {
+			t.Errorf("values mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("should limit the clients and upstreams without top limit", func(t *testing.T) {
+		queryLogLabelLimit = 2
+		defer func() { queryLogLabelLimit = defaultQueryLogLabelLimit }()
+		UpdateInstances(InstanceMetricsList{})
+		UpdateInstances(InstanceMetricsList{Metrics: []InstanceMetrics{
+			{HostName: "ql", Status: status, Stats: &model.Stats{}, QueryLog: []model.QueryLogItem{
+				{Client: new("10.0.0.1"), Upstream: new("1.1.1.1:53")},
+				{Client: new("10.0.0.2"), Upstream: new("1.1.1.1:53")},
+				{Client: new("10.0.0.3"), Upstream: new("1.1.1.1:53")},
+			}},
+		}})
+		values := gather(t, reg)
+		want := map[string]float64{
+			"adguard_querylog_queries_total,10.0.0.1,ql":              1,
+			"adguard_querylog_queries_total,10.0.0.2,ql":              1,
+			"adguard_querylog_queries_total,other,ql":                 1,
+			"adguard_querylog_upstream_responses_total,ql,1.1.1.1:53": 3,
+		}
+		if diff := cmp.Diff(want, values); diff != "" {
+			t.Errorf("values mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestInit(t *testing.T) {
+	if err := Init(types.Metrics{Disabled: []string{"top_clients", "unknown"}}); !errors.Is(err, errUnknownMetric) {
+		t.Errorf("Init() error = %v, want %v", err, errUnknownMetric)
+	}
+}
+
+func TestDisabledMetrics(t *testing.T) {
+	disabled, err := disabledMetrics(families(), []string{"top_clients", "query_types"})
+	if err != nil {
+		t.Fatalf("disabledMetrics() error = %v", err)
+	}
+	want := map[string]bool{"top_clients": true, "querylog_answer_types_total": true}
+	if diff := cmp.Diff(want, disabled); diff != "" {
+		t.Errorf("disabled mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSetTopValues(t *testing.T) {
+	defer func() {
+		topLimit = 0
+		domainLabel = ""
+	}()
+	reg := registry(topQueries)
+	entries := &[]model.TopArrayEntry{
+		{AdditionalProperties: map[string]float32{"www.example.com": 10}},
+		{AdditionalProperties: map[string]float32{"api.example.com": 5}},
+		{AdditionalProperties: map[string]float32{"example.org": 2}},
+	}
+	tests := []struct {
+		name        string
+		topLimit    int
+		domainLabel string
+		want        map[string]float64
+	}{
+		{
+			name: "should report all domains",
+			want: map[string]float64{"www.example.com": 10, "api.example.com": 5, "example.org": 2},
+		},
+		{
+			name:     "should cap the top domains",
+			topLimit: 2,
+			want:     map[string]float64{"www.example.com": 10, "api.example.com": 5},
+		},
+		{
+			name:        "should aggregate to eTLD+1",
+			domainLabel: types.MetricsDomainLabelETLD1,
+			want:        map[string]float64{"example.com": 15, "example.org": 2},
+		},
+		{
+			name:        "should hash the domains",
+			topLimit:    1,
+			domainLabel: types.MetricsDomainLabelHash,
+			want:        map[string]float64{domainHash("www.example.com"): 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topLimit = tt.topLimit
+			domainLabel = tt.domainLabel
+			setTopValues(topQueries, "top", entries, domainLabelValue)
+
+			got := make(map[string]float64)
+			for name, v := range gather(t, reg) {
+				got[strings.TrimPrefix(name, "adguard_top_queried_domains,")] = v
+			}
+			want := make(map[string]float64)
+			for domain, v := range tt.want {
+				want[domain+",top"] = v
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("series mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func domainHash(domain string) string {
+	sum := sha256.Sum256([]byte(domain))
+	return hex.EncodeToString(sum[:8])
+}
+
+// registry returns a registry with the metrics.
+func registry(cs ...prometheus.Collector) *prometheus.Registry {
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(cs...)
+	return reg
+}
+
+// gather returns the values of the registered metrics by name and label values; histograms by sample count.
+func gather(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+	t.Helper()
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	values := make(map[string]float64)
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			name := mf.GetName()
+			for _, lp := range m.GetLabel() {
+				name += "," + lp.GetValue()
+			}
+			switch {
+			case m.GetCounter() != nil:
+				values[name] = m.GetCounter().GetValue()
+			case m.GetGauge() != nil:
+				values[name] = m.GetGauge().GetValue()
+			case m.GetHistogram() != nil:
+				values[name] = float64(m.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return values
+}
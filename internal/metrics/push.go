@@ -0,0 +1,240 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+// maxStatsDPacket the maximum size of a StatsD packet, to fit into the MTU of common networks.
+const maxStatsDPacket = 1432
+
+var (
+	errInfluxDB = errors.New("InfluxDB write failed")
+
+	// pushers the configured push exporters.
+	pushers []pusher
+
+	influxEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+	statsDEscaper = strings.NewReplacer(",", "_", "|", "_", "#", "_", "\n", "_")
+)
+
+// pusher sends the gathered metrics to a push target.
+type pusher interface {
+	name() string
+	push(ctx context.Context, mfs []*dto.MetricFamily, now time.Time) error
+}
+
+// newPushers creates the push exporters configured in the metrics config.
+func newPushers(cfg types.Metrics) []pusher {
+	var ps []pusher
+	if cfg.InfluxDB.URL != "" {
+		ps = append(ps, &influxDB{url: cfg.InfluxDB.URL, token: cfg.InfluxDB.Token, client: &http.Client{}})
+	}
+	if cfg.StatsD.Address != "" {
+		ps = append(ps, &statsD{address: cfg.StatsD.Address, prefix: cfg.StatsD.Prefix})
+	}
+	return ps
+}
+
+// Push sends the registered AdGuard Home and sync metrics to the configured push exporters.
+func Push(ctx context.Context) error {
+	if len(pushers) == 0 {
+		return nil
+	}
+	all, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		return err
+	}
+	var mfs []*dto.MetricFamily
+	for _, mf := range all {
+		if strings.HasPrefix(mf.GetName(), "adguard_") {
+			mfs = append(mfs, mf)
+		}
+	}
+
+	now := time.Now()
+	var errs []error
+	for _, p := range pushers {
+		if err := p.push(ctx, mfs, now); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sample a value of a metric, histograms are reported by count and sum.
+type sample struct {
+	name   string
+	labels []*dto.LabelPair
+	fields map[string]float64
+}
+
+// samples flattens the metric families; values that are not finite are skipped.
+func samples(mfs []*dto.MetricFamily) []sample {
+	var result []sample
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			s := sample{name: mf.GetName(), labels: m.GetLabel(), fields: make(map[string]float64)}
+			switch {
+			case m.GetCounter() != nil:
+				s.fields["value"] = m.GetCounter().GetValue()
+			case m.GetGauge() != nil:
+				s.fields["value"] = m.GetGauge().GetValue()
+			case m.GetHistogram() != nil:
+				s.fields["count"] = float64(m.GetHistogram().GetSampleCount())
+				s.fields["sum"] = m.GetHistogram().GetSampleSum()
+			default:
+				continue
+			}
+			for k, v := range s.fields {
+				if math.IsNaN(v) || math.IsInf(v, 0) {
+					delete(s.fields, k)
+				}
+			}
+			if len(s.fields) > 0 {
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
+
+// influxDB writes the metrics in the line protocol to the write endpoint of InfluxDB.
+type influxDB struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func (*influxDB) name() string {
+	return "influxdb"
+}
+
+func (i *influxDB) push(ctx context.Context, mfs []*dto.MetricFamily, now time.Time) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.url, bytes.NewReader(lineProtocol(mfs, now)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if i.token != "" {
+		req.Header.Set("Authorization", "Token "+i.token)
+	}
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%w: %s", errInfluxDB, resp.Status)
+	}
+	return nil
+}
+
+// lineProtocol formats the metrics as InfluxDB line protocol, one measurement per metric with the labels as tags.
+func lineProtocol(mfs []*dto.MetricFamily, now time.Time) []byte {
+	var b bytes.Buffer
+	ts := strconv.FormatInt(now.UnixNano(), 10)
+	for _, s := range samples(mfs) {
+		b.WriteString(influxEscaper.Replace(s.name))
+		for _, lp := range s.labels {
+			// empty tag values are not allowed
+			if lp.GetValue() != "" {
+				b.WriteString("," + influxEscaper.Replace(lp.GetName()) + "=" + influxEscaper.Replace(lp.GetValue()))
+			}
+		}
+		sep := " "
+		for _, k := range []string{"value", "count", "sum"} {
+			if v, ok := s.fields[k]; ok {
+				b.WriteString(sep + k + "=" + formatFloat(v))
+				sep = ","
+			}
+		}
+		b.WriteString(" " + ts + "\n")
+	}
+	return b.Bytes()
+}
+
+// statsD sends the metrics as gauges with DogStatsD tags over UDP.
+type statsD struct {
+	address string
+	prefix  string
+}
+
+func (*statsD) name() string {
+	return "statsd"
+}
+
+func (s *statsD) push(ctx context.Context, mfs []*dto.MetricFamily, _ time.Time) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", s.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var packet []byte
+	for _, line := range statsDLines(s.prefix, mfs) {
+		if len(packet) > 0 && len(packet)+len(line)+1 > maxStatsDPacket {
+			if _, err := conn.Write(packet); err != nil {
+				return err
+			}
+			packet = packet[:0]
+		}
+		if len(packet) > 0 {
+			packet = append(packet, '\n')
+		}
+		packet = append(packet, line...)
+	}
+	if len(packet) > 0 {
+		_, err = conn.Write(packet)
+	}
+	return err
+}
+
+// statsDLines formats the metrics as StatsD gauges; histograms are sent as '_count' and '_sum' gauges.
+func statsDLines(prefix string, mfs []*dto.MetricFamily) []string {
+	var lines []string
+	for _, s := range samples(mfs) {
+		var tags []string
+		for _, lp := range s.labels {
+			tags = append(tags, statsDEscaper.Replace(lp.GetName())+":"+statsDEscaper.Replace(lp.GetValue()))
+		}
+		suffix := "|g"
+		if len(tags) > 0 {
+			suffix += "|#" + strings.Join(tags, ",")
+		}
+		for _, k := range []string{"value", "count", "sum"} {
+			v, ok := s.fields[k]
+			if !ok {
+				continue
+			}
+			name := prefix + s.name
+			if k != "value" {
+				name += "_" + k
+			}
+			// a signed value changes a gauge, a negative value is set by resetting the gauge first
+			if v < 0 {
+				lines = append(lines, name+":0"+suffix)
+			}
+			lines = append(lines, name+":"+formatFloat(v)+suffix)
+		}
+	}
+	return lines
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// pushFamilies returns the families of a gauge with escaped labels, a counter and a histogram.
+func pushFamilies(t *testing.T) []*dto.MetricFamily {
+	t.Helper()
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "adguard_gauge"}, []string{"hostname", "name"})
+	gauge.WithLabelValues("a:3000", "my list, v=2").Set(1.5)
+	gauge.WithLabelValues("b:3000", "").Set(-2)
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "adguard_counter_total"})
+	counter.Add(3)
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "adguard_duration_seconds"})
+	histogram.Observe(0.25)
+	histogram.Observe(0.5)
+
+	mfs, err := registry(gauge, counter, histogram).Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	return mfs
+}
+
+func TestLineProtocol(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	got := string(lineProtocol(pushFamilies(t), now))
+	want := `adguard_counter_total value=3 1700000000000000000
+adguard_duration_seconds count=2,sum=0.75 1700000000000000000
+adguard_gauge,hostname=a:3000,name=my\ list\,\ v\=2 value=1.5 1700000000000000000
+adguard_gauge,hostname=b:3000 value=-2 1700000000000000000
+`
+	if got != want {
+		t.Errorf("lineProtocol() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestStatsDLines(t *testing.T) {
+	got := statsDLines("home.", pushFamilies(t))
+	want := []string{
+		"home.adguard_counter_total:3|g",
+		"home.adguard_duration_seconds_count:2|g",
+		"home.adguard_duration_seconds_sum:0.75|g",
+		"home.adguard_gauge:1.5|g|#hostname:a:3000,name:my list_ v=2",
+		"home.adguard_gauge:0|g|#hostname:b:3000,name:",
+		"home.adguard_gauge:-2|g|#hostname:b:3000,name:",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("statsDLines() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestPushers(t *testing.T) {
+	mfs := pushFamilies(t)
+
+	t.Run("should write to InfluxDB", func(t *testing.T) {
+		var auth, body string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth = r.Header.Get("Authorization")
+			b, _ := io.ReadAll(r.Body)
+			body = string(b)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+
+		p := &influxDB{url: ts.URL + "/api/v2/write?bucket=adguard", token: "secret", client: ts.Client()}
+		if err := p.push(t.Context(), mfs, time.Now()); err != nil {
+			t.Fatalf("push() error = %v", err)
+		}
+		if auth != "Token secret" || !strings.Contains(body, "adguard_counter_total value=3 ") {
+			t.Errorf("request = %q %q, want the token and the metrics", auth, body)
+		}
+	})
+	t.Run("should fail on InfluxDB errors", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer ts.Close()
+
+		p := &influxDB{url: ts.URL, client: ts.Client()}
+		if err := p.push(t.Context(), mfs, time.Now()); err == nil || !strings.Contains(err.Error(), "401") {
+			t.Errorf("push() error = %v, want the status", err)
+		}
+	})
+	t.Run("should send to StatsD", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("ListenPacket() error = %v", err)
+		}
+		defer conn.Close()
+
+		p := &statsD{address: conn.LocalAddr().String()}
+		if err := p.push(t.Context(), mfs, time.Now()); err != nil {
+			t.Fatalf("push() error = %v", err)
+		}
+		buf := make([]byte, maxStatsDPacket)
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("ReadFrom() error = %v", err)
+		}
+		if lines := strings.Split(string(buf[:n]), "\n"); len(lines) != 6 || lines[0] != "adguard_counter_total:3|g" {
+			t.Errorf("packet = %q, want the 6 gauges", lines)
+		}
+	})
+}
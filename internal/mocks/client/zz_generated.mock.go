@@ -14,6 +14,7 @@ package client
 import (
 	reflect "reflect"
 
+	client "github.com/bakito/adguardhome-sync/internal/client"
 	model "github.com/bakito/adguardhome-sync/internal/client/model"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -58,17 +59,17 @@ func (mr *MockClientMockRecorder) AccessList() *gomock.Call {
 }
 
 // AddClient mocks base method.
-func (m *MockClient) AddClient(client *model.Client) error {
+func (m *MockClient) AddClient(arg0 *model.Client) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddClient", client)
+	ret := m.ctrl.Call(m, "AddClient", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddClient indicates an expected call of AddClient.
-func (mr *MockClientMockRecorder) AddClient(client any) *gomock.Call {
+func (mr *MockClientMockRecorder) AddClient(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddClient", reflect.TypeOf((*MockClient)(nil).AddClient), client)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddClient", reflect.TypeOf((*MockClient)(nil).AddClient), arg0)
 }
 
 // AddDHCPStaticLease mocks base method.
@@ -163,17 +164,17 @@ func (mr *MockClientMockRecorder) DNSConfig() *gomock.Call {
 }
 
 // DeleteClient mocks base method.
-func (m *MockClient) DeleteClient(client *model.Client) error {
+func (m *MockClient) DeleteClient(arg0 *model.Client) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteClient", client)
+	ret := m.ctrl.Call(m, "DeleteClient", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteClient indicates an expected call of DeleteClient.
-func (mr *MockClientMockRecorder) DeleteClient(client any) *gomock.Call {
+func (mr *MockClientMockRecorder) DeleteClient(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClient", reflect.TypeOf((*MockClient)(nil).DeleteClient), client)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClient", reflect.TypeOf((*MockClient)(nil).DeleteClient), arg0)
 }
 
 // DeleteDHCPStaticLease mocks base method.
@@ -297,18 +298,18 @@ func (mr *MockClientMockRecorder) ProfileInfo() *gomock.Call {
 }
 
 // QueryLog mocks base method.
-func (m *MockClient) QueryLog(limit int) (*model.QueryLog, error) {
+func (m *MockClient) QueryLog(query client.QueryLogQuery) (*model.QueryLog, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryLog", limit)
+	ret := m.ctrl.Call(m, "QueryLog", query)
 	ret0, _ := ret[0].(*model.QueryLog)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryLog indicates an expected call of QueryLog.
-func (mr *MockClientMockRecorder) QueryLog(limit any) *gomock.Call {
+func (mr *MockClientMockRecorder) QueryLog(query any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryLog", reflect.TypeOf((*MockClient)(nil).QueryLog), limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryLog", reflect.TypeOf((*MockClient)(nil).QueryLog), query)
 }
 
 // QueryLogConfig mocks base method.
@@ -685,17 +686,17 @@ func (mr *MockClientMockRecorder) ToggleSafeBrowsing(enable any) *gomock.Call {
 }
 
 // UpdateClient mocks base method.
-func (m *MockClient) UpdateClient(client *model.Client) error {
+func (m *MockClient) UpdateClient(arg0 *model.Client) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateClient", client)
+	ret := m.ctrl.Call(m, "UpdateClient", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateClient indicates an expected call of UpdateClient.
-func (mr *MockClientMockRecorder) UpdateClient(client any) *gomock.Call {
+func (mr *MockClientMockRecorder) UpdateClient(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClient", reflect.TypeOf((*MockClient)(nil).UpdateClient), client)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClient", reflect.TypeOf((*MockClient)(nil).UpdateClient), arg0)
 }
 
 // UpdateFilter mocks base method.
@@ -0,0 +1,226 @@
+package recording
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bakito/adguardhome-sync/version"
+)
+
+const harVersion = "1.2"
+
+// isHAR returns true if the file is recorded as HTTP archive instead of JSON lines.
+func isHAR(file string) bool {
+	return strings.EqualFold(filepath.Ext(file), ".har")
+}
+
+// har an HTTP archive (http://www.softwareishard.com/blog/har-12-spec/) with the fields of the recorded entries.
+type har struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// readHAR reads the entries of an HTTP archive, an empty file has none.
+func readHAR(r io.Reader) ([]Entry, error) {
+	var h har
+	if err := json.NewDecoder(r).Decode(&h); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(h.Log.Entries))
+	for _, he := range h.Log.Entries {
+		entries = append(entries, fromHAR(he))
+	}
+	return entries, nil
+}
+
+// writeHAR replaces the content of the file with the HTTP archive of the entries.
+func writeHAR(f *os.File, entries []Entry) error {
+	h := har{Log: harLog{
+		Version: harVersion,
+		Creator: harCreator{Name: "adguardhome-sync", Version: version.Version},
+		Entries: make([]harEntry, 0, len(entries)),
+	}}
+	for _, e := range entries {
+		h.Log.Entries = append(h.Log.Entries, toHAR(e))
+	}
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&h)
+}
+
+func toHAR(e Entry) harEntry {
+	u := url.URL{Scheme: e.Scheme, Host: e.Host, Path: e.Path, RawQuery: e.Query}
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+	var ms float64
+	if d, err := time.ParseDuration(e.Duration); err == nil {
+		ms = float64(d) / float64(time.Millisecond)
+	}
+
+	he := harEntry{
+		StartedDateTime: e.Time,
+		Time:            ms,
+		Request: harRequest{
+			Method:      e.Method,
+			URL:         u.String(),
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []harNameValue{},
+			Headers:     toNameValues(e.RequestHeaders),
+			QueryString: toNameValues(u.Query()),
+			HeadersSize: -1,
+			BodySize:    len(e.RequestBody),
+		},
+		Response: harResponse{
+			Status:      e.Status,
+			StatusText:  http.StatusText(e.Status),
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []harNameValue{},
+			Headers:     toNameValues(e.ResponseHeaders),
+			Content: harContent{
+				Size:     len(e.ResponseBody),
+				MimeType: http.Header(e.ResponseHeaders).Get("Content-Type"),
+				Text:     e.ResponseBody,
+			},
+			HeadersSize: -1,
+			BodySize:    len(e.ResponseBody),
+		},
+		Timings: harTimings{Send: 0, Wait: ms, Receive: 0},
+		Comment: e.Error,
+	}
+	if e.RequestBody != "" {
+		he.Request.PostData = &harPostData{
+			MimeType: http.Header(e.RequestHeaders).Get("Content-Type"),
+			Text:     e.RequestBody,
+		}
+	}
+	return he
+}
+
+func fromHAR(he harEntry) Entry {
+	e := Entry{
+		Time:            he.StartedDateTime,
+		Method:          he.Request.Method,
+		RequestHeaders:  fromNameValues(he.Request.Headers),
+		Status:          he.Response.Status,
+		ResponseHeaders: fromNameValues(he.Response.Headers),
+		ResponseBody:    he.Response.Content.Text,
+		Duration:        time.Duration(he.Time * float64(time.Millisecond)).String(),
+		Error:           he.Comment,
+	}
+	if u, err := url.Parse(he.Request.URL); err == nil {
+		e.Scheme = u.Scheme
+		e.Host = u.Host
+		e.Path = u.Path
+		e.Query = u.RawQuery
+	}
+	if he.Request.PostData != nil {
+		e.RequestBody = he.Request.PostData.Text
+	}
+	return e
+}
+
+func toNameValues(values map[string][]string) []harNameValue {
+	nvs := []harNameValue{}
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		for _, v := range values[name] {
+			nvs = append(nvs, harNameValue{Name: name, Value: v})
+		}
+	}
+	return nvs
+}
+
+func fromNameValues(nvs []harNameValue) map[string][]string {
+	if len(nvs) == 0 {
+		return nil
+	}
+	values := make(map[string][]string)
+	for _, nv := range nvs {
+		values[nv.Name] = append(values[nv.Name], nv.Value)
+	}
+	return values
+}
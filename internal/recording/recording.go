@@ -0,0 +1,266 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/bakito/adguardhome-sync/internal/log"
+)
+
+const redacted = "**REDACTED**"
+
+var (
+	l = log.GetLogger("recording")
+
+	sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+	sensitiveKeys    = []string{"password", "private_key", "token", "secret", "cookie"}
+
+	recorders   = make(map[string]*Recorder)
+	recordersMu sync.Mutex
+)
+
+// Entry a single recorded request / response pair.
+type Entry struct {
+	Time            time.Time           `json:"time"`
+	Scheme          string              `json:"scheme,omitempty"`
+	Host            string              `json:"host"`
+	Method          string              `json:"method"`
+	Path            string              `json:"path"`
+	Query           string              `json:"query,omitempty"`
+	RequestHeaders  map[string][]string `json:"requestHeaders,omitempty"`
+	RequestBody     string              `json:"requestBody,omitempty"`
+	Status          int                 `json:"status"`
+	ResponseHeaders map[string][]string `json:"responseHeaders,omitempty"`
+	ResponseBody    string              `json:"responseBody,omitempty"`
+	Duration        string              `json:"duration"`
+	Error           string              `json:"error,omitempty"`
+}
+
+func (e *Entry) key() string {
+	return e.Method + " " + e.Path + "?" + e.Query
+}
+
+// Recorder writes redacted request / response pairs of resty clients into a JSON lines file, or into an HTTP archive
+// if the file has the extension '.har'. Each entry is tagged with the host of its request, as clients of several
+// instances may share a file.
+type Recorder struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+	// entries the entries of an HTTP archive, rewritten as a whole with each entry.
+	entries []Entry
+	har     bool
+}
+
+// Open returns the recorder for the given file. Recorders are shared between clients writing to the same file.
+func Open(file string) (*Recorder, error) {
+	recordersMu.Lock()
+	defer recordersMu.Unlock()
+	if r, ok := recorders[file]; ok {
+		return r, nil
+	}
+
+	r, err := open(file)
+	if err != nil {
+		return nil, err
+	}
+	recorders[file] = r
+	l.With("file", file).Warn("Recording HTTP traffic - do not use in production")
+	return r, nil
+}
+
+func open(file string) (*Recorder, error) {
+	if !isHAR(file) {
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		return &Recorder{file: f, enc: json.NewEncoder(f)}, nil
+	}
+
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	// continue an existing archive
+	entries, err := readHAR(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &Recorder{file: f, entries: entries, har: true}, nil
+}
+
+// Close closes the files of all recorders on shutdown. Clients keep their recorder, which drops the entries of
+// requests made afterwards; clients created afterwards open a new recorder.
+func Close() error {
+	recordersMu.Lock()
+	defer recordersMu.Unlock()
+	var errs []error
+	for file, r := range recorders {
+		r.mu.Lock()
+		errs = append(errs, r.file.Close())
+		r.file = nil
+		r.mu.Unlock()
+		delete(recorders, file)
+	}
+	return errors.Join(errs...)
+}
+
+// Attach registers the recorder on the resty client.
+func (r *Recorder) Attach(cl *resty.Client) {
+	cl.OnSuccess(func(_ *resty.Client, resp *resty.Response) {
+		r.record(resp.Request, resp, nil)
+	})
+	cl.OnError(func(req *resty.Request, err error) {
+		var resp *resty.Response
+		if re := (&resty.ResponseError{}); errors.As(err, &re) {
+			resp = re.Response
+		}
+		r.record(req, resp, err)
+	})
+}
+
+func (r *Recorder) record(req *resty.Request, resp *resty.Response, err error) {
+	e := Entry{
+		Time:   time.Now(),
+		Method: req.Method,
+	}
+
+	if req.RawRequest != nil {
+		e.Scheme = req.RawRequest.URL.Scheme
+		e.Host = req.RawRequest.URL.Host
+		e.Path = req.RawRequest.URL.Path
+		e.Query = req.RawRequest.URL.RawQuery
+		e.RequestHeaders = redactHeaders(req.RawRequest.Header)
+	} else {
+		if u, pErr := url.Parse(req.URL); pErr == nil {
+			e.Scheme = u.Scheme
+			e.Host = u.Host
+		}
+		e.Path = req.URL
+		e.RequestHeaders = redactHeaders(req.Header)
+	}
+	if req.Body != nil {
+		if b, mErr := json.Marshal(req.Body); mErr == nil {
+			e.RequestBody = redactBody(b)
+		}
+	}
+	if resp != nil {
+		e.Status = resp.StatusCode()
+		e.ResponseHeaders = redactHeaders(resp.Header())
+		e.ResponseBody = redactBody(resp.Body())
+		e.Duration = resp.Time().String()
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		// closed
+		return
+	}
+	if wErr := r.write(e); wErr != nil {
+		l.With("error", wErr, "file", r.file.Name()).Error("Error writing recording")
+	}
+}
+
+func (r *Recorder) write(e Entry) error {
+	if !r.har {
+		return r.enc.Encode(&e)
+	}
+	r.entries = append(r.entries, e)
+	return writeHAR(r.file, r.entries)
+}
+
+func redactHeaders(h http.Header) map[string][]string {
+	if len(h) == 0 {
+		return nil
+	}
+	out := h.Clone()
+	for _, name := range sensitiveHeaders {
+		if _, ok := out[name]; ok {
+			out[name] = []string{redacted}
+		}
+	}
+	return out
+}
+
+func redactBody(b []byte) string {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return string(b)
+	}
+	redactValue(v)
+	rb, err := json.Marshal(v)
+	if err != nil {
+		return string(b)
+	}
+	return string(rb)
+}
+
+func redactValue(v any) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if _, isString := child.(string); isString && isSensitive(k) {
+				val[k] = redacted
+			} else {
+				redactValue(child)
+			}
+		}
+	case []any:
+		for _, child := range val {
+			redactValue(child)
+		}
+	}
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads all entries of a recording file, JSON lines or an HTTP archive if the file has the extension '.har'.
+func Load(file string) ([]Entry, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	if isHAR(file) {
+		return readHAR(f)
+	}
+
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for sc.Scan() {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, sc.Err()
+}
@@ -0,0 +1,216 @@
+package recording_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bakito/adguardhome-sync/internal/client"
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+	"github.com/bakito/adguardhome-sync/internal/recording"
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/control/status":
+			_, _ = w.Write([]byte(`{"version":"v0.107.78","running":true}`))
+		case "/control/install/configure":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	file := filepath.Join(t.TempDir(), "recording.jsonl")
+	inst := types.AdGuardInstance{URL: ts.URL, Username: "user", Password: "secret-password", RecordFile: file}
+	if err := inst.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	cl, err := client.New(inst, 0)
+	if err != nil {
+		t.Fatalf("client.New error = %v", err)
+	}
+	if _, err := cl.Status(); err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if err := cl.Setup(); err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+
+	entries, err := recording.Load(file)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("len(entries) = %d, want 2", len(entries))
+	}
+
+	t.Run("should redact credentials", func(t *testing.T) {
+		if got := entries[0].RequestHeaders["Authorization"]; len(got) != 1 || got[0] != "**REDACTED**" {
+			t.Errorf("Authorization header = %v, want redacted", got)
+		}
+		if strings.Contains(entries[1].RequestBody, "secret-password") {
+			t.Errorf("RequestBody = %s, should not contain the password", entries[1].RequestBody)
+		}
+		if entries[0].Path != "/control/status" || entries[0].Status != http.StatusOK {
+			t.Errorf("entry = %s %d, want /control/status 200", entries[0].Path, entries[0].Status)
+		}
+	})
+
+	t.Run("should replay the recorded responses", func(t *testing.T) {
+		rs := httptest.NewServer(recording.NewReplay(entries))
+		defer rs.Close()
+
+		rc, err := client.New(types.AdGuardInstance{URL: rs.URL}, 0)
+		if err != nil {
+			t.Fatalf("client.New error = %v", err)
+		}
+		var status *model.ServerStatus
+		if status, err = rc.Status(); err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		if status.Version != "v0.107.78" {
+			t.Errorf("Version = %s, want v0.107.78", status.Version)
+		}
+		if _, err := rc.Stats(); err == nil {
+			t.Error("Stats() error = nil, want error for unrecorded request")
+		}
+	})
+}
+
+func TestRecordSharedFile(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"version":"v0.107.78","running":true}`))
+	})
+	origin := httptest.NewServer(handler)
+	defer origin.Close()
+	replica := httptest.NewServer(handler)
+	defer replica.Close()
+
+	file := filepath.Join(t.TempDir(), "recording.jsonl")
+	var hosts []string
+	for _, ts := range []*httptest.Server{origin, replica} {
+		inst := types.AdGuardInstance{URL: ts.URL, RecordFile: file}
+		if err := inst.Init(); err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+		cl, err := client.New(inst, 0)
+		if err != nil {
+			t.Fatalf("client.New error = %v", err)
+		}
+		if _, err := cl.Status(); err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		hosts = append(hosts, strings.TrimPrefix(ts.URL, "http://"))
+	}
+	if err := recording.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	entries, err := recording.Load(file)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Host != hosts[0] || entries[1].Host != hosts[1] {
+		t.Fatalf("entries = %+v, want one entry per host %v", entries, hosts)
+	}
+	if got := recording.ByHost(entries, hosts[1]); len(got) != 1 || got[0].Host != hosts[1] {
+		t.Errorf("ByHost() = %+v, want the entry of %s", got, hosts[1])
+	}
+}
+
+func TestRecordHAR(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"version":"v0.107.78","running":true}`))
+	}))
+	defer ts.Close()
+
+	file := filepath.Join(t.TempDir(), "recording.har")
+	record := func() {
+		inst := types.AdGuardInstance{URL: ts.URL, Username: "user", Password: "secret-password", RecordFile: file}
+		if err := inst.Init(); err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+		cl, err := client.New(inst, 0)
+		if err != nil {
+			t.Fatalf("client.New error = %v", err)
+		}
+		if _, err := cl.Status(); err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		if err := recording.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		if _, err := cl.Status(); err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+	}
+	record()
+	record()
+
+	t.Run("should write an HTTP archive", func(t *testing.T) {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		var h struct {
+			Log struct {
+				Version string `json:"version"`
+				Entries []struct {
+					Request struct {
+						URL string `json:"url"`
+					} `json:"request"`
+				} `json:"entries"`
+			} `json:"log"`
+		}
+		if err := json.Unmarshal(b, &h); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if h.Log.Version != "1.2" || len(h.Log.Entries) != 2 {
+			t.Fatalf("log = %+v, want version 1.2 with 2 entries", h.Log)
+		}
+		if h.Log.Entries[0].Request.URL != ts.URL+"/control/status" {
+			t.Errorf("URL = %s, want %s/control/status", h.Log.Entries[0].Request.URL, ts.URL)
+		}
+		if strings.Contains(string(b), "secret-password") {
+			t.Error("archive should not contain the password")
+		}
+	})
+
+	t.Run("should load and replay the archive", func(t *testing.T) {
+		entries, err := recording.Load(file)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if len(entries) != 2 {
+			t.Fatalf("len(entries) = %d, want 2", len(entries))
+		}
+		if got := entries[0].RequestHeaders["Authorization"]; len(got) != 1 || got[0] != "**REDACTED**" {
+			t.Errorf("Authorization header = %v, want redacted", got)
+		}
+
+		rs := httptest.NewServer(recording.NewReplay(entries))
+		defer rs.Close()
+		rc, err := client.New(types.AdGuardInstance{URL: rs.URL}, 0)
+		if err != nil {
+			t.Fatalf("client.New error = %v", err)
+		}
+		status, err := rc.Status()
+		if err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		if status.Version != "v0.107.78" {
+			t.Errorf("Version = %s, want v0.107.78", status.Version)
+		}
+	})
+}
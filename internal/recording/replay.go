@@ -0,0 +1,75 @@
+package recording
+
+import (
+	"net/http"
+	"sync"
+)
+
+// Replay serves recorded responses back as a fake AdGuard Home instance.
+// Responses of the same request are replayed in recorded order; the last one is repeated once exhausted.
+type Replay struct {
+	mu        sync.Mutex
+	responses map[string][]Entry
+	served    map[string]int
+}
+
+// ByHost returns the entries of the given host, e.g. of one instance of a file shared by several instances.
+func ByHost(entries []Entry, host string) []Entry {
+	var result []Entry
+	for _, e := range entries {
+		if e.Host == host {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// NewReplay creates a new replay handler from the given entries.
+func NewReplay(entries []Entry) *Replay {
+	r := &Replay{
+		responses: make(map[string][]Entry),
+		served:    make(map[string]int),
+	}
+	for _, e := range entries {
+		if e.Status == 0 {
+			// request failed without response
+			continue
+		}
+		r.responses[e.key()] = append(r.responses[e.key()], e)
+	}
+	return r
+}
+
+func (r *Replay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	e, ok := r.next(req)
+	if !ok {
+		l.With("method", req.Method, "path", req.URL.Path, "query", req.URL.RawQuery).Warn("No recording found")
+		http.Error(w, "no recording found for "+req.Method+" "+req.URL.RequestURI(), http.StatusNotFound)
+		return
+	}
+
+	for name, values := range e.ResponseHeaders {
+		if name == "Content-Length" {
+			continue
+		}
+		for _, v := range values {
+			w.Header().Add(name, v)
+		}
+	}
+	w.WriteHeader(e.Status)
+	_, _ = w.Write([]byte(e.ResponseBody))
+}
+
+func (r *Replay) next(req *http.Request) (Entry, bool) {
+	key := (&Entry{Method: req.Method, Path: req.URL.Path, Query: req.URL.RawQuery}).key()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	responses, ok := r.responses[key]
+	if !ok {
+		return Entry{}, false
+	}
+	i := min(r.served[key], len(responses)-1)
+	r.served[key]++
+	return responses[i], true
+}
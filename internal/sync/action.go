@@ -10,70 +10,76 @@ import (
 
 func setupActions(cfg *types.Config) (actions []syncAction) {
 	if cfg.Features.GeneralSettings {
-		actions = append(actions, action("profile info", actionProfileInfo))
+		actions = append(actions, action("profile info", actionProfileInfo, client.EndpointProfileUpdate))
 		if cfg.Features.ProtectionStatus {
-			actions = append(actions, action("protection", actionProtection))
+			actions = append(actions, action("protection", actionProtection, client.EndpointDNSConfig))
 		}
 		actions = append(actions,
-			action("parental", actionParental),
-			action("safe search config", actionSafeSearchConfig),
-			action("safe browsing", actionSafeBrowsing),
+			action("parental", actionParental, client.EndpointParentalEnable, client.EndpointParentalDisable),
+			action("safe search config", actionSafeSearchConfig, client.EndpointSafeSearchSettings),
+			action("safe browsing", actionSafeBrowsing,
+				client.EndpointSafeBrowsingEnable, client.EndpointSafeBrowsingDisable),
 		)
 	}
 	if cfg.Features.DNS.ServerConfig {
 		actions = append(actions,
-			action("DNS server config", actionDNSServerConfig),
+			action("DNS server config", actionDNSServerConfig, client.EndpointDNSConfig),
 		)
 	}
 	if cfg.Features.QueryLogConfig {
 		actions = append(actions,
-			action("query log config", actionQueryLogConfig),
+			action("query log config", actionQueryLogConfig, client.EndpointQueryLogConfigUpdate),
 		)
 	}
 	if cfg.Features.StatsConfig {
 		actions = append(actions,
-			action("stats config", actionStatsConfig),
+			action("stats config", actionStatsConfig, client.EndpointStatsConfigUpdate),
 		)
 	}
 	if cfg.Features.DNS.Rewrites {
 		actions = append(actions,
-			action("DNS rewrite settings", actionRewriteSettings),
-			action("DNS rewrite entries", actionRewriteEntries),
+			action("DNS rewrite settings", actionRewriteSettings, client.EndpointRewriteSettingsUpdate),
+			action("DNS rewrite entries", actionRewriteEntries,
+				client.EndpointRewriteAdd, client.EndpointRewriteDelete, client.EndpointRewriteUpdate),
 		)
 	}
 	if cfg.Features.Filters.Blacklist || cfg.Features.Filters.Whitelist || cfg.Features.Filters.UserRules {
 		actions = append(actions,
-			action("actionFilters", actionFilters),
+			action("actionFilters", actionFilters,
+				client.EndpointFilteringAddURL, client.EndpointFilteringRemoveURL, client.EndpointFilteringSetURL,
+				client.EndpointFilteringRefresh, client.EndpointFilteringSetRules, client.EndpointFilteringConfig),
 		)
 	}
 	if cfg.Features.Services {
 		actions = append(actions,
-			action("blocked services schedule", actionBlockedServicesSchedule),
+			action("blocked services schedule", actionBlockedServicesSchedule, client.EndpointBlockedServicesUpdate),
 		)
 	}
 	if cfg.Features.ClientSettings {
 		actions = append(actions,
-			action("client settings", actionClientSettings),
+			action("client settings", actionClientSettings,
+				client.EndpointClientsAdd, client.EndpointClientsUpdate, client.EndpointClientsDelete),
 		)
 	}
 	if cfg.Features.DNS.AccessLists {
 		actions = append(actions,
-			action("DNS access lists", actionDNSAccessLists),
+			action("DNS access lists", actionDNSAccessLists, client.EndpointAccessSet),
 		)
 	}
 	if cfg.Features.DHCP.ServerConfig {
 		actions = append(actions,
-			action("DHCP server config", actionDHCPServerConfig),
+			action("DHCP server config", actionDHCPServerConfig, client.EndpointDhcpSetConfig),
 		)
 	}
 	if cfg.Features.DHCP.StaticLeases {
 		actions = append(actions,
-			action("DHCP static leases", actionDHCPStaticLeases),
+			action("DHCP static leases", actionDHCPStaticLeases,
+				client.EndpointDhcpAddStaticLease, client.EndpointDhcpRemoveStaticLease),
 		)
 	}
 	if cfg.Features.TLSConfig {
 		actions = append(actions,
-			action("TLS config", tlsConfig),
+			action("TLS config", tlsConfig, client.EndpointTLSConfigure),
 		)
 	}
 	return actions
@@ -82,6 +88,7 @@ func setupActions(cfg *types.Config) (actions []syncAction) {
 type syncAction interface {
 	sync(ac *actionContext) error
 	name() string
+	endpoints() []string
 }
 
 type actionContext struct {
@@ -94,12 +101,14 @@ type actionContext struct {
 }
 
 type defaultAction struct {
-	myName string
-	doSync func(ac *actionContext) error
+	myName      string
+	doSync      func(ac *actionContext) error
+	myEndpoints []string
 }
 
-func action(name string, f func(ac *actionContext) error) syncAction {
-	return &defaultAction{myName: name, doSync: f}
+// action creates a sync action writing to the given replica endpoints.
+func action(name string, f func(ac *actionContext) error, endpoints ...string) syncAction {
+	return &defaultAction{myName: name, doSync: f, myEndpoints: endpoints}
 }
 
 func (d *defaultAction) sync(ac *actionContext) error {
@@ -109,3 +118,7 @@ func (d *defaultAction) sync(ac *actionContext) error {
 func (d *defaultAction) name() string {
 	return d.myName
 }
+
+func (d *defaultAction) endpoints() []string {
+	return d.myEndpoints
+}
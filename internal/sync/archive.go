@@ -0,0 +1,84 @@
+package sync
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/bakito/adguardhome-sync/internal/archive"
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+const (
+	defaultArchiveInterval = time.Minute
+	defaultArchiveLimit    = 100_000
+)
+
+// startArchiving archives the query logs until the context is done and closes the archive.
+func (w *worker) startArchiving(ctx context.Context, a *archive.Archive) {
+	cfg := &w.cfg.QueryLogArchive
+	if cfg.Interval == 0 {
+		cfg.Interval = defaultArchiveInterval
+	}
+	if cfg.Limit == 0 {
+		cfg.Limit = defaultArchiveLimit
+	}
+	l.With(
+		"dir", cfg.Dir,
+		"format", cfg.Format,
+		"interval", cfg.Interval,
+		"limit", cfg.Limit,
+	).Info("setup query log archive")
+	defer func() {
+		if err := a.Close(); err != nil {
+			l.With("error", err).Error("Error closing the query log archive")
+		}
+	}()
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	for {
+		w.archiveQueryLogs(ctx, a)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// archiveQueryLogs appends the query log entries added since the last run to the archive, one instance
+// after the other, until the context is done. The first run of an instance archives the entries still in its
+// query log, at most Limit.
+func (w *worker) archiveQueryLogs(ctx context.Context, a *archive.Archive) {
+	for _, inst := range append([]types.AdGuardInstance{*w.cfg.Origin}, w.cfg.UniqueReplicas()...) {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := w.archiveQueryLog(a, inst); err != nil {
+			l.With("error", err, "url", inst.URL).Warn("Error archiving the query log")
+		}
+	}
+}
+
+func (w *worker) archiveQueryLog(a *archive.Archive, inst types.AdGuardInstance) error {
+	cl, err := w.createClient(inst, w.cfg.ClientTimeout)
+	if err != nil {
+		return err
+	}
+	cursor, _ := a.Cursor(inst.WebHost)
+	limit := w.cfg.QueryLogArchive.Limit
+	items, newest, err := queryLogSince(cl, cursor, limit)
+	if err != nil {
+		return err
+	}
+	if len(items) >= limit {
+		l.With("url", inst.URL, "limit", limit).Warn("Query log archive limit reached, older entries are skipped")
+	}
+
+	entries := make([]archive.Entry, len(items))
+	for i, item := range items {
+		entries[i] = newQueryLogEntry(inst.WebHost, item)
+	}
+	slices.Reverse(entries)
+	return a.Write(inst.WebHost, entries, newest, time.Now())
+}
@@ -0,0 +1,93 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bakito/adguardhome-sync/internal/archive"
+	"github.com/bakito/adguardhome-sync/internal/client"
+	"github.com/bakito/adguardhome-sync/internal/fakeagh"
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+func TestArchiveQueryLogs(t *testing.T) {
+	origin := fakeagh.New()
+	ots := httptest.NewServer(origin)
+	defer ots.Close()
+	replica := fakeagh.New()
+	rts := httptest.NewServer(replica)
+	defer rts.Close()
+
+	now := time.Now()
+	origin.RecordQuery(fakeagh.Query{Time: now.Add(-2 * time.Second), Domain: "one.org", Client: "10.0.0.1"})
+	origin.RecordQuery(fakeagh.Query{Time: now.Add(-time.Second), Domain: "two.org", Client: "10.0.0.2"})
+	replica.RecordQuery(fakeagh.Query{Time: now.Add(-time.Second), Domain: "three.org", Client: "10.0.0.3"})
+
+	dir := t.TempDir()
+	cfg := &types.Config{
+		Origin:          &types.AdGuardInstance{URL: ots.URL},
+		Replicas:        []types.AdGuardInstance{{URL: rts.URL}},
+		QueryLogArchive: types.QueryLogArchive{Dir: dir, Limit: defaultArchiveLimit},
+	}
+	if err := cfg.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	w := &worker{cfg: cfg, createClient: client.New}
+	a, err := archive.New(cfg.QueryLogArchive)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer a.Close()
+
+	archived := func(t *testing.T) []string {
+		t.Helper()
+		b, err := os.ReadFile(filepath.Join(dir, "querylog-"+time.Now().UTC().Format(time.DateOnly)+".jsonl"))
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		var got []string
+		for line := range strings.SplitSeq(strings.TrimSpace(string(b)), "\n") {
+			var e archive.Entry
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			got = append(got, e.Instance+" "+e.Client+" "+e.Domain)
+		}
+		return got
+	}
+
+	t.Run("should archive the existing entries oldest first", func(t *testing.T) {
+		w.archiveQueryLogs(t.Context(), a)
+		want := []string{
+			cfg.Origin.WebHost + " 10.0.0.1 one.org",
+			cfg.Origin.WebHost + " 10.0.0.2 two.org",
+			cfg.Replicas[0].WebHost + " 10.0.0.3 three.org",
+		}
+		if got := archived(t); strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("archived = %q, want %q", got, want)
+		}
+	})
+	t.Run("should only archive the new entries", func(t *testing.T) {
+		replica.RecordQuery(fakeagh.Query{Time: now, Domain: "four.org", Client: "10.0.0.3"})
+		w.archiveQueryLogs(t.Context(), a)
+		got := archived(t)
+		if len(got) != 4 || got[3] != cfg.Replicas[0].WebHost+" 10.0.0.3 four.org" {
+			t.Errorf("archived = %q, want the new entry appended", got)
+		}
+	})
+	t.Run("should stop archiving when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		replica.RecordQuery(fakeagh.Query{Time: now.Add(time.Second), Domain: "five.org", Client: "10.0.0.3"})
+		w.startArchiving(ctx, a)
+		if got := archived(t); len(got) != 4 {
+			t.Errorf("archived = %q, want no entry archived after the shutdown", got)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/bakito/adguardhome-sync/api"
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+// roleKey the context key of the authenticated role.
+const roleKey = "role"
+
+var roleLevels = map[string]int{
+	types.RoleViewer:   1,
+	types.RoleOperator: 2,
+}
+
+// authenticator authenticates API requests with basic auth or bearer tokens and authorizes them by role.
+type authenticator struct {
+	api types.API
+
+	mu sync.Mutex
+	// verified caches the roles of verified basic auth credentials to avoid repeated bcrypt comparisons
+	verified map[[sha256.Size]byte]string
+}
+
+func newAuthenticator(api types.API) *authenticator {
+	return &authenticator{api: api, verified: make(map[[sha256.Size]byte]string)}
+}
+
+// enabled returns true if any credentials are configured.
+func (a *authenticator) enabled() bool {
+	return (a.api.Username != "" && a.api.Password != "") || len(a.api.Users) > 0 || len(a.api.Tokens) > 0
+}
+
+// require returns a middleware allowing only requests authenticated with at least the given role.
+func (a *authenticator) require(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		a.check(c, role)
+	}
+}
+
+// authorize is the middleware of the generated API routes. The required role is the scope of the
+// security requirements of the operation, operations without security requirements are public.
+func (a *authenticator) authorize(c *gin.Context) {
+	for _, key := range []string{api.BasicAuthScopes, api.BearerAuthScopes} {
+		if roles := c.GetStringSlice(key); len(roles) > 0 {
+			a.check(c, roles[0])
+			return
+		}
+	}
+}
+
+// check aborts the request if it is not authenticated with at least the given role.
+func (a *authenticator) check(c *gin.Context, role string) {
+	if !a.enabled() {
+		return
+	}
+	name, granted, ok := a.authenticate(c.Request)
+	if !ok {
+		c.Header("WWW-Authenticate", `Basic realm="Authorization Required"`)
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	if roleLevels[granted] < roleLevels[role] {
+		l.With("user", name, "role", granted, "path", c.Request.URL.Path).Warn("Access denied")
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "role " + role + " required"})
+		return
+	}
+	c.Set(gin.AuthUserKey, name)
+	c.Set(roleKey, granted)
+}
+
+// authenticate returns the name and role of the authenticated user or token.
+func (a *authenticator) authenticate(r *http.Request) (name, role string, ok bool) {
+	if token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found {
+		return a.authenticateToken(token)
+	}
+	if username, password, found := r.BasicAuth(); found {
+		return a.authenticateUser(username, password)
+	}
+	return "", "", false
+}
+
+func (a *authenticator) authenticateToken(token string) (name, role string, ok bool) {
+	sum := sha256.Sum256([]byte(token))
+	hash := hex.EncodeToString(sum[:])
+	for _, t := range a.api.Tokens {
+		if subtle.ConstantTimeCompare([]byte(strings.ToLower(t.Hash)), []byte(hash)) == 1 {
+			return t.Name, t.Role, true
+		}
+	}
+	return "", "", false
+}
+
+func (a *authenticator) authenticateUser(username, password string) (name, role string, ok bool) {
+	if a.api.Username != "" && a.api.Password != "" &&
+		subtle.ConstantTimeCompare([]byte(username), []byte(a.api.Username)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(password), []byte(a.api.Password)) == 1 {
+		return username, types.RoleOperator, true
+	}
+
+	key := sha256.Sum256([]byte(username + ":" + password))
+	a.mu.Lock()
+	role, ok = a.verified[key]
+	a.mu.Unlock()
+	if ok {
+		return username, role, true
+	}
+
+	for _, u := range a.api.Users {
+		if u.Username == username && bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)) == nil {
+			a.mu.Lock()
+			a.verified[key] = u.Role
+			a.mu.Unlock()
+			return username, u.Role, true
+		}
+	}
+	return "", "", false
+}
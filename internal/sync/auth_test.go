@@ -0,0 +1,104 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+func TestRoutesAuthorization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+	sum := sha256.Sum256([]byte("monitoring-token"))
+
+	w := &worker{cfg: &types.Config{API: types.API{
+		Username: "admin",
+		Password: "admin-pass",
+		Users: []types.APIUser{
+			{Username: "operator", Password: string(hash), Role: types.RoleOperator},
+			{Username: "viewer", Password: string(hash), Role: types.RoleViewer},
+		},
+		Tokens: []types.APIToken{{Name: "monitoring", Hash: hex.EncodeToString(sum[:]), Role: types.RoleViewer}},
+	}}}
+	r := gin.New()
+	w.routes(r)
+
+	basic := func(user, password string) func(r *http.Request) {
+		return func(r *http.Request) { r.SetBasicAuth(user, password) }
+	}
+	bearer := func(token string) func(r *http.Request) {
+		return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }
+	}
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		auth   func(r *http.Request)
+		want   int
+	}{
+		{name: "should require auth", method: http.MethodGet, path: "/api/v1/logs", want: http.StatusUnauthorized},
+		{
+			name: "should reject wrong passwords", method: http.MethodGet, path: "/api/v1/logs",
+			auth: basic("viewer", "wrong"), want: http.StatusUnauthorized,
+		},
+		{
+			name: "should reject unknown tokens", method: http.MethodGet, path: "/api/v1/logs",
+			auth: bearer("unknown"), want: http.StatusUnauthorized,
+		},
+		{
+			name: "should allow viewers to read", method: http.MethodGet, path: "/api/v1/logs",
+			auth: basic("viewer", "secret"), want: http.StatusOK,
+		},
+		{
+			name: "should allow viewer tokens to read", method: http.MethodGet, path: "/api/v1/logs",
+			auth: bearer("monitoring-token"), want: http.StatusOK,
+		},
+		{
+			name: "should forbid viewers to run admin actions", method: http.MethodPost, path: "/api/v1/clear-logs",
+			auth: bearer("monitoring-token"), want: http.StatusForbidden,
+		},
+		{
+			name: "should allow operators to run admin actions", method: http.MethodPost, path: "/api/v1/clear-logs",
+			auth: basic("operator", "secret"), want: http.StatusOK,
+		},
+		{
+			name: "should allow the legacy user to run admin actions", method: http.MethodPost, path: "/api/v1/clear-logs",
+			auth: basic("admin", "admin-pass"), want: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, http.NoBody)
+			if tt.auth != nil {
+				tt.auth(req)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", newAuthenticator(types.API{}).require(types.RoleOperator), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"github.com/bakito/adguardhome-sync/internal/client"
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+	"github.com/bakito/adguardhome-sync/internal/metrics"
+)
+
+// changeCounter passes all calls to the replica and counts the entities added, updated and removed.
+// Batches of rewrite entries are only counted if the whole batch was applied.
+type changeCounter struct {
+	client.Client
+	changes metrics.EntityChanges
+}
+
+var _ client.Client = (*changeCounter)(nil)
+
+// reset returns the counted changes and starts counting anew.
+func (cc *changeCounter) reset() metrics.EntityChanges {
+	changes := cc.changes
+	cc.changes = metrics.EntityChanges{}
+	return changes
+}
+
+// count adds n to the counter if the write succeeded.
+func count(counter *int, n int, err error) error {
+	if err == nil {
+		*counter += n
+	}
+	return err
+}
+
+func (cc *changeCounter) AddRewriteEntries(e ...model.RewriteEntry) error {
+	return count(&cc.changes.Added, len(e), cc.Client.AddRewriteEntries(e...))
+}
+
+func (cc *changeCounter) DeleteRewriteEntries(e ...model.RewriteEntry) error {
+	return count(&cc.changes.Removed, len(e), cc.Client.DeleteRewriteEntries(e...))
+}
+
+func (cc *changeCounter) UpdateRewriteEntries(e ...model.RewriteUpdate) error {
+	return count(&cc.changes.Updated, len(e), cc.Client.UpdateRewriteEntries(e...))
+}
+
+func (cc *changeCounter) AddFilter(whitelist bool, f model.Filter) error {
+	return count(&cc.changes.Added, 1, cc.Client.AddFilter(whitelist, f))
+}
+
+func (cc *changeCounter) DeleteFilter(whitelist bool, f model.Filter) error {
+	return count(&cc.changes.Removed, 1, cc.Client.DeleteFilter(whitelist, f))
+}
+
+func (cc *changeCounter) UpdateFilter(whitelist bool, f model.Filter) error {
+	return count(&cc.changes.Updated, 1, cc.Client.UpdateFilter(whitelist, f))
+}
+
+func (cc *changeCounter) AddClient(cl *model.Client) error {
+	return count(&cc.changes.Added, 1, cc.Client.AddClient(cl))
+}
+
+func (cc *changeCounter) UpdateClient(cl *model.Client) error {
+	return count(&cc.changes.Updated, 1, cc.Client.UpdateClient(cl))
+}
+
+func (cc *changeCounter) DeleteClient(cl *model.Client) error {
+	return count(&cc.changes.Removed, 1, cc.Client.DeleteClient(cl))
+}
+
+func (cc *changeCounter) AddDHCPStaticLease(lease model.DhcpStaticLease) error {
+	return count(&cc.changes.Added, 1, cc.Client.AddDHCPStaticLease(lease))
+}
+
+func (cc *changeCounter) DeleteDHCPStaticLease(lease model.DhcpStaticLease) error {
+	return count(&cc.changes.Removed, 1, cc.Client.DeleteDHCPStaticLease(lease))
+}
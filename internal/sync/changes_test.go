@@ -0,0 +1,38 @@
+package sync
+
+import (
+	"errors"
+	"testing"
+
+	gm "go.uber.org/mock/gomock"
+
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+	"github.com/bakito/adguardhome-sync/internal/metrics"
+	clientmock "github.com/bakito/adguardhome-sync/internal/mocks/client"
+)
+
+func TestChangeCounter(t *testing.T) {
+	cl := clientmock.NewMockClient(gm.NewController(t))
+	cc := &changeCounter{Client: cl}
+
+	cl.EXPECT().AddRewriteEntries(gm.Any(), gm.Any()).Return(nil)
+	cl.EXPECT().UpdateRewriteEntries(gm.Any()).Return(nil)
+	cl.EXPECT().DeleteFilter(false, gm.Any()).Return(nil)
+	cl.EXPECT().AddClient(gm.Any()).Return(errors.New("boom"))
+	cl.EXPECT().DeleteDHCPStaticLease(gm.Any()).Return(nil)
+
+	_ = cc.AddRewriteEntries(model.RewriteEntry{}, model.RewriteEntry{})
+	_ = cc.UpdateRewriteEntries(model.RewriteUpdate{})
+	_ = cc.DeleteFilter(false, model.Filter{})
+	if err := cc.AddClient(&model.Client{}); err == nil {
+		t.Error("AddClient() error = nil, want the replica error")
+	}
+	_ = cc.DeleteDHCPStaticLease(model.DhcpStaticLease{})
+
+	if got, want := cc.reset(), (metrics.EntityChanges{Added: 2, Updated: 1, Removed: 2}); got != want {
+		t.Errorf("reset() = %+v, want %+v", got, want)
+	}
+	if got := cc.reset(); got != (metrics.EntityChanges{}) {
+		t.Errorf("reset() = %+v, want no changes after a reset", got)
+	}
+}
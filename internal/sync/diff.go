@@ -0,0 +1,460 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bakito/adguardhome-sync/api"
+	"github.com/bakito/adguardhome-sync/internal/client"
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+	"github.com/bakito/adguardhome-sync/internal/types"
+	"github.com/bakito/adguardhome-sync/internal/versions"
+)
+
+const (
+	diffAdd    = "add"
+	diffUpdate = "update"
+	diffDelete = "delete"
+	diffSet    = "set"
+)
+
+// instanceDiff the differences of a replica to the origin, by feature.
+type instanceDiff struct {
+	Host     string        `json:"host"`
+	Origin   string        `json:"origin"`
+	Features []featureDiff `json:"features"`
+}
+
+// featureDiff the changes a sync would apply for a feature; no changes if the feature is in sync.
+type featureDiff struct {
+	Feature string       `json:"feature"`
+	Changes []diffChange `json:"changes"`
+	Skipped bool         `json:"skipped,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// diffChange a change of a sync, with the differing fields of updated items.
+type diffChange struct {
+	Operation string      `json:"operation"`
+	Item      string      `json:"item"`
+	Fields    []fieldDiff `json:"fields,omitempty"`
+}
+
+// fieldDiff a differing field; a value is nil if the field is not set.
+type fieldDiff struct {
+	Path    string  `json:"path"`
+	Replica *string `json:"replica"`
+	Origin  *string `json:"origin"`
+}
+
+// diff runs the sync actions of the replica with a client recording the changes instead of applying them.
+func (w *worker) diff(cfg *types.Config, replica types.AdGuardInstance) (*instanceDiff, error) {
+	oc, err := w.createClient(*cfg.Origin, cfg.ClientTimeout)
+	if err != nil {
+		return nil, err
+	}
+	sl := l.With("from", oc.Host())
+	o, err := loadOrigin(sl, cfg, oc)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := w.createClient(replica, cfg.ClientTimeout)
+	if err != nil {
+		return nil, err
+	}
+	replicaStatus, err := rc.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	dc := &diffClient{replicaReader: rc, current: make(map[string]any)}
+	ac := &actionContext{
+		cfg:           cfg,
+		rl:            sl.With("to", rc.Host(), "diff", true),
+		origin:        o,
+		replicaStatus: replicaStatus,
+		client:        dc,
+		replica:       replica,
+	}
+
+	d := &instanceDiff{Host: replica.WebHost, Origin: cfg.Origin.WebHost}
+	for _, action := range setupActions(cfg) {
+		fd := featureDiff{Feature: action.name(), Changes: []diffChange{}}
+		if _, ok := versions.Default.UnsupportedEndpoint(replicaStatus.Version, action.endpoints()...); ok {
+			fd.Skipped = true
+		} else if err := action.sync(ac); err != nil {
+			fd.Error = err.Error()
+		}
+		fd.Changes = append(fd.Changes, dc.changes...)
+		dc.changes = nil
+		d.Features = append(d.Features, fd)
+	}
+	return d, nil
+}
+
+func (w *worker) GetInstanceDiff(c *gin.Context, host string, params api.GetInstanceDiffParams) {
+	cfg, err := w.cfg.Select(selectors(params.Features), []string{host})
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, types.ErrUnknownReplica) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	d, err := w.diff(cfg, cfg.Replicas[0])
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, d)
+}
+
+// replicaReader the read methods of the client a diff passes to the replica.
+type replicaReader interface {
+	Host() string
+	Status() (*model.ServerStatus, error)
+	Stats() (*model.Stats, error)
+	QueryLog(query client.QueryLogQuery) (*model.QueryLog, error)
+	RewriteEntries() (*model.RewriteEntries, error)
+	RewriteSettings() (*model.RewriteSettings, error)
+	Filtering() (*model.FilterStatus, error)
+	SafeBrowsing() (bool, error)
+	Parental() (bool, error)
+	SafeSearchConfig() (*model.SafeSearchConfig, error)
+	ProfileInfo() (*model.ProfileInfo, error)
+	BlockedServicesSchedule() (*model.BlockedServicesSchedule, error)
+	Clients() (*model.Clients, error)
+	QueryLogConfig() (*model.QueryLogConfigWithIgnored, error)
+	StatsConfig() (*model.GetStatsConfigResponse, error)
+	AccessList() (*model.AccessList, error)
+	DNSConfig() (*model.DNSConfig, error)
+	DhcpConfig() (*model.DhcpStatus, error)
+	TLSConfig() (*model.TlsConfig, error)
+}
+
+// diffClient passes reads to the replica and records writes as changes without applying them.
+// The last read values are kept to report the differing fields of settings and updated items.
+// Only the reads are embedded: each write of the client must be implemented to be a client.Client.
+type diffClient struct {
+	replicaReader
+	changes []diffChange
+	current map[string]any
+}
+
+var _ client.Client = (*diffClient)(nil)
+
+func (dc *diffClient) record(operation, item string, fields ...fieldDiff) error {
+	dc.changes = append(dc.changes, diffChange{Operation: operation, Item: item, Fields: fields})
+	return nil
+}
+
+// set records a settings change with the fields differing from the last read value.
+func (dc *diffClient) set(item string, desired any) error {
+	return dc.record(diffSet, item, diffFields(dc.current[item], desired)...)
+}
+
+// read keeps the value read from the replica for the settings item.
+func read[T any](dc *diffClient, item string, v T, err error) (T, error) {
+	if err == nil {
+		dc.current[item] = v
+	}
+	return v, err
+}
+
+func (dc *diffClient) RewriteSettings() (*model.RewriteSettings, error) {
+	v, err := dc.replicaReader.RewriteSettings()
+	return read(dc, "rewrite settings", v, err)
+}
+
+func (dc *diffClient) Filtering() (*model.FilterStatus, error) {
+	v, err := dc.replicaReader.Filtering()
+	return read(dc, "filtering", v, err)
+}
+
+func (dc *diffClient) SafeSearchConfig() (*model.SafeSearchConfig, error) {
+	v, err := dc.replicaReader.SafeSearchConfig()
+	return read(dc, "safe search config", v, err)
+}
+
+func (dc *diffClient) ProfileInfo() (*model.ProfileInfo, error) {
+	v, err := dc.replicaReader.ProfileInfo()
+	return read(dc, "profile info", v, err)
+}
+
+func (dc *diffClient) BlockedServicesSchedule() (*model.BlockedServicesSchedule, error) {
+	v, err := dc.replicaReader.BlockedServicesSchedule()
+	return read(dc, "blocked services schedule", v, err)
+}
+
+func (dc *diffClient) Clients() (*model.Clients, error) {
+	v, err := dc.replicaReader.Clients()
+	return read(dc, "clients", v, err)
+}
+
+func (dc *diffClient) QueryLogConfig() (*model.QueryLogConfigWithIgnored, error) {
+	v, err := dc.replicaReader.QueryLogConfig()
+	return read(dc, "query log config", v, err)
+}
+
+func (dc *diffClient) StatsConfig() (*model.GetStatsConfigResponse, error) {
+	v, err := dc.replicaReader.StatsConfig()
+	return read(dc, "stats config", v, err)
+}
+
+func (dc *diffClient) AccessList() (*model.AccessList, error) {
+	v, err := dc.replicaReader.AccessList()
+	return read(dc, "access list", v, err)
+}
+
+func (dc *diffClient) DNSConfig() (*model.DNSConfig, error) {
+	v, err := dc.replicaReader.DNSConfig()
+	return read(dc, "DNS server config", v, err)
+}
+
+func (dc *diffClient) DhcpConfig() (*model.DhcpStatus, error) {
+	v, err := dc.replicaReader.DhcpConfig()
+	return read(dc, "DHCP server config", v, err)
+}
+
+func (dc *diffClient) TLSConfig() (*model.TlsConfig, error) {
+	v, err := dc.replicaReader.TLSConfig()
+	return read(dc, "TLS config", v, err)
+}
+
+func (dc *diffClient) ToggleProtection(enable bool) error {
+	return dc.record(diffSet, "protection "+enabled(enable))
+}
+
+func (dc *diffClient) AddRewriteEntries(e ...model.RewriteEntry) error {
+	for _, entry := range e {
+		_ = dc.record(diffAdd, rewriteItem(entry))
+	}
+	return nil
+}
+
+func (dc *diffClient) DeleteRewriteEntries(e ...model.RewriteEntry) error {
+	for _, entry := range e {
+		_ = dc.record(diffDelete, rewriteItem(entry))
+	}
+	return nil
+}
+
+func (dc *diffClient) UpdateRewriteEntries(e ...model.RewriteUpdate) error {
+	for _, u := range e {
+		_ = dc.record(diffUpdate, rewriteItem(deref(u.Target)), diffFields(u.Target, u.Update)...)
+	}
+	return nil
+}
+
+func (dc *diffClient) SetRewriteSettings(s *model.RewriteSettings) error {
+	return dc.set("rewrite settings", s)
+}
+
+func (dc *diffClient) ToggleFiltering(enable bool, interval int) error {
+	return dc.record(diffSet, fmt.Sprintf("filtering %s, update interval %dh", enabled(enable), interval))
+}
+
+func (dc *diffClient) AddFilter(whitelist bool, f model.Filter) error {
+	return dc.record(diffAdd, filterItem(whitelist, f))
+}
+
+func (dc *diffClient) DeleteFilter(whitelist bool, f model.Filter) error {
+	return dc.record(diffDelete, filterItem(whitelist, f))
+}
+
+func (dc *diffClient) UpdateFilter(whitelist bool, f model.Filter) error {
+	var current any
+	if fs, ok := dc.current["filtering"].(*model.FilterStatus); ok {
+		list := fs.Filters
+		if whitelist {
+			list = fs.WhitelistFilters
+		}
+		if i := slices.IndexFunc(deref(list), func(rf model.Filter) bool { return rf.Url == f.Url }); i >= 0 {
+			current = (*list)[i]
+		}
+	}
+	return dc.record(diffUpdate, filterItem(whitelist, f), diffFields(current, f)...)
+}
+
+func (*diffClient) RefreshFilters(bool) error {
+	return nil
+}
+
+func (dc *diffClient) SetCustomRules(rules *[]string) error {
+	var current any
+	if fs, ok := dc.current["filtering"].(*model.FilterStatus); ok {
+		current = fs.UserRules
+	}
+	return dc.record(diffSet, "user rules", diffFields(current, rules)...)
+}
+
+func (dc *diffClient) ToggleSafeBrowsing(enable bool) error {
+	return dc.record(diffSet, "safe browsing "+enabled(enable))
+}
+
+func (dc *diffClient) ToggleParental(enable bool) error {
+	return dc.record(diffSet, "parental control "+enabled(enable))
+}
+
+func (dc *diffClient) SetSafeSearchConfig(settings *model.SafeSearchConfig) error {
+	return dc.set("safe search config", settings)
+}
+
+func (dc *diffClient) SetProfileInfo(settings *model.ProfileInfo) error {
+	return dc.set("profile info", settings)
+}
+
+func (dc *diffClient) SetBlockedServicesSchedule(schedule *model.BlockedServicesSchedule) error {
+	return dc.set("blocked services schedule", schedule)
+}
+
+func (dc *diffClient) AddClient(cl *model.Client) error {
+	return dc.record(diffAdd, "client "+deref(cl.Name))
+}
+
+func (dc *diffClient) UpdateClient(cl *model.Client) error {
+	var current any
+	if cls, ok := dc.current["clients"].(*model.Clients); ok && cls.Clients != nil {
+		if i := slices.IndexFunc(*cls.Clients, func(rc model.Client) bool { return deref(rc.Name) == deref(cl.Name) }); i >= 0 {
+			current = (*cls.Clients)[i]
+		}
+	}
+	return dc.record(diffUpdate, "client "+deref(cl.Name), diffFields(current, cl)...)
+}
+
+func (dc *diffClient) DeleteClient(cl *model.Client) error {
+	return dc.record(diffDelete, "client "+deref(cl.Name))
+}
+
+func (dc *diffClient) SetQueryLogConfig(ql *model.QueryLogConfigWithIgnored) error {
+	return dc.set("query log config", ql)
+}
+
+func (dc *diffClient) SetStatsConfig(sc *model.PutStatsConfigUpdateRequest) error {
+	return dc.set("stats config", sc)
+}
+
+func (dc *diffClient) Setup() error {
+	return dc.record(diffSet, "initial setup")
+}
+
+func (dc *diffClient) SetAccessList(accessList *model.AccessList) error {
+	return dc.set("access list", accessList)
+}
+
+func (dc *diffClient) SetDNSConfig(config *model.DNSConfig) error {
+	return dc.set("DNS server config", config)
+}
+
+func (dc *diffClient) SetDhcpConfig(status *model.DhcpStatus) error {
+	return dc.set("DHCP server config", status)
+}
+
+func (dc *diffClient) AddDHCPStaticLease(lease model.DhcpStaticLease) error {
+	return dc.record(diffAdd, leaseItem(lease))
+}
+
+func (dc *diffClient) DeleteDHCPStaticLease(lease model.DhcpStaticLease) error {
+	return dc.record(diffDelete, leaseItem(lease))
+}
+
+func (dc *diffClient) SetTLSConfig(tls *model.TlsConfig) error {
+	return dc.set("TLS config", tls)
+}
+
+func enabled(enable bool) string {
+	if enable {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+func rewriteItem(e model.RewriteEntry) string {
+	return fmt.Sprintf("rewrite %s -> %s", deref(e.Domain), deref(e.Answer))
+}
+
+func filterItem(whitelist bool, f model.Filter) string {
+	if whitelist {
+		return fmt.Sprintf("allowlist %s (%s)", f.Name, f.Url)
+	}
+	return fmt.Sprintf("blocklist %s (%s)", f.Name, f.Url)
+}
+
+func leaseItem(lease model.DhcpStaticLease) string {
+	return fmt.Sprintf("static lease %s (%s, %s)", lease.Hostname, lease.Mac, lease.Ip)
+}
+
+// diffFields compares the JSON representation of the values and returns the differing fields sorted by path.
+func diffFields(replica, origin any) []fieldDiff {
+	rf := flatten(replica)
+	of := flatten(origin)
+	paths := maps.Clone(rf)
+	maps.Copy(paths, of)
+
+	var fields []fieldDiff
+	for _, p := range slices.Sorted(maps.Keys(paths)) {
+		r, rok := rf[p]
+		o, ook := of[p]
+		if rok && ook && r == o {
+			continue
+		}
+		fd := fieldDiff{Path: p}
+		if rok {
+			fd.Replica = &r
+		}
+		if ook {
+			fd.Origin = &o
+		}
+		fields = append(fields, fd)
+	}
+	return fields
+}
+
+// flatten returns the scalar values of the JSON representation of the value by their path.
+func flatten(v any) map[string]string {
+	values := make(map[string]string)
+	b, err := json.Marshal(v)
+	if err != nil {
+		return values
+	}
+	var decoded any
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if dec.Decode(&decoded) != nil {
+		return values
+	}
+	flattenInto(values, "", decoded)
+	return values
+}
+
+func flattenInto(values map[string]string, path string, v any) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, e := range t {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			flattenInto(values, p, e)
+		}
+	case []any:
+		for i, e := range t {
+			flattenInto(values, path+"["+strconv.Itoa(i)+"]", e)
+		}
+	case nil:
+	default:
+		if path == "" {
+			path = "value"
+		}
+		values[path] = fmt.Sprint(t)
+	}
+}
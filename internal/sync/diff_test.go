@@ -0,0 +1,138 @@
+package sync
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	gm "go.uber.org/mock/gomock"
+
+	"github.com/bakito/adguardhome-sync/internal/client"
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+	"github.com/bakito/adguardhome-sync/internal/fakeagh"
+	clientmock "github.com/bakito/adguardhome-sync/internal/mocks/client"
+	"github.com/bakito/adguardhome-sync/internal/types"
+	"github.com/bakito/adguardhome-sync/internal/utils"
+)
+
+func TestDiffClient_writes(t *testing.T) {
+	// the mock fails on any call passed to the replica
+	dc := &diffClient{replicaReader: clientmock.NewMockClient(gm.NewController(t)), current: make(map[string]any)}
+	dv := reflect.ValueOf(dc)
+	ct := reflect.TypeFor[client.Client]()
+	reads := reflect.TypeFor[replicaReader]()
+	for i := range ct.NumMethod() {
+		m := ct.Method(i)
+		if _, ok := reads.MethodByName(m.Name); ok {
+			continue
+		}
+		t.Run(m.Name+" should not write to the replica", func(t *testing.T) {
+			method := dv.MethodByName(m.Name)
+			args := make([]reflect.Value, m.Type.NumIn())
+			for j := range args {
+				in := m.Type.In(j)
+				if in.Kind() == reflect.Pointer {
+					args[j] = reflect.New(in.Elem())
+				} else {
+					args[j] = reflect.Zero(in)
+				}
+			}
+			var out []reflect.Value
+			if m.Type.IsVariadic() {
+				out = method.CallSlice(args)
+			} else {
+				out = method.Call(args)
+			}
+			if err := out[0].Interface(); err != nil {
+				t.Errorf("%s() error = %v", m.Name, err)
+			}
+		})
+	}
+}
+
+func TestDiffFields(t *testing.T) {
+	replica := &model.SafeSearchConfig{Enabled: new(true), Bing: new(false), Google: new(true)}
+	origin := &model.SafeSearchConfig{Enabled: new(true), Bing: new(true), Youtube: new(true)}
+	want := []fieldDiff{
+		{Path: "bing", Replica: new("false"), Origin: new("true")},
+		{Path: "google", Replica: new("true")},
+		{Path: "youtube", Origin: new("true")},
+	}
+	if diff := cmp.Diff(want, diffFields(replica, origin)); diff != "" {
+		t.Errorf("diffFields() mismatch (-want +got):\n%s", diff)
+	}
+	t.Run("should flatten lists", func(t *testing.T) {
+		got := diffFields(&model.DNSConfig{UpstreamDns: &[]string{"1.1.1.1"}},
+			&model.DNSConfig{UpstreamDns: &[]string{"1.1.1.1", "8.8.8.8"}})
+		want := []fieldDiff{{Path: "upstream_dns[1]", Origin: new("8.8.8.8")}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("diffFields() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestWorker_diff(t *testing.T) {
+	origin := fakeagh.New(fakeagh.WithState(fakeagh.SampleState()))
+	ots := httptest.NewServer(origin)
+	defer ots.Close()
+	replica := fakeagh.New()
+	rts := httptest.NewServer(replica)
+	defer rts.Close()
+
+	cfg := &types.Config{
+		Origin:   &types.AdGuardInstance{URL: ots.URL},
+		Replicas: []types.AdGuardInstance{{URL: rts.URL}},
+		Features: types.NewFeatures(true),
+	}
+	if err := cfg.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	w := &worker{cfg: cfg, createClient: client.New}
+	before := replica.State()
+
+	d, err := w.diff(cfg, cfg.Replicas[0])
+	if err != nil {
+		t.Fatalf("diff() error = %v", err)
+	}
+
+	t.Run("should not change the replica", func(t *testing.T) {
+		if !utils.JSONEquals(before, replica.State()) {
+			t.Error("diff() changed the replica")
+		}
+	})
+	t.Run("should report the differing features", func(t *testing.T) {
+		changes := make(map[string][]diffChange)
+		for _, f := range d.Features {
+			if f.Error != "" {
+				t.Errorf("feature %s error = %s", f.Feature, f.Error)
+			}
+			changes[f.Feature] = f.Changes
+		}
+		rewrites := changes["DNS rewrite entries"]
+		if len(rewrites) != len(origin.State().RewriteEntries) || rewrites[0].Operation != diffAdd {
+			t.Errorf("rewrite changes = %v, want to add all origin rewrites", rewrites)
+		}
+		if dns := changes["DNS server config"]; len(dns) != 1 || dns[0].Operation != diffSet || len(dns[0].Fields) == 0 {
+			t.Errorf("DNS server config changes = %v, want the differing fields", dns)
+		}
+		if sr := changes["stats config"]; len(sr) != 0 {
+			t.Errorf("stats config changes = %v, want none", sr)
+		}
+	})
+
+	t.Run("should be in sync after a sync", func(t *testing.T) {
+		if err := w.sync(); err != nil {
+			t.Fatalf("sync() error = %v", err)
+		}
+		d, err := w.diff(cfg, cfg.Replicas[0])
+		if err != nil {
+			t.Fatalf("diff() error = %v", err)
+		}
+		for _, f := range d.Features {
+			if f.Feature == "DNS rewrite entries" && len(f.Changes) != 0 {
+				t.Errorf("rewrite changes = %v, want none", f.Changes)
+			}
+		}
+	})
+}
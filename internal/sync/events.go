@@ -0,0 +1,148 @@
+package sync
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/bakito/adguardhome-sync/api"
+	"github.com/bakito/adguardhome-sync/internal/log"
+)
+
+const (
+	eventRun     = "run"
+	eventReplica = "replica"
+	eventAction  = "action"
+
+	replicaStarted   = "started"
+	replicaSucceeded = "succeeded"
+	replicaFailed    = "failed"
+	actionApplied    = "applied"
+	actionFailed     = "failed"
+	actionSkipped    = "skipped"
+
+	// streamBuffer the number of entries buffered per stream before entries are dropped.
+	streamBuffer = 100
+	// keepAliveInterval the interval of keep alive comments on idle streams.
+	keepAliveInterval = 15 * time.Second
+)
+
+// syncEvent a structured event of a sync run.
+type syncEvent struct {
+	Type    string    `json:"type"`
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Status  string    `json:"status"`
+	Run     *syncRun  `json:"run,omitempty"`
+	Replica string    `json:"replica,omitempty"`
+	Action  string    `json:"action,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+func (w *worker) publishRun(run syncRun) {
+	e := syncEvent{Type: eventRun, Level: "info", Status: run.Status, Run: &run}
+	if run.Status == runFailed {
+		e.Level = "error"
+		e.Error = run.Error
+	}
+	w.publish(e)
+}
+
+func (w *worker) publishReplica(replica, status string, err error) {
+	w.publish(withError(syncEvent{Type: eventReplica, Level: "info", Status: status, Replica: replica}, err))
+}
+
+func (w *worker) publishAction(replica, action, status string, err error) {
+	e := syncEvent{Type: eventAction, Level: "info", Status: status, Replica: replica, Action: action}
+	if status == actionSkipped {
+		e.Level = "warn"
+	}
+	w.publish(withError(e, err))
+}
+
+func (w *worker) publish(e syncEvent) {
+	e.Time = time.Now()
+	w.events.Publish(e)
+}
+
+func withError(e syncEvent, err error) syncEvent {
+	if err != nil {
+		e.Level = "error"
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// streamFilter filters the streamed logs and events by minimum level and replica.
+type streamFilter struct {
+	level   zapcore.Level
+	replica string
+}
+
+func newStreamFilter(params api.StreamEventsParams) (streamFilter, error) {
+	f := streamFilter{level: zapcore.DebugLevel, replica: deref(params.Replica)}
+	if params.Level != nil {
+		var err error
+		if f.level, err = zapcore.ParseLevel(string(*params.Level)); err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
+
+func (f streamFilter) matches(level, replica string) bool {
+	if lvl, err := zapcore.ParseLevel(level); err == nil && lvl < f.level {
+		return false
+	}
+	return f.replica == "" || f.replica == replica
+}
+
+// logReplica returns the replica a log entry refers to.
+func logReplica(e log.Entry) string {
+	if to, ok := e.Fields["to"].(string); ok {
+		return to
+	}
+	return ""
+}
+
+func (w *worker) StreamEvents(c *gin.Context, params api.StreamEventsParams) {
+	filter, err := newStreamFilter(params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logs, unsubscribeLogs := log.Subscribe(streamBuffer)
+	defer unsubscribeLogs()
+	events, unsubscribeEvents := w.events.Subscribe(streamBuffer)
+	defer unsubscribeEvents()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+	c.Stream(func(out io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case e := <-logs:
+			if filter.matches(e.Level.String(), logReplica(e)) {
+				c.SSEvent("log", e)
+			}
+		case e := <-events:
+			if filter.matches(e.Level, e.Replica) {
+				c.SSEvent("sync", e)
+			}
+		case <-keepAlive.C:
+			_, _ = io.WriteString(out, ": keep-alive\n\n")
+		}
+		return true
+	})
+}
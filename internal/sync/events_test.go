@@ -0,0 +1,113 @@
+package sync
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/bakito/adguardhome-sync/api"
+)
+
+func TestHandleEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := &worker{}
+	r := gin.New()
+	api.RegisterHandlers(r, w)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	t.Run("should reject invalid levels", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/api/v1/events?level=foo")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("should stream the filtered events", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/api/v1/events?level=warn&replica=r1", http.NoBody)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		defer resp.Body.Close()
+		if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+			t.Errorf("Content-Type = %s, want text/event-stream", ct)
+		}
+
+		for !w.events.HasSubscribers() {
+			time.Sleep(time.Millisecond)
+		}
+		w.publishReplica("r1", replicaStarted, nil)
+		w.publishAction("r2", "DNS rewrite entries", actionFailed, errors.New("boom"))
+		w.publishAction("r1", "DNS rewrite entries", actionFailed, errors.New("boom"))
+
+		name, data := readEvent(t, bufio.NewScanner(resp.Body))
+		if name != "sync" {
+			t.Errorf("event = %s, want sync", name)
+		}
+		e := syncEvent{}
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if e.Type != eventAction || e.Replica != "r1" || e.Level != "error" || e.Error != "boom" {
+			t.Errorf("event = %v", e)
+		}
+	})
+}
+
+func TestStreamFilter_matches(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  streamFilter
+		level   string
+		replica string
+		want    bool
+	}{
+		{name: "should match without filter", filter: streamFilter{level: zapcore.DebugLevel}, level: "debug", want: true},
+		{name: "should match higher levels", filter: streamFilter{level: zapcore.WarnLevel}, level: "error", want: true},
+		{name: "should not match lower levels", filter: streamFilter{level: zapcore.WarnLevel}, level: "info"},
+		{name: "should match the replica", filter: streamFilter{replica: "r1"}, level: "info", replica: "r1", want: true},
+		{name: "should not match other replicas", filter: streamFilter{replica: "r1"}, level: "info", replica: "r2"},
+		{name: "should not match without replica", filter: streamFilter{replica: "r1"}, level: "info"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.matches(tt.level, tt.replica); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// readEvent reads the next server sent event, skipping comments.
+func readEvent(t *testing.T, sc *bufio.Scanner) (name, data string) {
+	t.Helper()
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			name = strings.TrimPrefix(line, "event:")
+		case strings.HasPrefix(line, "data:"):
+			data = strings.TrimPrefix(line, "data:")
+		case line == "" && name != "":
+			return name, data
+		}
+	}
+	t.Fatalf("no event received: %v", sc.Err())
+	return "", ""
+}
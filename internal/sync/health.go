@@ -0,0 +1,150 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultStatusInterval the default interval to poll the status of the instances.
+const defaultStatusInterval = 30 * time.Second
+
+var (
+	errStatusPending = errors.New("the status of the instances was not polled yet")
+	errNotReady      = errors.New("not ready")
+)
+
+// pollStatus polls the status of the instances into the status cache until the context is done.
+// Besides the interval, the status is refreshed after each sync run.
+func (w *worker) pollStatus(ctx context.Context) {
+	interval := w.cfg.API.Health.StatusInterval
+	if interval == 0 {
+		interval = defaultStatusInterval
+	}
+	l.With("status-interval", interval).Info("Starting status poller")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		w.statusCache.Store(w.fetchStatus())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.statusRefresh:
+		}
+	}
+}
+
+// refreshStatusAfter requests a refresh of the cached status once a sync run is finished.
+func (w *worker) refreshStatusAfter(run syncRun) {
+	if run.Status != runSucceeded && run.Status != runFailed {
+		return
+	}
+	select {
+	case w.statusRefresh <- struct{}{}:
+	default:
+		// a refresh is already pending
+	}
+}
+
+// status returns the cached status of the instances; it is fetched directly as long as none is cached.
+func (w *worker) status() *syncStatus {
+	cached := w.statusCache.Load()
+	if cached == nil {
+		cached = w.fetchStatus()
+	}
+	st := *cached
+	st.SyncRunning = w.running.Load()
+	st.Replicas = slices.Clone(cached.Replicas)
+	if st.SyncRunning {
+		for i := range st.Replicas {
+			st.Replicas[i].Status = "info"
+		}
+	}
+	return &st
+}
+
+// fetchStatus fetches the status of the origin and all replicas.
+func (w *worker) fetchStatus() *syncStatus {
+	st := &syncStatus{
+		Updated: time.Now(),
+		Origin:  w.getStatus(*w.cfg.Origin),
+	}
+	for _, replica := range w.cfg.UniqueReplicas() {
+		st.Replicas = append(st.Replicas, w.getStatus(replica))
+	}
+	slices.SortFunc(st.Replicas, func(i, j replicaStatus) int {
+		return strings.Compare(i.Host, j.Host)
+	})
+	return st
+}
+
+// ready evaluates the readiness policy against the cached status.
+// By default, the origin and all replicas must be healthy.
+func (w *worker) ready() error {
+	st := w.statusCache.Load()
+	if st == nil {
+		return errStatusPending
+	}
+	policy := w.cfg.API.Health
+	if !policy.OriginOptional && st.Origin.Status != "success" {
+		return fmt.Errorf("%w: origin %s is %s", errNotReady, st.Origin.Host, st.Origin.Status)
+	}
+
+	var healthy int
+	var unhealthy []string
+	for _, replica := range st.Replicas {
+		if replica.Status == "success" {
+			healthy++
+		} else {
+			unhealthy = append(unhealthy, replica.Host)
+		}
+	}
+	minReplicas := len(st.Replicas)
+	if policy.MinReplicas != nil {
+		minReplicas = *policy.MinReplicas
+	}
+	if healthy < minReplicas {
+		return fmt.Errorf("%w: %d of %d required replicas are healthy, unhealthy: %s",
+			errNotReady, healthy, minReplicas, strings.Join(unhealthy, ", "))
+	}
+	return nil
+}
+
+func (*worker) GetLivez(c *gin.Context) {
+	c.Status(http.StatusOK)
+}
+
+func (w *worker) HeadLivez(c *gin.Context) {
+	w.GetLivez(c)
+}
+
+func (w *worker) GetReadyz(c *gin.Context) {
+	if err := w.ready(); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (w *worker) HeadReadyz(c *gin.Context) {
+	if w.ready() != nil {
+		c.Status(http.StatusServiceUnavailable)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (w *worker) HeadHealthz(c *gin.Context) {
+	w.HeadReadyz(c)
+}
+
+func (w *worker) GetHealthz(c *gin.Context) {
+	w.HeadReadyz(c)
+}
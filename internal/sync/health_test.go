@@ -0,0 +1,169 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+func TestWorker_ready(t *testing.T) {
+	status := func(origin string, replicas ...string) *syncStatus {
+		st := &syncStatus{Origin: replicaStatus{Host: "origin", Status: origin}}
+		for i, s := range replicas {
+			st.Replicas = append(st.Replicas, replicaStatus{Host: "replica" + string(rune('1'+i)), Status: s})
+		}
+		return st
+	}
+	tests := []struct {
+		name   string
+		policy types.Health
+		status *syncStatus
+		want   error
+	}{
+		{name: "should not be ready before the first poll", want: errStatusPending},
+		{name: "should be ready if all are healthy", status: status("success", "success", "success")},
+		{name: "should require the origin", status: status("danger", "success"), want: errNotReady},
+		{name: "should require all replicas", status: status("success", "success", "danger"), want: errNotReady},
+		{
+			name: "should allow an unhealthy origin", policy: types.Health{OriginOptional: true},
+			status: status("danger", "success"),
+		},
+		{
+			name: "should allow unhealthy replicas", policy: types.Health{MinReplicas: new(0)},
+			status: status("success", "danger", "warning"),
+		},
+		{
+			name: "should require the minimum of replicas", policy: types.Health{MinReplicas: new(1)},
+			status: status("success", "danger", "success"),
+		},
+		{
+			name: "should fail below the minimum of replicas", policy: types.Health{MinReplicas: new(2)},
+			status: status("success", "danger", "success"), want: errNotReady,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &worker{cfg: &types.Config{API: types.API{Health: tt.policy}}}
+			if tt.status != nil {
+				w.statusCache.Store(tt.status)
+			}
+			if err := w.ready(); !errors.Is(err, tt.want) {
+				t.Errorf("ready() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorker_status(t *testing.T) {
+	w := newAPITestWorker()
+	t.Run("should fetch the status if none is cached", func(t *testing.T) {
+		st := w.status()
+		if st.Origin.Status != "danger" || st.Updated.IsZero() {
+			t.Errorf("status() = %+v, want the fetched status", st)
+		}
+	})
+	t.Run("should fetch the status of each replica once", func(t *testing.T) {
+		w := newAPITestWorker()
+		w.cfg.Replicas = append(w.cfg.Replicas, w.cfg.Replicas[0])
+		if st := w.fetchStatus(); len(st.Replicas) != 1 {
+			t.Errorf("fetchStatus() = %+v, want the duplicate replica once", st.Replicas)
+		}
+	})
+	t.Run("should return the cached status", func(t *testing.T) {
+		w.statusCache.Store(&syncStatus{
+			Origin:   replicaStatus{Host: "origin", Status: "success"},
+			Replicas: []replicaStatus{{Host: "replica", Status: "success"}},
+		})
+		w.running.Store(true)
+		defer w.running.Store(false)
+		st := w.status()
+		if !st.SyncRunning || st.Origin.Status != "success" || st.Replicas[0].Status != "info" {
+			t.Errorf("status() = %+v, want the cached status of a running sync", st)
+		}
+		if w.statusCache.Load().Replicas[0].Status != "success" {
+			t.Errorf("status() must not modify the cached status")
+		}
+	})
+}
+
+func TestWorker_pollStatus(t *testing.T) {
+	w := newAPITestWorker()
+	w.cfg.API.Health.StatusInterval = time.Hour
+	w.statusRefresh = make(chan struct{}, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.pollStatus(ctx)
+		close(done)
+	}()
+
+	waitForStatus := func(after time.Time) *syncStatus {
+		t.Helper()
+		for range 100 {
+			if st := w.statusCache.Load(); st != nil && st.Updated.After(after) {
+				return st
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatal("the status was not polled")
+		return nil
+	}
+	first := waitForStatus(time.Time{})
+
+	w.refreshStatusAfter(syncRun{Status: runRunning})
+	if len(w.statusRefresh) != 0 {
+		t.Errorf("refreshStatusAfter() requested a refresh for a running sync")
+	}
+	w.refreshStatusAfter(syncRun{Status: runSucceeded})
+	waitForStatus(first.Updated)
+
+	cancel()
+	<-done
+}
+
+func TestReadyz(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := newAPITestWorker()
+	w.cfg.API.Username, w.cfg.API.Password = "admin", "admin"
+	r := gin.New()
+	w.routes(r)
+	w.statusCache.Store(&syncStatus{
+		Origin:   replicaStatus{Host: "origin:3000", Status: "success"},
+		Replicas: []replicaStatus{{Host: "replica:3000", Status: "danger"}},
+	})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+		return rec
+	}
+
+	t.Run("should be alive without authentication", func(t *testing.T) {
+		if rec := get("/livez"); rec.Code != http.StatusOK {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+	})
+	t.Run("should report the unhealthy replicas", func(t *testing.T) {
+		rec := get("/readyz")
+		if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "unhealthy: replica:3000") {
+			t.Errorf("status = %d, body = %s, want the unhealthy replica", rec.Code, rec.Body)
+		}
+	})
+	t.Run("should apply the readiness policy", func(t *testing.T) {
+		w.cfg.API.Health.MinReplicas = new(0)
+		defer func() { w.cfg.API.Health.MinReplicas = nil }()
+		for _, path := range []string{"/readyz", "/healthz"} {
+			if rec := get(path); rec.Code != http.StatusOK {
+				t.Errorf("%s status = %d, want %d", path, rec.Code, http.StatusOK)
+			}
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package sync
+
+import (
+	"crypto/hmac"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bakito/adguardhome-sync/api"
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+const (
+	// hookTolerance the maximum age of a webhook timestamp; used signatures are remembered as long.
+	hookTolerance = 5 * time.Minute
+	// maxHookBody the maximum size of a webhook payload.
+	maxHookBody = 1 << 20
+)
+
+var (
+	errHookExpired   = errors.New("the timestamp is expired or in the future")
+	errHookSignature = errors.New("invalid signature")
+	errHookReplayed  = errors.New("the signature was already used")
+	errHookUnknown   = errors.New("unknown hook")
+)
+
+// hookVerifier verifies the signatures of webhooks and rejects replayed requests. The zero value is ready to use.
+type hookVerifier struct {
+	mu sync.Mutex
+	// used the expiry of the used signatures
+	used map[string]time.Time
+	now  func() time.Time
+}
+
+// verify verifies the timestamp and the signature of the request.
+func (v *hookVerifier) verify(hook types.APIHook, params api.TriggerHookParams, body []byte) error {
+	now := time.Now()
+	if v.now != nil {
+		now = v.now()
+	}
+	ts := time.Unix(params.XSyncTimestamp, 0)
+	if ts.Before(now.Add(-hookTolerance)) || ts.After(now.Add(hookTolerance)) {
+		return errHookExpired
+	}
+	return v.verifyOnce(params.XSyncSignature, api.SignHook(hook.Secret, params.XSyncTimestamp, body), now,
+		ts.Add(hookTolerance))
+}
+
+// verifyOnce compares the signature with the expected one and rejects signatures used before the expiry.
+func (v *hookVerifier) verifyOnce(signature, expected string, now, expiry time.Time) error {
+	if signature == "" || !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errHookSignature
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.used == nil {
+		v.used = make(map[string]time.Time)
+	}
+	for sig, exp := range v.used {
+		if now.After(exp) {
+			delete(v.used, sig)
+		}
+	}
+	if _, ok := v.used[signature]; ok {
+		return errHookReplayed
+	}
+	v.used[signature] = expiry
+	return nil
+}
+
+func (w *worker) TriggerHook(c *gin.Context, name string, params api.TriggerHookParams) {
+	hook, ok := w.cfg.API.Hook(name)
+	if !ok {
+		// unknown hooks are rejected like invalid signatures, to not reveal the configured hooks
+		l.With("hook", name, "remote-addr", c.Request.RemoteAddr, "error", errHookUnknown).Warn("Rejected webhook")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": errHookSignature.Error()})
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxHookBody))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := w.hooks.verify(hook, params, body); err != nil {
+		l.With("hook", name, "remote-addr", c.Request.RemoteAddr, "error", err).Warn("Rejected webhook")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	// the targets are those of the hook: the signatures don't cover the query, it must not select other ones
+	w.trigger(c, "hook:"+name, syncRequest{Features: hook.Features, Replicas: hook.Replicas})
+}
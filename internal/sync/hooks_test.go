@@ -0,0 +1,141 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bakito/adguardhome-sync/api"
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+func TestHookVerifier_verify(t *testing.T) {
+	hook := types.APIHook{Name: "dns-repo", Secret: "secret"}
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"ref":"refs/heads/main"}`)
+	ts := now.Unix()
+	signed := func(timestamp int64, signature string) api.TriggerHookParams {
+		return api.TriggerHookParams{XSyncTimestamp: timestamp, XSyncSignature: signature}
+	}
+
+	t.Run("should accept valid signatures once", func(t *testing.T) {
+		v := &hookVerifier{now: func() time.Time { return now }}
+		params := signed(ts, api.SignHook(hook.Secret, ts, body))
+		if err := v.verify(hook, params, body); err != nil {
+			t.Fatalf("verify() error = %v", err)
+		}
+		if err := v.verify(hook, params, body); !errors.Is(err, errHookReplayed) {
+			t.Errorf("verify() error = %v, want %v", err, errHookReplayed)
+		}
+	})
+	t.Run("should forget expired signatures", func(t *testing.T) {
+		v := &hookVerifier{now: func() time.Time { return now }}
+		if err := v.verify(hook, signed(ts, api.SignHook(hook.Secret, ts, body)), body); err != nil {
+			t.Fatalf("verify() error = %v", err)
+		}
+		now := now.Add(2 * hookTolerance)
+		v.now = func() time.Time { return now }
+		if err := v.verify(hook, signed(now.Unix(), api.SignHook(hook.Secret, now.Unix(), body)), body); err != nil {
+			t.Fatalf("verify() error = %v", err)
+		}
+		if len(v.used) != 1 {
+			t.Errorf("used signatures = %d, want 1", len(v.used))
+		}
+	})
+	tests := []struct {
+		name   string
+		params api.TriggerHookParams
+		want   error
+	}{
+		{name: "should reject other secrets", params: signed(ts, api.SignHook("other", ts, body)), want: errHookSignature},
+		{name: "should reject other bodies", params: signed(ts, api.SignHook(hook.Secret, ts, nil)), want: errHookSignature},
+		{
+			name: "should reject other timestamps", params: signed(ts+1, api.SignHook(hook.Secret, ts, body)),
+			want: errHookSignature,
+		},
+		{
+			name: "should reject expired timestamps", params: signed(ts-301, api.SignHook(hook.Secret, ts-301, body)),
+			want: errHookExpired,
+		},
+		{
+			name: "should reject future timestamps", params: signed(ts+301, api.SignHook(hook.Secret, ts+301, body)),
+			want: errHookExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &hookVerifier{now: func() time.Time { return now }}
+			if err := v.verify(hook, tt.params, body); !errors.Is(err, tt.want) {
+				t.Errorf("verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTriggerHook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := newAPITestWorker()
+	w.cfg.API.Username, w.cfg.API.Password = "admin", "admin"
+	w.cfg.API.Hooks = []types.APIHook{
+		{Name: "dns-repo", Secret: "secret", Features: []string{"dns.rewrites"}},
+	}
+	r := gin.New()
+	w.routes(r)
+
+	post := func(path, secret, body string) *httptest.ResponseRecorder {
+		ts := time.Now().Unix()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("X-Sync-Timestamp", strconv.FormatInt(ts, 10))
+		req.Header.Set("X-Sync-Signature", api.SignHook(secret, ts, []byte(body)))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("should trigger the configured features without basic auth", func(t *testing.T) {
+		rec := post("/api/v1/hooks/dns-repo", "secret", `{"ref":"main"}`)
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusAccepted, rec.Body)
+		}
+		run := syncRun{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &run); err != nil {
+			t.Fatalf("Unmarshal error = %v", err)
+		}
+		if run.Trigger != "hook:dns-repo" || len(run.Features) != 1 || run.Features[0] != "dns.rewrites" {
+			t.Errorf("run = %+v, want the hook features", run)
+		}
+	})
+	t.Run("should ignore selected features", func(t *testing.T) {
+		w.runs = newRunManager(func(syncRequest) error { return nil })
+		rec := post("/api/v1/hooks/dns-repo?features=filters", "secret", `{"ref":"other"}`)
+		if rec.Code != http.StatusAccepted || !strings.Contains(rec.Body.String(), `"features":["dns.rewrites"]`) {
+			t.Errorf("status = %d, body = %s, want the hook features", rec.Code, rec.Body)
+		}
+	})
+	t.Run("should reject invalid signatures", func(t *testing.T) {
+		if rec := post("/api/v1/hooks/dns-repo", "wrong", "{}"); rec.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+	t.Run("should reject unknown hooks like invalid signatures", func(t *testing.T) {
+		unknown := post("/api/v1/hooks/unknown", "secret", "{}")
+		invalid := post("/api/v1/hooks/dns-repo", "wrong", "{}")
+		if unknown.Code != http.StatusUnauthorized || unknown.Body.String() != invalid.Body.String() {
+			t.Errorf("status = %d, body = %s, want %d %s", unknown.Code, unknown.Body, invalid.Code, invalid.Body)
+		}
+	})
+	t.Run("should require the signature headers", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/hooks/dns-repo", http.NoBody))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+	})
+}
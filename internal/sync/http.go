@@ -10,23 +10,80 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"go.uber.org/zap/zapcore"
 
+	"github.com/bakito/adguardhome-sync/api"
 	"github.com/bakito/adguardhome-sync/internal/log"
 	"github.com/bakito/adguardhome-sync/internal/metrics"
 	"github.com/bakito/adguardhome-sync/internal/sync/static"
+	"github.com/bakito/adguardhome-sync/internal/types"
 	"github.com/bakito/adguardhome-sync/version"
 
 	// go embed blank import.
 	_ "embed"
 )
 
-func (w *worker) handleSync(c *gin.Context) {
-	l.With("remote-addr", c.Request.RemoteAddr).Info("Starting sync from API")
-	w.sync()
+// metricsHandler the handler of the Prometheus metrics, created once on first use.
+var metricsHandler = sync.OnceValue(metrics.Handler)
+
+// openAPISpec the OpenAPI document of the API, decoded once on first use.
+var openAPISpec = sync.OnceValues(api.GetSwagger)
+
+// the worker implements the handlers of the routes generated from the OpenAPI document.
+var _ api.ServerInterface = (*worker)(nil)
+
+func (w *worker) TriggerSync(c *gin.Context, params api.TriggerSyncParams) {
+	w.trigger(c, "api", syncRequest{
+		Features: selectors(params.Features),
+		Replicas: selectors(params.Replicas),
+	})
+}
+
+// trigger queues a sync run for the validated request and responds with the run.
+func (w *worker) trigger(c *gin.Context, trigger string, req syncRequest) {
+	if _, err := w.cfg.Select(req.Features, req.Replicas); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	run := w.runs.trigger(trigger, req)
+	l.With("remote-addr", c.Request.RemoteAddr, "run", run.ID, "trigger", trigger).Info("Sync triggered from API")
+	c.Header("Location", "/api/v1/sync/"+run.ID)
+	c.JSON(http.StatusAccepted, run)
+}
+
+// selectors splits comma separated query values.
+func selectors(values *[]string) []string {
+	if values == nil {
+		return nil
+	}
+	var sel []string
+	for _, v := range *values {
+		for s := range strings.SplitSeq(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				sel = append(sel, s)
+			}
+		}
+	}
+	return sel
+}
+
+func (w *worker) ListSyncRuns(c *gin.Context) {
+	c.JSON(http.StatusOK, w.runs.list())
+}
+
+func (w *worker) GetSyncRun(c *gin.Context, id string) {
+	run, ok := w.runs.get(id)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "sync run not found"})
+		return
+	}
+	c.JSON(http.StatusOK, run)
 }
 
 func (w *worker) handleRoot(c *gin.Context) {
@@ -34,6 +91,7 @@ func (w *worker) handleRoot(c *gin.Context) {
 
 	c.HTML(http.StatusOK, "index.html", map[string]any{
 		"DarkMode":   w.cfg.API.DarkMode,
+		"Operator":   c.GetString(roleKey) != types.RoleViewer,
 		"Metrics":    w.cfg.API.Metrics.Enabled,
 		"Version":    version.Version,
 		"Build":      version.Build,
@@ -63,35 +121,160 @@ func percent(a, b *int) string {
 	return fmt.Sprintf("%.2f", (float64(*a)*100.0)/float64(*b))
 }
 
-func (*worker) handleLogs(c *gin.Context) {
-	c.Data(http.StatusOK, "text/plain", []byte(strings.Join(log.Logs(), "")))
+func (*worker) GetLogs(c *gin.Context, params api.GetLogsParams) {
+	filter, err := logFilter(params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	entries := log.Entries(filter)
+
+	if format := params.Format; (format != nil && *format == api.Json) ||
+		(format == nil && c.NegotiateFormat(binding.MIMEPlain, binding.MIMEJSON) == binding.MIMEJSON) {
+		if entries == nil {
+			entries = []log.Entry{}
+		}
+		c.JSON(http.StatusOK, entries)
+		return
+	}
+
+	var sb strings.Builder
+	for _, e := range entries {
+		sb.WriteString(e.String())
+	}
+	c.Data(http.StatusOK, "text/plain", []byte(sb.String()))
+}
+
+// logFilter creates the log filter from the level, logger, host and since query parameters.
+// Since is either a RFC3339 time or a duration before now.
+func logFilter(params api.GetLogsParams) (log.Filter, error) {
+	filter := log.Filter{Logger: deref(params.Logger), Host: deref(params.Host)}
+	if params.Level != nil {
+		level, err := zapcore.ParseLevel(string(*params.Level))
+		if err != nil {
+			return filter, err
+		}
+		filter.Level = level
+	}
+	if since := deref(params.Since); since != "" {
+		var err error
+		if filter.Since, err = parseTime("since", since, time.Now()); err != nil {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
+
+// parseTime parses a RFC3339 time or a duration before now.
+func parseTime(name, value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return t, fmt.Errorf("invalid %s %q: expected a RFC3339 time or a duration", name, value)
+	}
+	return t, nil
 }
 
-func (*worker) handleClearLogs(c *gin.Context) {
+// deref returns the value of an optional parameter or the zero value if not set.
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
+
+func (*worker) ClearLogs(c *gin.Context) {
 	log.Clear()
 	c.Status(http.StatusOK)
 }
 
-func (w *worker) handleStatus(c *gin.Context) {
+func (w *worker) GetStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, w.status())
 }
 
-func (w *worker) handleHealthz(c *gin.Context) {
-	status := w.status()
-
-	if status.Origin.Status != "success" {
-		c.Status(http.StatusServiceUnavailable)
+func (w *worker) GetMetrics(c *gin.Context) {
+	if !w.cfg.API.Metrics.Enabled {
+		c.Status(http.StatusNotFound)
 		return
 	}
+	metricsHandler()(c)
+}
 
-	for _, replica := range status.Replicas {
-		if replica.Status != "success" {
-			c.Status(http.StatusServiceUnavailable)
+// GetStats responds with the stats history of the last 24 hours, unless the range is selected.
+func (w *worker) GetStats(c *gin.Context, params api.GetStatsParams) {
+	if w.history == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "metrics are not enabled"})
+		return
+	}
+	now := time.Now()
+	from, to := now.Add(-24*time.Hour), now
+	var err error
+	if v := deref(params.From); v != "" {
+		if from, err = parseTime("from", v, now); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if v := deref(params.To); v != "" {
+		if to, err = parseTime("to", v, now); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 	}
+	if from.After(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return
+	}
+	var host string
+	if params.Instance != nil {
+		inst, _, ok := w.instance(*params.Instance)
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "instance not found"})
+			return
+		}
+		host = inst.Host
+	}
+	c.JSON(http.StatusOK, w.history.Query(from, to, host))
+}
 
-	c.Status(http.StatusOK)
+func (*worker) GetOpenAPI(c *gin.Context) {
+	spec, err := openAPISpec()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, spec)
+}
+
+// routes registers the API routes generated from the OpenAPI document and the dashboard.
+// Viewers may read, operators may additionally trigger syncs and admin actions.
+func (w *worker) routes(r gin.IRouter) {
+	auth := newAuthenticator(w.cfg.API)
+
+	api.RegisterHandlersWithOptions(r, w, api.GinServerOptions{
+		Middlewares:  []api.MiddlewareFunc{auth.authorize},
+		ErrorHandler: handleAPIError,
+	})
+
+	viewer := r.Group("/", auth.require(types.RoleViewer))
+	static.HandleResources(viewer, w.cfg.API.DarkMode)
+	viewer.GET("/", w.handleRoot)
+	viewer.GET("/instances/:host", w.handleInstance)
+	viewer.GET("/querylog", w.handleQueryLog)
+}
+
+// templates parses the templates of the dashboard pages.
+func templates() *template.Template {
+	t := template.Must(template.New("index.html").Parse(static.Index()))
+	t = template.Must(t.New("instance.html").Parse(static.Instance()))
+	return template.Must(t.New("querylog.html").Parse(static.QueryLog()))
+}
+
+func handleAPIError(c *gin.Context, err error, status int) {
+	c.JSON(status, gin.H{"error": err.Error()})
 }
 
 func (w *worker) listenAndServe() {
@@ -108,23 +291,9 @@ func (w *worker) listenAndServe() {
 	r := gin.New()
 	r.Use(gin.Recovery())
 
-	r.HEAD("/healthz", w.handleHealthz)
-	r.GET("/healthz", w.handleHealthz)
-
-	var group gin.IRouter = r
-	if w.cfg.API.Username != "" && w.cfg.API.Password != "" {
-		group = r.Group("/", gin.BasicAuth(map[string]string{w.cfg.API.Username: w.cfg.API.Password}))
-	}
-
-	group.POST("/api/v1/sync", w.handleSync)
-	group.GET("/api/v1/logs", w.handleLogs)
-	group.POST("/api/v1/clear-logs", w.handleClearLogs)
-	group.GET("/api/v1/status", w.handleStatus)
-	static.HandleResources(group, w.cfg.API.DarkMode)
-	group.GET("/", w.handleRoot)
+	w.routes(r)
+	go w.pollStatus(ctx)
 	if w.cfg.API.Metrics.Enabled {
-		group.GET("/metrics", metrics.Handler())
-
 		go w.startScraping()
 	}
 
@@ -134,8 +303,10 @@ func (w *worker) listenAndServe() {
 		BaseContext:       func(_ net.Listener) context.Context { return ctx },
 		ReadHeaderTimeout: 1 * time.Second,
 	}
+	// end the open event streams on shutdown
+	httpServer.RegisterOnShutdown(cancel)
 
-	r.SetHTMLTemplate(template.Must(template.New("index.html").Parse(static.Index())))
+	r.SetHTMLTemplate(templates())
 
 	go func() {
 		var err error
@@ -182,12 +353,13 @@ func (w *worker) listenAndServe() {
 		l.Info("API server stopped")
 	}
 
-	// manually cancel context if not using httpServer.RegisterOnShutdown(cancel)
+	// cancel the context in case the shutdown failed
 	cancel()
 }
 
 type syncStatus struct {
 	SyncRunning bool            `json:"syncRunning"`
+	Updated     time.Time       `json:"updated"`
 	Origin      replicaStatus   `json:"origin"`
 	Replicas    []replicaStatus `json:"replicas"`
 }
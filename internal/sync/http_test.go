@@ -1,7 +1,16 @@
 package sync
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bakito/adguardhome-sync/api"
+	"github.com/bakito/adguardhome-sync/internal/log"
 )
 
 func TestPercent(t *testing.T) {
@@ -33,3 +42,57 @@ func TestPercent(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleLogs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := &worker{}
+	r := gin.New()
+	api.RegisterHandlers(r, w)
+
+	log.Clear()
+	tl := log.GetLogger("test-logs")
+	tl.With("to", "r1").Warn("warning r1")
+	tl.With("to", "r2").Warn("warning r2")
+	tl.With("to", "r1").Info("info r1")
+
+	get := func(query string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/logs?"+query, http.NoBody)
+		if len(header) > 0 {
+			req.Header.Set("Accept", header[0])
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("should return the filtered entries as json", func(t *testing.T) {
+		rec := get("logger=test-logs&level=warn&host=r1&since=1m&format=json")
+		var entries []log.Entry
+		if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if len(entries) != 1 || entries[0].Message != "warning r1" {
+			t.Errorf("entries = %v, want [warning r1]", entries)
+		}
+	})
+	t.Run("should negotiate json", func(t *testing.T) {
+		rec := get("logger=unknown", "application/json")
+		if body := rec.Body.String(); body != "[]" {
+			t.Errorf("body = %s, want []", body)
+		}
+	})
+	t.Run("should return the filtered lines as text", func(t *testing.T) {
+		rec := get("logger=test-logs&host=r1")
+		body := rec.Body.String()
+		if strings.Count(body, "\n") != 2 || !strings.Contains(body, "info r1") || strings.Contains(body, "r2") {
+			t.Errorf("body = %s, want the lines of r1", body)
+		}
+	})
+	t.Run("should reject invalid filters", func(t *testing.T) {
+		for _, query := range []string{"level=foo", "since=yesterday"} {
+			if rec := get(query); rec.Code != http.StatusBadRequest {
+				t.Errorf("GET %s status = %d, want %d", query, rec.Code, http.StatusBadRequest)
+			}
+		}
+	})
+}
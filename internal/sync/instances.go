@@ -0,0 +1,151 @@
+package sync
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bakito/adguardhome-sync/internal/types"
+	"github.com/bakito/adguardhome-sync/version"
+)
+
+// instanceDetail the state of an instance shown on its dashboard page.
+type instanceDetail struct {
+	replicaStatus
+
+	Origin       bool        `json:"origin"`
+	Version      string      `json:"version,omitempty"`
+	Rewrites     *int        `json:"rewrites"`
+	Clients      *int        `json:"clients"`
+	Filters      *int        `json:"filters"`
+	Allowlists   *int        `json:"allowlists"`
+	UserRules    *int        `json:"user_rules"`
+	StaticLeases *int        `json:"static_leases"`
+	LastSync     *syncResult `json:"last_sync,omitempty"`
+}
+
+// syncResult the result of the last sync to a replica.
+type syncResult struct {
+	Status   string    `json:"status"`
+	Finished time.Time `json:"finished"`
+	Duration float64   `json:"duration"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// syncResults the results of the last sync by replica host. The zero value is ready to use.
+type syncResults struct {
+	mu      sync.RWMutex
+	results map[string]syncResult
+}
+
+func (r *syncResults) store(host string, result syncResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.results == nil {
+		r.results = make(map[string]syncResult)
+	}
+	r.results[host] = result
+}
+
+func (r *syncResults) get(host string) (syncResult, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result, ok := r.results[host]
+	return result, ok
+}
+
+// instance returns the origin or the replica with the given host, web host or URL.
+func (w *worker) instance(host string) (inst types.AdGuardInstance, isOrigin, ok bool) {
+	o := *w.cfg.Origin
+	if host == o.Host || host == o.WebHost || host == o.URL || host == o.WebURL {
+		return o, true, true
+	}
+	replicas, err := w.cfg.SelectReplicas(host)
+	if err != nil {
+		return inst, false, false
+	}
+	return replicas[0], false, true
+}
+
+// instanceDetail loads the detail of the instance; counts of unavailable features remain nil.
+func (w *worker) instanceDetail(inst types.AdGuardInstance, isOrigin bool) *instanceDetail {
+	d := &instanceDetail{
+		replicaStatus: replicaStatus{Host: inst.WebHost, URL: inst.WebURL},
+		Origin:        isOrigin,
+	}
+	if result, ok := w.results.get(inst.Host); ok {
+		d.LastSync = &result
+	}
+
+	cl, err := w.createClient(inst, w.cfg.ClientTimeout)
+	if err != nil {
+		d.Status = "danger"
+		d.Error = err.Error()
+		return d
+	}
+	st, err := cl.Status()
+	if err != nil {
+		d.Status = "danger"
+		d.Error = err.Error()
+		return d
+	}
+	d.Status = "success"
+	d.Version = st.Version
+	d.ProtectionEnabled = new(st.ProtectionEnabled)
+
+	if rw, err := cl.RewriteEntries(); err == nil && rw != nil {
+		d.Rewrites = new(len(*rw))
+	}
+	if cls, err := cl.Clients(); err == nil && cls != nil {
+		d.Clients = new(len(deref(cls.Clients)))
+	}
+	if fs, err := cl.Filtering(); err == nil && fs != nil {
+		d.Filters = new(len(deref(fs.Filters)))
+		d.Allowlists = new(len(deref(fs.WhitelistFilters)))
+		d.UserRules = new(countRules(deref(fs.UserRules)))
+	}
+	if dhcp, err := cl.DhcpConfig(); err == nil && dhcp != nil {
+		d.StaticLeases = new(len(deref(dhcp.StaticLeases)))
+	}
+	return d
+}
+
+// countRules counts the user rules without empty lines and comments.
+func countRules(rules []string) int {
+	var n int
+	for _, r := range rules {
+		if r != "" && r[0] != '!' && r[0] != '#' {
+			n++
+		}
+	}
+	return n
+}
+
+func (w *worker) GetInstance(c *gin.Context, host string) {
+	inst, isOrigin, ok := w.instance(host)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "instance not found"})
+		return
+	}
+	c.JSON(http.StatusOK, w.instanceDetail(inst, isOrigin))
+}
+
+// handleInstance renders the dashboard page of an instance; its data is loaded from the API.
+func (w *worker) handleInstance(c *gin.Context) {
+	inst, isOrigin, ok := w.instance(c.Param("host"))
+	if !ok {
+		c.String(http.StatusNotFound, "instance not found")
+		return
+	}
+	c.HTML(http.StatusOK, "instance.html", map[string]any{
+		"DarkMode": w.cfg.API.DarkMode,
+		"Operator": c.GetString(roleKey) != types.RoleViewer,
+		"Version":  version.Version,
+		"Build":    version.Build,
+		"Host":     inst.WebHost,
+		"URL":      inst.WebURL,
+		"Origin":   isOrigin,
+	})
+}
@@ -0,0 +1,131 @@
+package sync
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/bakito/adguardhome-sync/internal/client"
+	"github.com/bakito/adguardhome-sync/internal/fakeagh"
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+func TestInstances(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	origin := fakeagh.New(fakeagh.WithState(fakeagh.SampleState()))
+	ots := httptest.NewServer(origin)
+	defer ots.Close()
+	replica := fakeagh.New()
+	rts := httptest.NewServer(replica)
+	defer rts.Close()
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+
+	cfg := &types.Config{
+		Origin:   &types.AdGuardInstance{URL: ots.URL},
+		Replicas: []types.AdGuardInstance{{URL: rts.URL}},
+		Features: types.NewFeatures(true),
+		API:      types.API{Users: []types.APIUser{{Username: "viewer", Password: string(hash), Role: types.RoleViewer}}},
+	}
+	if err := cfg.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	w := &worker{cfg: cfg, createClient: client.New}
+	w.runs = newRunManager(w.syncSelected)
+	r := gin.New()
+	r.SetHTMLTemplate(templates())
+	w.routes(r)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+		req.SetBasicAuth("viewer", "secret")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+	detail := func(t *testing.T, host string) instanceDetail {
+		t.Helper()
+		rec := get("/api/v1/instances/" + host)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		d := instanceDetail{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &d); err != nil {
+			t.Fatalf("Unmarshal error = %v", err)
+		}
+		return d
+	}
+
+	t.Run("should get the origin detail", func(t *testing.T) {
+		d := detail(t, cfg.Origin.WebHost)
+		st := origin.State()
+		if !d.Origin || d.Status != "success" || d.Version == "" || deref(d.Rewrites) != len(st.RewriteEntries) ||
+			deref(d.Clients) != 1 || deref(d.Filters) != 1 || deref(d.Allowlists) != 1 || deref(d.StaticLeases) != 1 {
+			t.Errorf("detail = %+v, want the origin counts", d)
+		}
+		if d.LastSync != nil {
+			t.Errorf("last sync = %+v, want none for the origin", d.LastSync)
+		}
+	})
+	t.Run("should get the last sync result of a replica", func(t *testing.T) {
+		if err := w.sync(); err != nil {
+			t.Fatalf("sync() error = %v", err)
+		}
+		d := detail(t, cfg.Replicas[0].Host)
+		if d.Origin || d.LastSync == nil || d.LastSync.Status != replicaSucceeded || deref(d.Rewrites) == 0 {
+			t.Errorf("detail = %+v, last sync = %+v, want the synced replica", d, d.LastSync)
+		}
+	})
+	t.Run("should get the diff of a replica", func(t *testing.T) {
+		rec := get("/api/v1/instances/" + cfg.Replicas[0].WebHost + "/diff?features=dns.rewrites")
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"feature":"DNS rewrite entries","changes":[]`) {
+			t.Errorf("status = %d, body = %s, want the rewrites in sync", rec.Code, rec.Body)
+		}
+	})
+	t.Run("should not diff the origin", func(t *testing.T) {
+		if rec := get("/api/v1/instances/" + cfg.Origin.WebHost + "/diff"); rec.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+		}
+	})
+	t.Run("should reject unknown features", func(t *testing.T) {
+		if rec := get("/api/v1/instances/" + cfg.Replicas[0].WebHost + "/diff?features=x"); rec.Code != http.StatusBadRequest {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+	})
+	t.Run("should not find unknown instances", func(t *testing.T) {
+		if rec := get("/api/v1/instances/unknown"); rec.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+		}
+		if rec := get("/instances/unknown"); rec.Code != http.StatusNotFound {
+			t.Errorf("page status = %d, want %d", rec.Code, http.StatusNotFound)
+		}
+	})
+	t.Run("should render the instance page", func(t *testing.T) {
+		rec := get("/instances/" + cfg.Replicas[0].WebHost)
+		body := rec.Body.String()
+		if rec.Code != http.StatusOK || !strings.Contains(body, "Replica "+cfg.Replicas[0].WebHost) ||
+			!strings.Contains(body, `id="compare"`) {
+			t.Errorf("status = %d, want the replica page with the diff", rec.Code)
+		}
+		if strings.Contains(body, `id="sync"`) {
+			t.Error("the page offers a sync to a viewer")
+		}
+	})
+	t.Run("should link the instance pages from the dashboard", func(t *testing.T) {
+		rec := get("/")
+		body := rec.Body.String()
+		if rec.Code != http.StatusOK || !strings.Contains(body, `href="instances/`+cfg.Replicas[0].WebHost+`"`) {
+			t.Errorf("status = %d, want the dashboard linking the replica page", rec.Code)
+		}
+		if strings.Contains(body, `data-replica=`) {
+			t.Error("the dashboard offers a replica sync to a viewer")
+		}
+	})
+}
@@ -0,0 +1,237 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/gin-gonic/gin"
+
+	"github.com/bakito/adguardhome-sync/api"
+	"github.com/bakito/adguardhome-sync/internal/client"
+	"github.com/bakito/adguardhome-sync/internal/log"
+	"github.com/bakito/adguardhome-sync/internal/metrics"
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+var errUnreachable = errors.New("unreachable")
+
+func newAPITestWorker() *worker {
+	w := &worker{
+		cfg: &types.Config{
+			Origin:   &types.AdGuardInstance{URL: "http://origin:3000", WebHost: "origin:3000", WebURL: "http://origin:3000"},
+			Replicas: []types.AdGuardInstance{{URL: "http://replica:3000", WebHost: "replica:3000", WebURL: "http://replica:3000"}},
+			Features: types.NewFeatures(true),
+			API:      types.API{Metrics: types.Metrics{Enabled: true}},
+		},
+		createClient: func(types.AdGuardInstance, time.Duration) (client.Client, error) {
+			return nil, errUnreachable
+		},
+	}
+	w.runs = newRunManager(func(syncRequest) error { return nil })
+	w.history, _ = metrics.NewHistory("", 0)
+	return w
+}
+
+func TestRoutesMatchOpenAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	spec, err := api.GetSwagger()
+	if err != nil {
+		t.Fatalf("GetSwagger() error = %v", err)
+	}
+	if err := spec.Validate(context.Background()); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	// validate the paths only, independent of the configured servers
+	spec.Servers = nil
+	router, err := legacy.NewRouter(spec)
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+
+	w := newAPITestWorker()
+	r := gin.New()
+	w.routes(r)
+	log.GetLogger("openapi-test").With("to", "replica:3000").Info("validate")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		accept string
+		want   int
+	}{
+		{name: "should check the health", method: http.MethodGet, path: "/healthz", want: http.StatusServiceUnavailable},
+		{name: "should check the health with head", method: http.MethodHead, path: "/healthz", want: http.StatusServiceUnavailable},
+		{name: "should check the liveness", method: http.MethodGet, path: "/livez", want: http.StatusOK},
+		{name: "should check the liveness with head", method: http.MethodHead, path: "/livez", want: http.StatusOK},
+		{name: "should check the readiness", method: http.MethodGet, path: "/readyz", want: http.StatusServiceUnavailable},
+		{name: "should check the readiness with head", method: http.MethodHead, path: "/readyz", want: http.StatusServiceUnavailable},
+		{name: "should get the status", method: http.MethodGet, path: "/api/v1/status", want: http.StatusOK},
+		{name: "should get an instance", method: http.MethodGet, path: "/api/v1/instances/origin:3000", want: http.StatusOK},
+		{name: "should not find unknown instances", method: http.MethodGet, path: "/api/v1/instances/x", want: http.StatusNotFound},
+		{
+			name: "should fail the diff of unreachable replicas", method: http.MethodGet,
+			path: "/api/v1/instances/replica:3000/diff", want: http.StatusBadGateway,
+		},
+		{name: "should trigger a sync", method: http.MethodPost, path: "/api/v1/sync?features=dns", want: http.StatusAccepted},
+		{
+			name: "should reject unknown features", method: http.MethodPost,
+			path: "/api/v1/sync?features=unknown", want: http.StatusBadRequest,
+		},
+		{name: "should list the runs", method: http.MethodGet, path: "/api/v1/sync", want: http.StatusOK},
+		{name: "should not find unknown runs", method: http.MethodGet, path: "/api/v1/sync/unknown", want: http.StatusNotFound},
+		{name: "should get the logs", method: http.MethodGet, path: "/api/v1/logs", want: http.StatusOK},
+		{
+			name: "should get the logs as json", method: http.MethodGet,
+			path: "/api/v1/logs?format=json&level=info", want: http.StatusOK,
+		},
+		{
+			name: "should negotiate json logs", method: http.MethodGet,
+			path: "/api/v1/logs", accept: "application/json", want: http.StatusOK,
+		},
+		{name: "should reject invalid filters", method: http.MethodGet, path: "/api/v1/logs?since=x", want: http.StatusBadRequest},
+		{name: "should clear the logs", method: http.MethodPost, path: "/api/v1/clear-logs", want: http.StatusOK},
+		{name: "should get the metrics", method: http.MethodGet, path: "/metrics", want: http.StatusOK},
+		{name: "should get the stats history", method: http.MethodGet, path: "/api/v1/stats?from=720h", want: http.StatusOK},
+		{
+			name: "should get the stats history of an instance", method: http.MethodGet,
+			path: "/api/v1/stats?instance=replica:3000&from=48h&to=24h", want: http.StatusOK,
+		},
+		{name: "should reject invalid ranges", method: http.MethodGet, path: "/api/v1/stats?from=1h&to=2h", want: http.StatusBadRequest},
+		{name: "should not find the stats of unknown instances", method: http.MethodGet, path: "/api/v1/stats?instance=x", want: http.StatusNotFound},
+		{
+			name: "should fail the query log search of unreachable instances", method: http.MethodGet,
+			path: "/api/v1/querylog?search=example.com&status=blocked", want: http.StatusBadGateway,
+		},
+		{
+			name: "should reject invalid query log cursors", method: http.MethodGet,
+			path: "/api/v1/querylog?older_than=yesterday", want: http.StatusBadRequest,
+		},
+		{name: "should get the openapi document", method: http.MethodGet, path: "/api/v1/openapi.json", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, http.NoBody)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("%s %s status = %d, want %d", tt.method, tt.path, rec.Code, tt.want)
+			}
+
+			route, pathParams, err := router.FindRoute(req)
+			if err != nil {
+				t.Fatalf("FindRoute() error = %v", err)
+			}
+			input := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+			}
+			if err := openapi3filter.ValidateRequest(context.Background(), input); err != nil {
+				t.Errorf("ValidateRequest() error = %v", err)
+			}
+			res := &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 rec.Code,
+				Header:                 rec.Header(),
+				Body:                   io.NopCloser(bytes.NewReader(rec.Body.Bytes())),
+				Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+			}
+			if err := openapi3filter.ValidateResponse(context.Background(), res); err != nil {
+				t.Errorf("ValidateResponse() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestGeneratedClient(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := newAPITestWorker()
+	sum := sha256.Sum256([]byte("ci-token"))
+	w.cfg.API.Tokens = []types.APIToken{{Name: "ci", Hash: hex.EncodeToString(sum[:]), Role: types.RoleOperator}}
+	r := gin.New()
+	w.routes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	cl, err := api.NewClientWithResponses(srv.URL, api.WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer ci-token")
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() error = %v", err)
+	}
+
+	t.Run("should trigger a sync", func(t *testing.T) {
+		resp, err := cl.TriggerSyncWithResponse(context.Background(), &api.TriggerSyncParams{
+			Features: &[]string{"dns.rewrites"},
+		})
+		if err != nil {
+			t.Fatalf("TriggerSyncWithResponse() error = %v", err)
+		}
+		if resp.JSON202 == nil {
+			t.Fatalf("TriggerSyncWithResponse() status = %d, want %d", resp.StatusCode(), http.StatusAccepted)
+		}
+		if got := resp.HTTPResponse.Header.Get("Location"); got != "/api/v1/sync/"+resp.JSON202.Id {
+			t.Errorf("Location = %q, want the run path", got)
+		}
+		run, err := cl.GetSyncRunWithResponse(context.Background(), resp.JSON202.Id)
+		if err != nil {
+			t.Fatalf("GetSyncRunWithResponse() error = %v", err)
+		}
+		if run.JSON200 == nil || run.JSON200.Features == nil || (*run.JSON200.Features)[0] != "dns.rewrites" {
+			t.Errorf("GetSyncRunWithResponse() = %s, want the selected features", run.Body)
+		}
+	})
+	t.Run("should get the status", func(t *testing.T) {
+		resp, err := cl.GetStatusWithResponse(context.Background())
+		if err != nil {
+			t.Fatalf("GetStatusWithResponse() error = %v", err)
+		}
+		if resp.JSON200 == nil || resp.JSON200.Origin.Status != api.InstanceStatusStatusDanger {
+			t.Errorf("GetStatusWithResponse() = %s, want the origin to be unreachable", resp.Body)
+		}
+	})
+	t.Run("should reject unauthenticated clients", func(t *testing.T) {
+		anonymous, err := api.NewClientWithResponses(srv.URL)
+		if err != nil {
+			t.Fatalf("NewClientWithResponses() error = %v", err)
+		}
+		resp, err := anonymous.GetStatusWithResponse(context.Background())
+		if err != nil {
+			t.Fatalf("GetStatusWithResponse() error = %v", err)
+		}
+		if resp.StatusCode() != http.StatusUnauthorized {
+			t.Errorf("GetStatusWithResponse() status = %d, want %d", resp.StatusCode(), http.StatusUnauthorized)
+		}
+	})
+}
+
+func TestGetOpenAPI(t *testing.T) {
+	spec, err := openAPISpec()
+	if err != nil {
+		t.Fatalf("openAPISpec() error = %v", err)
+	}
+	for _, path := range []string{"/api/v1/sync", "/api/v1/logs", "/api/v1/clear-logs", "/api/v1/status", "/healthz", "/metrics"} {
+		if spec.Paths.Find(path) == nil {
+			t.Errorf("OpenAPI document does not describe %s", path)
+		}
+	}
+	if _, ok := spec.Components.SecuritySchemes["bearerAuth"]; !ok {
+		t.Errorf("OpenAPI document does not describe the bearer auth")
+	}
+}
@@ -0,0 +1,231 @@
+package sync
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bakito/adguardhome-sync/api"
+	"github.com/bakito/adguardhome-sync/internal/archive"
+	"github.com/bakito/adguardhome-sync/internal/client"
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+	"github.com/bakito/adguardhome-sync/internal/types"
+	"github.com/bakito/adguardhome-sync/version"
+)
+
+const (
+	defaultQueryLogSearchLimit = 100
+	maxQueryLogSearchLimit     = 1000
+)
+
+type instanceError struct {
+	Instance string `json:"instance"`
+	Error    string `json:"error"`
+}
+
+// queryLogSearch the merged query log entries of the instances.
+type queryLogSearch struct {
+	Entries []archive.Entry `json:"entries"`
+	Oldest  string          `json:"oldest,omitempty"`
+	Errors  []instanceError `json:"errors,omitempty"`
+}
+
+// queryLogPage the entries of an instance matching the search.
+type queryLogPage struct {
+	entries []archive.Entry
+	// more is set if the instance returned a full page, oldest is the time of its oldest entry
+	more   bool
+	oldest time.Time
+}
+
+// SearchQueryLog responds with the merged query log entries of the origin and the replicas.
+func (w *worker) SearchQueryLog(c *gin.Context, params api.SearchQueryLogParams) {
+	query := client.QueryLogQuery{
+		Limit:     defaultQueryLogSearchLimit,
+		Search:    deref(params.Search),
+		OlderThan: deref(params.OlderThan),
+	}
+	if params.Limit != nil {
+		if *params.Limit < 1 || *params.Limit > maxQueryLogSearchLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxQueryLogSearchLimit)})
+			return
+		}
+		query.Limit = *params.Limit
+	}
+	if params.Status != nil {
+		query.ResponseStatus = model.QueryLogParamsResponseStatus(*params.Status)
+		if !query.ResponseStatus.Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid status %q", *params.Status)})
+			return
+		}
+	}
+	if query.OlderThan != "" {
+		if _, err := time.Parse(time.RFC3339Nano, query.OlderThan); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid older_than %q: expected a RFC3339 time", query.OlderThan)})
+			return
+		}
+	}
+	// the client is also searched for by AdGuard Home, the entries are filtered by the exact client
+	clientFilter := deref(params.Client)
+	if query.Search == "" {
+		query.Search = clientFilter
+	}
+
+	result, err := w.searchQueryLog(query, clientFilter)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// handleQueryLog renders the query log search page; the entries are loaded from the API.
+func (w *worker) handleQueryLog(c *gin.Context) {
+	c.HTML(http.StatusOK, "querylog.html", map[string]any{
+		"DarkMode": w.cfg.API.DarkMode,
+		"Version":  version.Version,
+		"Build":    version.Build,
+	})
+}
+
+// searchQueryLog searches the query logs of the origin and the replicas concurrently. It fails only if no
+// instance could be searched.
+func (w *worker) searchQueryLog(query client.QueryLogQuery, clientFilter string) (*queryLogSearch, error) {
+	instances := append([]types.AdGuardInstance{*w.cfg.Origin}, w.cfg.UniqueReplicas()...)
+	pages := make([]queryLogPage, len(instances))
+	errs := make([]error, len(instances))
+
+	var wg sync.WaitGroup
+	for i, inst := range instances {
+		wg.Go(func() {
+			pages[i], errs[i] = w.instanceQueryLog(inst, query, clientFilter)
+		})
+	}
+	wg.Wait()
+
+	result := &queryLogSearch{}
+	var ok []queryLogPage
+	for i, err := range errs {
+		if err != nil {
+			l.With("error", err, "url", instances[i].URL).Warn("Error searching the query log")
+			result.Errors = append(result.Errors, instanceError{Instance: instances[i].WebHost, Error: err.Error()})
+		} else {
+			ok = append(ok, pages[i])
+		}
+	}
+	if len(ok) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	result.Entries, result.Oldest = mergeQueryLogs(ok, query.Limit)
+	return result, nil
+}
+
+func (w *worker) instanceQueryLog(
+	inst types.AdGuardInstance,
+	query client.QueryLogQuery,
+	clientFilter string,
+) (queryLogPage, error) {
+	var page queryLogPage
+	cl, err := w.createClient(inst, w.cfg.ClientTimeout)
+	if err != nil {
+		return page, err
+	}
+	ql, err := cl.QueryLog(query)
+	if err != nil {
+		return page, err
+	}
+	items := deref(ql.Data)
+	if len(items) > 0 {
+		page.more = len(items) >= query.Limit
+		page.oldest = queryLogTime(items[len(items)-1])
+	}
+	for _, item := range items {
+		e := newQueryLogEntry(inst.WebHost, item)
+		if clientFilter == "" || strings.EqualFold(e.Client, clientFilter) ||
+			strings.EqualFold(deref(item.ClientId), clientFilter) || strings.EqualFold(e.ClientName, clientFilter) {
+			page.entries = append(page.entries, e)
+		}
+	}
+	return page, nil
+}
+
+func newQueryLogEntry(instance string, item model.QueryLogItem) archive.Entry {
+	e := archive.Entry{
+		Instance: instance,
+		Time:     queryLogTime(item),
+		Client:   deref(item.Client),
+		Status:   deref(item.Status),
+		Upstream: deref(item.Upstream),
+		Cached:   deref(item.Cached),
+	}
+	if item.ClientInfo != nil {
+		e.ClientName = item.ClientInfo.Name
+	}
+	if item.Question != nil {
+		e.Domain = deref(item.Question.Name)
+		e.Type = deref(item.Question.Type)
+	}
+	if item.Reason != nil {
+		e.Reason = string(*item.Reason)
+	}
+	for _, r := range deref(item.Rules) {
+		if r.Text != nil {
+			e.Rules = append(e.Rules, *r.Text)
+		}
+	}
+	for _, a := range deref(item.Answer) {
+		if a.Value != nil {
+			e.Answers = append(e.Answers, *a.Value)
+		}
+	}
+	e.ElapsedMs, _ = strconv.ParseFloat(deref(item.ElapsedMs), 64)
+	return e
+}
+
+// mergeQueryLogs merges the pages by time, newest first, and returns the older_than of the next page.
+// Entries older than the oldest entry of an instance with more entries are left for the next page,
+// as the entries of that instance between them are not loaded yet. As the next page starts older than
+// the last entry, a page doesn't end within entries of the same time.
+func mergeQueryLogs(pages []queryLogPage, limit int) ([]archive.Entry, string) {
+	var boundary time.Time
+	entries := []archive.Entry{}
+	for _, p := range pages {
+		if p.more && p.oldest.After(boundary) {
+			boundary = p.oldest
+		}
+		entries = append(entries, p.entries...)
+	}
+	slices.SortStableFunc(entries, func(a, b archive.Entry) int {
+		return cmp.Or(b.Time.Compare(a.Time), strings.Compare(a.Instance, b.Instance))
+	})
+	entries = slices.DeleteFunc(entries, func(e archive.Entry) bool { return e.Time.Before(boundary) })
+
+	switch {
+	case len(entries) > limit:
+		cut := limit
+		for cut > 0 && entries[cut].Time.Equal(entries[cut-1].Time) {
+			cut--
+		}
+		if cut == 0 {
+			// the entries of the same time exceed the limit, they are returned together
+			cut = limit
+			for cut < len(entries) && entries[cut].Time.Equal(entries[0].Time) {
+				cut++
+			}
+		}
+		entries = entries[:cut]
+		return entries, entries[cut-1].Time.Format(time.RFC3339Nano)
+	case !boundary.IsZero():
+		return entries, boundary.Format(time.RFC3339Nano)
+	default:
+		return entries, ""
+	}
+}
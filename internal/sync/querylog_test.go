@@ -0,0 +1,185 @@
+package sync
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bakito/adguardhome-sync/internal/archive"
+	"github.com/bakito/adguardhome-sync/internal/client"
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+	"github.com/bakito/adguardhome-sync/internal/fakeagh"
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+func TestSearchQueryLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	origin := fakeagh.New()
+	ots := httptest.NewServer(origin)
+	defer ots.Close()
+	replica := fakeagh.New()
+	rts := httptest.NewServer(replica)
+	defer rts.Close()
+
+	now := time.Now()
+	origin.RecordQuery(fakeagh.Query{Time: now.Add(-3 * time.Second), Domain: "ads.example.com", Client: "10.0.0.1",
+		Reason: model.FilteredBlackList})
+	origin.RecordQuery(fakeagh.Query{Time: now.Add(-time.Second), Domain: "www.example.com", Client: "10.0.0.2"})
+	replica.RecordQuery(fakeagh.Query{Time: now.Add(-2 * time.Second), Domain: "ads.example.com", Client: "10.0.0.2",
+		Reason: model.FilteredBlackList, Upstream: "1.1.1.1:53", Elapsed: 1500 * time.Microsecond})
+	replica.RecordQuery(fakeagh.Query{Time: now, Domain: "other.org", Client: "10.0.0.1"})
+
+	cfg := &types.Config{
+		Origin:   &types.AdGuardInstance{URL: ots.URL},
+		Replicas: []types.AdGuardInstance{{URL: rts.URL}},
+		Features: types.NewFeatures(true),
+	}
+	if err := cfg.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	w := &worker{cfg: cfg, createClient: client.New}
+	r := gin.New()
+	r.SetHTMLTemplate(templates())
+	w.routes(r)
+
+	search := func(t *testing.T, query string, want int) queryLogSearch {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/querylog?"+query, http.NoBody))
+		if rec.Code != want {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, want, rec.Body)
+		}
+		var result queryLogSearch
+		if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatalf("Unmarshal error = %v", err)
+		}
+		return result
+	}
+	domains := func(result queryLogSearch) []string {
+		var d []string
+		for _, e := range result.Entries {
+			d = append(d, e.Instance+" "+e.Domain)
+		}
+		return d
+	}
+
+	t.Run("should merge the entries of all instances by time", func(t *testing.T) {
+		result := search(t, "", http.StatusOK)
+		want := []string{
+			cfg.Replicas[0].WebHost + " other.org",
+			cfg.Origin.WebHost + " www.example.com",
+			cfg.Replicas[0].WebHost + " ads.example.com",
+			cfg.Origin.WebHost + " ads.example.com",
+		}
+		if got := domains(result); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] ||
+			got[2] != want[2] || got[3] != want[3] {
+			t.Errorf("entries = %v, want %v", got, want)
+		}
+		if result.Oldest != "" || len(result.Errors) != 0 {
+			t.Errorf("result = %+v, want no more entries and no errors", result)
+		}
+		if e := result.Entries[2]; e.Reason != string(model.FilteredBlackList) || e.Upstream != "1.1.1.1:53" ||
+			e.ElapsedMs != 1.5 || e.Client != "10.0.0.2" || e.Type != "A" {
+			t.Errorf("entry = %+v, want the fields of the query log item", e)
+		}
+	})
+	t.Run("should search the blocked entries", func(t *testing.T) {
+		result := search(t, "search=ads&status=blocked", http.StatusOK)
+		if len(result.Entries) != 2 {
+			t.Errorf("entries = %v, want the blocked ads.example.com entries", domains(result))
+		}
+	})
+	t.Run("should filter by the client", func(t *testing.T) {
+		result := search(t, "client=10.0.0.1", http.StatusOK)
+		if got := domains(result); len(got) != 2 || got[0] != cfg.Replicas[0].WebHost+" other.org" {
+			t.Errorf("entries = %v, want the entries of 10.0.0.1", got)
+		}
+	})
+	t.Run("should page with the oldest entry", func(t *testing.T) {
+		var got []string
+		query := "limit=1"
+		for range 5 {
+			result := search(t, query, http.StatusOK)
+			got = append(got, domains(result)...)
+			if result.Oldest == "" {
+				break
+			}
+			query = "limit=1&older_than=" + result.Oldest
+		}
+		if len(got) != 4 {
+			t.Errorf("paged entries = %v, want all 4", got)
+		}
+	})
+	t.Run("should reject invalid limits", func(t *testing.T) {
+		search(t, "limit=1001", http.StatusBadRequest)
+	})
+	t.Run("should report unreachable instances", func(t *testing.T) {
+		rts.Close()
+		result := search(t, "", http.StatusOK)
+		if len(result.Entries) != 2 || len(result.Errors) != 1 || result.Errors[0].Instance != cfg.Replicas[0].WebHost {
+			t.Errorf("result = %+v, want the origin entries and the replica error", result)
+		}
+	})
+	t.Run("should render the search page", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/querylog", http.NoBody))
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+	})
+}
+
+func TestMergeQueryLogs(t *testing.T) {
+	now := time.Now()
+	entry := func(instance string, ago int) archive.Entry {
+		return archive.Entry{Instance: instance, Time: now.Add(-time.Duration(ago) * time.Second)}
+	}
+
+	t.Run("should keep entries of instances with more entries for the next page", func(t *testing.T) {
+		entries, oldest := mergeQueryLogs([]queryLogPage{
+			{entries: []archive.Entry{entry("a", 1), entry("a", 2)}, more: true, oldest: now.Add(-2 * time.Second)},
+			{entries: []archive.Entry{entry("b", 3)}},
+		}, 2)
+		if len(entries) != 2 || entries[1].Instance != "a" || oldest != now.Add(-2*time.Second).Format(time.RFC3339Nano) {
+			t.Errorf("mergeQueryLogs() = %v, %q, want the entries of a", entries, oldest)
+		}
+	})
+	t.Run("should truncate to the limit", func(t *testing.T) {
+		entries, oldest := mergeQueryLogs([]queryLogPage{
+			{entries: []archive.Entry{entry("a", 1), entry("a", 3)}},
+			{entries: []archive.Entry{entry("b", 2)}},
+		}, 2)
+		if len(entries) != 2 || entries[1].Instance != "b" || oldest != entries[1].Time.Format(time.RFC3339Nano) {
+			t.Errorf("mergeQueryLogs() = %v, %q, want the 2 newest entries", entries, oldest)
+		}
+	})
+	t.Run("should not truncate within entries of the same time", func(t *testing.T) {
+		entries, oldest := mergeQueryLogs([]queryLogPage{
+			{entries: []archive.Entry{entry("a", 1), entry("a", 2)}},
+			{entries: []archive.Entry{entry("b", 2), entry("b", 3)}},
+		}, 2)
+		if len(entries) != 1 || entries[0].Instance != "a" || oldest != entries[0].Time.Format(time.RFC3339Nano) {
+			t.Errorf("mergeQueryLogs() = %v, %q, want the newest entry", entries, oldest)
+		}
+	})
+	t.Run("should return all entries of the same time exceeding the limit", func(t *testing.T) {
+		entries, oldest := mergeQueryLogs([]queryLogPage{
+			{entries: []archive.Entry{entry("a", 1), entry("a", 2)}},
+			{entries: []archive.Entry{entry("b", 1)}},
+			{entries: []archive.Entry{entry("c", 1)}},
+		}, 2)
+		if len(entries) != 3 || oldest != now.Add(-time.Second).Format(time.RFC3339Nano) {
+			t.Errorf("mergeQueryLogs() = %v, %q, want the 3 entries of the same time", entries, oldest)
+		}
+	})
+	t.Run("should report the last page", func(t *testing.T) {
+		entries, oldest := mergeQueryLogs([]queryLogPage{{entries: []archive.Entry{entry("a", 1)}}, {}}, 2)
+		if len(entries) != 1 || oldest != "" {
+			t.Errorf("mergeQueryLogs() = %v, %q, want the last page", entries, oldest)
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package sync
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	runQueued    = "queued"
+	runRunning   = "running"
+	runSucceeded = "succeeded"
+	runFailed    = "failed"
+
+	// maxRunHistory the number of finished runs kept for status polling.
+	maxRunHistory = 100
+)
+
+var errSyncRunning = errors.New("sync already running")
+
+// syncRequest the features and replicas a sync is restricted to. Empty selectors select all.
+type syncRequest struct {
+	Features []string `json:"features,omitempty"`
+	Replicas []string `json:"replicas,omitempty"`
+}
+
+// merge widens the request to also cover the other request.
+func (r syncRequest) merge(other syncRequest) syncRequest {
+	return syncRequest{
+		Features: mergeSelectors(r.Features, other.Features),
+		Replicas: mergeSelectors(r.Replicas, other.Replicas),
+	}
+}
+
+func mergeSelectors(a, b []string) []string {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	merged := slices.Concat(a, b)
+	slices.Sort(merged)
+	return slices.Compact(merged)
+}
+
+// syncRun a single triggered sync.
+type syncRun struct {
+	syncRequest
+
+	ID       string     `json:"id"`
+	Trigger  string     `json:"trigger"`
+	Status   string     `json:"status"`
+	Queued   time.Time  `json:"queued"`
+	Started  *time.Time `json:"started,omitempty"`
+	Finished *time.Time `json:"finished,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// runManager executes the triggered syncs one after the other.
+// While a sync is running, at most one further run is queued; additional triggers join the queued run.
+type runManager struct {
+	mu      sync.Mutex
+	runs    map[string]*syncRun
+	order   []string
+	active  *syncRun
+	pending *syncRun
+	exec    func(req syncRequest) error
+	observe []func(run syncRun)
+}
+
+// newRunManager creates a run manager executing the runs with exec. Observers are notified on every status change.
+func newRunManager(exec func(req syncRequest) error, observe ...func(run syncRun)) *runManager {
+	return &runManager{
+		runs:    make(map[string]*syncRun),
+		exec:    exec,
+		observe: observe,
+	}
+}
+
+// trigger queues a new run or returns the already queued one, widened by the request.
+func (m *runManager) trigger(trigger string, req syncRequest) syncRun {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.pending != nil {
+		m.pending.syncRequest = m.pending.merge(req)
+		return *m.pending
+	}
+
+	run := &syncRun{
+		syncRequest: req,
+		ID:          uuid.NewString(),
+		Trigger:     trigger,
+		Status:      runQueued,
+		Queued:      time.Now(),
+	}
+	m.runs[run.ID] = run
+	m.order = append(m.order, run.ID)
+	m.prune()
+	m.notify(run)
+
+	if m.active == nil {
+		m.active = run
+		go m.process(run)
+	} else {
+		m.pending = run
+	}
+	return *run
+}
+
+// get returns the run with the given id.
+func (m *runManager) get(id string) (syncRun, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run, ok := m.runs[id]
+	if !ok {
+		return syncRun{}, false
+	}
+	return *run, true
+}
+
+// list returns all known runs, newest first.
+func (m *runManager) list() []syncRun {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	runs := make([]syncRun, 0, len(m.order))
+	for _, id := range slices.Backward(m.order) {
+		runs = append(runs, *m.runs[id])
+	}
+	return runs
+}
+
+func (m *runManager) process(run *syncRun) {
+	for run != nil {
+		m.mu.Lock()
+		run.Status = runRunning
+		run.Started = new(time.Now())
+		req := run.syncRequest
+		m.notify(run)
+		m.mu.Unlock()
+
+		err := m.exec(req)
+
+		m.mu.Lock()
+		run.Finished = new(time.Now())
+		if err != nil {
+			run.Status = runFailed
+			run.Error = err.Error()
+		} else {
+			run.Status = runSucceeded
+		}
+		m.notify(run)
+		run = m.pending
+		m.pending = nil
+		m.active = run
+		m.mu.Unlock()
+	}
+}
+
+// notify passes a copy of the run to the observers. The caller must hold the lock.
+func (m *runManager) notify(run *syncRun) {
+	for _, o := range m.observe {
+		o(*run)
+	}
+}
+
+// prune removes the oldest finished runs exceeding the history size.
+func (m *runManager) prune() {
+	for len(m.order) > maxRunHistory {
+		id := m.order[0]
+		if st := m.runs[id].Status; st == runQueued || st == runRunning {
+			return
+		}
+		delete(m.runs, id)
+		m.order = m.order[1:]
+	}
+}
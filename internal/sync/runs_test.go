@@ -0,0 +1,171 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/bakito/adguardhome-sync/api"
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+func waitForRun(t *testing.T, m *runManager, id, status string) syncRun {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if run, ok := m.get(id); ok && run.Status == status {
+			return run
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	run, _ := m.get(id)
+	t.Fatalf("run %s status = %s, want %s", id, run.Status, status)
+	return run
+}
+
+func TestRunManager(t *testing.T) {
+	t.Run("should queue at most one pending run", func(t *testing.T) {
+		release := make(chan struct{})
+		var executions int
+		m := newRunManager(func(syncRequest) error {
+			executions++
+			<-release
+			return nil
+		})
+
+		first := m.trigger("api", syncRequest{})
+		waitForRun(t, m, first.ID, runRunning)
+		second := m.trigger("api", syncRequest{})
+		third := m.trigger("cron", syncRequest{})
+
+		if second.ID == first.ID {
+			t.Error("second trigger should queue a new run")
+		}
+		if third.ID != second.ID || third.Status != runQueued {
+			t.Errorf("third trigger = %s/%s, want the queued run %s", third.ID, third.Status, second.ID)
+		}
+
+		close(release)
+		run := waitForRun(t, m, first.ID, runSucceeded)
+		if run.Started == nil || run.Finished == nil {
+			t.Error("finished run should have start and finish time")
+		}
+		waitForRun(t, m, second.ID, runSucceeded)
+		if executions != 2 {
+			t.Errorf("executions = %d, want 2", executions)
+		}
+		if runs := m.list(); len(runs) != 2 || runs[0].ID != second.ID {
+			t.Errorf("list() = %v, want newest run first", runs)
+		}
+	})
+	t.Run("should widen the queued run by further requests", func(t *testing.T) {
+		release := make(chan struct{})
+		var requests []syncRequest
+		m := newRunManager(func(req syncRequest) error {
+			requests = append(requests, req)
+			<-release
+			return nil
+		})
+
+		first := m.trigger("api", syncRequest{Features: []string{"dns"}})
+		waitForRun(t, m, first.ID, runRunning)
+		m.trigger("api", syncRequest{Features: []string{"filters"}, Replicas: []string{"b"}})
+		queued := m.trigger("api", syncRequest{Features: []string{"dns"}, Replicas: []string{"a"}})
+
+		if !slices.Equal(queued.Features, []string{"dns", "filters"}) || !slices.Equal(queued.Replicas, []string{"a", "b"}) {
+			t.Errorf("queued run = %v, want merged selectors", queued.syncRequest)
+		}
+		if all := m.trigger("cron", syncRequest{}); all.Features != nil || all.Replicas != nil {
+			t.Errorf("queued run = %v, want a full sync", all.syncRequest)
+		}
+
+		close(release)
+		waitForRun(t, m, queued.ID, runSucceeded)
+		if len(requests) != 2 || requests[1].Features != nil {
+			t.Errorf("executed requests = %v, want a targeted and a full sync", requests)
+		}
+	})
+	t.Run("should record failed runs", func(t *testing.T) {
+		m := newRunManager(func(syncRequest) error { return errors.New("boom") })
+		run := waitForRun(t, m, m.trigger("api", syncRequest{}).ID, runFailed)
+		if run.Error != "boom" {
+			t.Errorf("Error = %s, want boom", run.Error)
+		}
+	})
+}
+
+func TestHandleSync(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var executed syncRequest
+	w := &worker{cfg: &types.Config{
+		Features: types.NewFeatures(true),
+		Replicas: []types.AdGuardInstance{{URL: "http://replica:3000", Host: "replica:3000"}},
+	}}
+	w.runs = newRunManager(func(req syncRequest) error {
+		executed = req
+		return nil
+	})
+	r := gin.New()
+	api.RegisterHandlers(r, w)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/sync", http.NoBody))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("POST status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	run := syncRun{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &run); err != nil {
+		t.Fatalf("Unmarshal error = %v", err)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/api/v1/sync/"+run.ID {
+		t.Errorf("Location = %s, want /api/v1/sync/%s", loc, run.ID)
+	}
+
+	t.Run("should return the run status", func(t *testing.T) {
+		waitForRun(t, w.runs, run.ID, runSucceeded)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/sync/"+run.ID, http.NoBody))
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET status = %d, want %d", rec.Code, http.StatusOK)
+		}
+	})
+	t.Run("should pass the selectors to the run", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		url := "/api/v1/sync?features=dns.rewrites,filters&replicas=replica:3000"
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, url, http.NoBody))
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("POST status = %d, want %d", rec.Code, http.StatusAccepted)
+		}
+		run := syncRun{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &run); err != nil {
+			t.Fatalf("Unmarshal error = %v", err)
+		}
+		waitForRun(t, w.runs, run.ID, runSucceeded)
+		if !slices.Equal(executed.Features, []string{"dns.rewrites", "filters"}) ||
+			!slices.Equal(executed.Replicas, []string{"replica:3000"}) {
+			t.Errorf("executed request = %v", executed)
+		}
+	})
+	t.Run("should reject unknown selectors", func(t *testing.T) {
+		for _, query := range []string{"features=foo", "replicas=unknown"} {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/sync?"+query, http.NoBody))
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("POST %s status = %d, want %d", query, rec.Code, http.StatusBadRequest)
+			}
+		}
+	})
+	t.Run("should return not found for unknown runs", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/sync/unknown", http.NoBody))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("GET status = %d, want %d", rec.Code, http.StatusNotFound)
+		}
+	})
+}
@@ -1,14 +1,20 @@
 package sync
 
 import (
+	"context"
+	"errors"
 	"time"
 
+	"github.com/bakito/adguardhome-sync/internal/client"
+	"github.com/bakito/adguardhome-sync/internal/client/model"
 	"github.com/bakito/adguardhome-sync/internal/metrics"
 	"github.com/bakito/adguardhome-sync/internal/types"
 )
 
+// queryLogPageSize the number of query log entries fetched per request.
+const queryLogPageSize = 1000
+
 func (w *worker) startScraping() {
-	metrics.Init()
 	if w.cfg.API.Metrics.ScrapeInterval == 0 {
 		w.cfg.API.Metrics.ScrapeInterval = 30 * time.Second
 	}
@@ -29,23 +35,143 @@ func (w *worker) scrape() {
 	var iml metrics.InstanceMetricsList
 
 	iml.Metrics = append(iml.Metrics, w.getMetrics(*w.cfg.Origin))
-	for _, replica := range w.cfg.Replicas {
+	for _, replica := range w.cfg.UniqueReplicas() {
 		iml.Metrics = append(iml.Metrics, w.getMetrics(replica))
 	}
 	metrics.UpdateInstances(iml)
+	if w.history != nil {
+		w.history.Record(iml, time.Now())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.API.Metrics.ScrapeInterval)
+	defer cancel()
+	if err := metrics.Push(ctx); err != nil {
+		l.With("error", err).Warn("Error pushing metrics")
+	}
 }
 
+// getMetrics scrapes the instance; the returned metrics carry the error if any call failed.
 func (w *worker) getMetrics(inst types.AdGuardInstance) metrics.InstanceMetrics {
-	var im metrics.InstanceMetrics
-	client, err := w.createClient(inst, w.cfg.ClientTimeout)
+	im := metrics.InstanceMetrics{HostName: inst.Host}
+	start := time.Now()
+
+	cl, err := w.createClient(inst, w.cfg.ClientTimeout)
 	if err != nil {
-		l.With("error", err, "url", w.cfg.Origin.URL).Error("Error creating origin client")
-		return im
+		im.Err = err
+	} else {
+		var statusErr, statsErr error
+		im.Status, statusErr = cl.Status()
+		im.Stats, statsErr = cl.Stats()
+		im.Err = errors.Join(statusErr, statsErr)
+		if im.Err == nil {
+			// the cursor only advances if the entries are counted, which needs a successful scrape
+			im.QueryLog, im.Err = w.newQueryLogEntries(inst.Host, cl)
+		}
+		if im.Err == nil {
+			getOptionalMetrics(inst, cl, &im)
+		}
 	}
 
-	im.HostName = inst.Host
-	im.Status, _ = client.Status()
-	im.Stats, _ = client.Stats()
-	im.QueryLog, _ = client.QueryLog(w.cfg.API.Metrics.QueryLogLimit)
+	im.Duration = time.Since(start).Seconds()
+	if im.Err != nil {
+		l.With("error", im.Err, "url", inst.URL).Warn("Error scraping metrics")
+	}
 	return im
 }
+
+// newQueryLogEntries returns the query log entries added since the last scrape of the instance,
+// at most QueryLogLimit. The first scrape of an instance only sets the cursor, to not count the
+// existing log again after each restart.
+func (w *worker) newQueryLogEntries(host string, cl client.Client) ([]model.QueryLogItem, error) {
+	if w.queryLogCursors == nil {
+		w.queryLogCursors = make(map[string]time.Time)
+	}
+	cursor, ok := w.queryLogCursors[host]
+	if !ok {
+		ql, err := cl.QueryLog(client.QueryLogQuery{Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+		var newest time.Time
+		if ql.Data != nil && len(*ql.Data) > 0 {
+			newest = queryLogTime((*ql.Data)[0])
+		}
+		w.queryLogCursors[host] = newest
+		return nil, nil
+	}
+
+	entries, newest, err := queryLogSince(cl, cursor, w.cfg.API.Metrics.QueryLogLimit)
+	if err != nil {
+		return nil, err
+	}
+	w.queryLogCursors[host] = newest
+	return entries, nil
+}
+
+// queryLogSince pages through the query log of the instance, newest first, until the entries are not newer
+// than the cursor or the limit is reached. It returns the entries and the time of the newest entry.
+func queryLogSince(cl client.Client, cursor time.Time, limit int) ([]model.QueryLogItem, time.Time, error) {
+	var entries []model.QueryLogItem
+	newest := cursor
+	query := client.QueryLogQuery{}
+	for len(entries) < limit {
+		query.Limit = min(queryLogPageSize, limit-len(entries))
+		ql, err := cl.QueryLog(query)
+		if err != nil {
+			return nil, cursor, err
+		}
+		var data []model.QueryLogItem
+		if ql.Data != nil {
+			data = *ql.Data
+		}
+		for _, e := range data {
+			t := queryLogTime(e)
+			if !t.After(cursor) {
+				return entries, newest, nil
+			}
+			if t.After(newest) {
+				newest = t
+			}
+			entries = append(entries, e)
+		}
+		if len(data) < query.Limit || ql.Oldest == nil || *ql.Oldest == "" {
+			break
+		}
+		query.OlderThan = *ql.Oldest
+	}
+	return entries, newest, nil
+}
+
+func queryLogTime(e model.QueryLogItem) time.Time {
+	if e.Time == nil {
+		return time.Time{}
+	}
+	t, _ := time.Parse(time.RFC3339Nano, *e.Time)
+	return t
+}
+
+// getOptionalMetrics fetches the values of the extended metrics. They don't fail the scrape,
+// e.g. if the DHCP server is not available on the platform of the instance.
+func getOptionalMetrics(inst types.AdGuardInstance, cl client.Client, im *metrics.InstanceMetrics) {
+	var err error
+	var errs []error
+	if im.Filtering, err = cl.Filtering(); err != nil {
+		im.Filtering = nil
+		errs = append(errs, err)
+	}
+	if im.Clients, err = cl.Clients(); err != nil {
+		im.Clients = nil
+		errs = append(errs, err)
+	}
+	if im.RewriteEntries, err = cl.RewriteEntries(); err != nil {
+		im.RewriteEntries = nil
+		errs = append(errs, err)
+	}
+	if im.Dhcp, err = cl.DhcpConfig(); err != nil {
+		im.Dhcp = nil
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		l.With("error", errors.Join(errs...), "url", inst.URL).Debug("Error scraping optional metrics")
+	}
+}
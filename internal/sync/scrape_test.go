@@ -0,0 +1,125 @@
+package sync
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bakito/adguardhome-sync/internal/client"
+	"github.com/bakito/adguardhome-sync/internal/client/model"
+	"github.com/bakito/adguardhome-sync/internal/fakeagh"
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+func TestWorker_getMetrics(t *testing.T) {
+	t.Run("should scrape the instance", func(t *testing.T) {
+		ts := httptest.NewServer(fakeagh.New(fakeagh.WithState(fakeagh.SampleState())))
+		defer ts.Close()
+		inst := types.AdGuardInstance{URL: ts.URL}
+		if err := inst.Init(); err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+		w := &worker{cfg: &types.Config{API: types.API{Metrics: types.Metrics{QueryLogLimit: 10}}}, createClient: client.New}
+
+		im := w.getMetrics(inst)
+		if im.Err != nil {
+			t.Fatalf("getMetrics() error = %v", im.Err)
+		}
+		if im.HostName != inst.Host || im.Status == nil || im.Stats == nil || im.Duration <= 0 {
+			t.Errorf("getMetrics() = %+v, want the scraped values", im)
+		}
+		if im.Filtering == nil || im.Clients == nil || im.RewriteEntries == nil || im.Dhcp == nil {
+			t.Errorf("getMetrics() = %+v, want the optional values", im)
+		}
+	})
+	t.Run("should scrape the query log incrementally", func(t *testing.T) {
+		srv := fakeagh.New(fakeagh.WithState(fakeagh.SampleState()))
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+		inst := types.AdGuardInstance{URL: ts.URL}
+		if err := inst.Init(); err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+		w := &worker{cfg: &types.Config{API: types.API{Metrics: types.Metrics{QueryLogLimit: 3}}}, createClient: client.New}
+
+		if im := w.getMetrics(inst); im.Err != nil || len(im.QueryLog) != 0 {
+			t.Fatalf("first scrape = %d entries, %v; want none", len(im.QueryLog), im.Err)
+		}
+		if im := w.getMetrics(inst); len(im.QueryLog) != 0 {
+			t.Errorf("unchanged log = %d entries, want none", len(im.QueryLog))
+		}
+
+		now := time.Now()
+		for i := range 2 {
+			srv.RecordQuery(fakeagh.Query{Time: now.Add(time.Duration(i) * time.Second), Domain: "new.example.com"})
+		}
+		if im := w.getMetrics(inst); len(im.QueryLog) != 2 {
+			t.Errorf("new entries = %d, want 2", len(im.QueryLog))
+		}
+
+		for i := range 5 {
+			srv.RecordQuery(fakeagh.Query{Time: now.Add(time.Duration(i+10) * time.Second), Domain: "more.example.com"})
+		}
+		if im := w.getMetrics(inst); len(im.QueryLog) != 3 {
+			t.Errorf("new entries = %d, want the limit of 3", len(im.QueryLog))
+		}
+		if im := w.getMetrics(inst); len(im.QueryLog) != 0 {
+			t.Errorf("entries after the limit = %d, want none", len(im.QueryLog))
+		}
+	})
+	t.Run("should keep the query log cursor on failed scrapes", func(t *testing.T) {
+		srv := fakeagh.New(fakeagh.WithState(fakeagh.SampleState()))
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+		inst := types.AdGuardInstance{URL: ts.URL}
+		if err := inst.Init(); err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+		cl := &statsFailingClient{}
+		w := &worker{
+			cfg: &types.Config{API: types.API{Metrics: types.Metrics{QueryLogLimit: 10}}},
+			createClient: func(inst types.AdGuardInstance, timeout time.Duration) (client.Client, error) {
+				var err error
+				cl.Client, err = client.New(inst, timeout)
+				return cl, err
+			},
+		}
+
+		if im := w.getMetrics(inst); im.Err != nil {
+			t.Fatalf("first scrape error = %v", im.Err)
+		}
+		srv.RecordQuery(fakeagh.Query{Time: time.Now(), Domain: "new.example.com"})
+		cl.failing = true
+		if im := w.getMetrics(inst); !errors.Is(im.Err, errUnreachable) || len(im.QueryLog) != 0 {
+			t.Fatalf("failed scrape = %d entries, %v; want none, %v", len(im.QueryLog), im.Err, errUnreachable)
+		}
+		cl.failing = false
+		if im := w.getMetrics(inst); len(im.QueryLog) != 1 {
+			t.Errorf("entries after the failed scrape = %d, want 1", len(im.QueryLog))
+		}
+	})
+	t.Run("should report unreachable instances", func(t *testing.T) {
+		inst := types.AdGuardInstance{URL: "http://replica:3000", Host: "replica:3000"}
+		im := newAPITestWorker().getMetrics(inst)
+		if !errors.Is(im.Err, errUnreachable) {
+			t.Errorf("getMetrics() error = %v, want %v", im.Err, errUnreachable)
+		}
+		if im.HostName != inst.Host {
+			t.Errorf("host = %q, want %q", im.HostName, inst.Host)
+		}
+	})
+}
+
+// statsFailingClient fails to read the stats while failing is set.
+type statsFailingClient struct {
+	client.Client
+	failing bool
+}
+
+func (cl *statsFailingClient) Stats() (*model.Stats, error) {
+	if cl.failing {
+		return nil, errUnreachable
+	}
+	return cl.Client.Stats()
+}
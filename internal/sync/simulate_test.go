@@ -0,0 +1,124 @@
+package sync
+
+import (
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/bakito/adguardhome-sync/internal/client"
+	"github.com/bakito/adguardhome-sync/internal/fakeagh"
+	"github.com/bakito/adguardhome-sync/internal/types"
+	"github.com/bakito/adguardhome-sync/internal/utils"
+)
+
+func TestSyncSimulatedInstances(t *testing.T) {
+	origin := fakeagh.New(fakeagh.WithState(fakeagh.SampleState()), fakeagh.WithCredentials("origin", "pass"))
+	ots := httptest.NewServer(origin)
+	defer ots.Close()
+	replica := fakeagh.New(fakeagh.WithSetupNeeded())
+	rts := httptest.NewServer(replica)
+	defer rts.Close()
+
+	cfg := &types.Config{
+		Origin:   &types.AdGuardInstance{URL: ots.URL, Username: "origin", Password: "pass"},
+		Replicas: []types.AdGuardInstance{{URL: rts.URL, Username: "replica", Password: "pass", AutoSetup: true}},
+		Features: types.NewFeatures(true),
+	}
+	if err := cfg.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	w := &worker{cfg: cfg, createClient: client.New}
+
+	if err := w.sync(); err != nil {
+		t.Fatalf("sync() error = %v", err)
+	}
+
+	o := origin.State()
+	r := replica.State()
+	tests := []struct {
+		name string
+		a, b any
+	}{
+		{name: "rewrites", a: o.RewriteEntries, b: r.RewriteEntries},
+		{name: "user rules", a: o.Filtering.UserRules, b: r.Filtering.UserRules},
+		{name: "clients", a: o.Clients.Clients, b: r.Clients.Clients},
+		{name: "static leases", a: o.DHCP.StaticLeases, b: r.DHCP.StaticLeases},
+		{name: "blocked services", a: o.BlockedServices, b: r.BlockedServices},
+		{name: "upstream dns", a: o.DNSConfig.UpstreamDns, b: r.DNSConfig.UpstreamDns},
+		{name: "safe browsing", a: o.SafeBrowsing, b: r.SafeBrowsing},
+		{name: "profile", a: o.Profile, b: r.Profile},
+	}
+	for _, tt := range tests {
+		t.Run("should sync "+tt.name, func(t *testing.T) {
+			if !utils.JSONEquals(tt.a, tt.b) {
+				t.Errorf("replica %s = %v, want %v", tt.name, tt.b, tt.a)
+			}
+		})
+	}
+	t.Run("should sync filter urls", func(t *testing.T) {
+		if len(*r.Filtering.Filters) != 1 || (*r.Filtering.Filters)[0].Url != (*o.Filtering.Filters)[0].Url {
+			t.Errorf("replica filters = %v, want %v", *r.Filtering.Filters, *o.Filtering.Filters)
+		}
+	})
+}
+
+func TestSyncSimulatedInstancesSelected(t *testing.T) {
+	origin := fakeagh.New(fakeagh.WithState(fakeagh.SampleState()))
+	ots := httptest.NewServer(origin)
+	defer ots.Close()
+	selected := fakeagh.New()
+	sts := httptest.NewServer(selected)
+	defer sts.Close()
+	other := fakeagh.New()
+	rts := httptest.NewServer(other)
+	defer rts.Close()
+
+	cfg := &types.Config{
+		Origin:   &types.AdGuardInstance{URL: ots.URL},
+		Replicas: []types.AdGuardInstance{{URL: sts.URL}, {URL: rts.URL}},
+		Features: types.NewFeatures(true),
+	}
+	if err := cfg.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	w := &worker{cfg: cfg, createClient: client.New}
+	events, unsubscribe := w.events.Subscribe(100)
+
+	if err := w.syncSelected(syncRequest{Features: []string{"dns.rewrites"}, Replicas: []string{sts.URL}}); err != nil {
+		t.Fatalf("syncSelected() error = %v", err)
+	}
+	unsubscribe()
+
+	o := origin.State()
+	s := selected.State()
+	t.Run("should sync the selected feature to the selected replica", func(t *testing.T) {
+		if !utils.JSONEquals(o.RewriteEntries, s.RewriteEntries) {
+			t.Errorf("replica rewrites = %v, want %v", s.RewriteEntries, o.RewriteEntries)
+		}
+	})
+	t.Run("should not sync other features", func(t *testing.T) {
+		if utils.JSONEquals(o.Filtering.UserRules, s.Filtering.UserRules) {
+			t.Error("replica user rules should not be synced")
+		}
+	})
+	t.Run("should publish the sync events", func(t *testing.T) {
+		var got []string
+		for e := range events {
+			got = append(got, e.Type+" "+e.Action+" "+e.Status)
+		}
+		want := []string{
+			"replica  started",
+			"action DNS rewrite settings applied",
+			"action DNS rewrite entries applied",
+			"replica  succeeded",
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("events = %q, want %q", got, want)
+		}
+	})
+	t.Run("should not sync other replicas", func(t *testing.T) {
+		if r := other.State(); len(r.RewriteEntries) != 0 {
+			t.Errorf("other replica rewrites = %v, want none", r.RewriteEntries)
+		}
+	})
+}
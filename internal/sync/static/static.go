@@ -12,6 +12,12 @@ var (
 	//go:embed index.html
 	index string
 
+	//go:embed instance.html
+	instance string
+
+	//go:embed querylog.html
+	queryLog string
+
 	//go:embed favicon.ico
 	favicon []byte
 
@@ -54,6 +60,14 @@ func Index() string {
 	return index
 }
 
+func Instance() string {
+	return instance
+}
+
+func QueryLog() string {
+	return queryLog
+}
+
 func HandleResources(group gin.IRouter, dark bool) {
 	group.GET("/favicon.ico", handleFavicon)
 	group.GET("/logo.svg", handleLogo)
@@ -1,21 +1,28 @@
 package sync
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"runtime"
-	"slices"
+	"sync/atomic"
 	"time"
 
 	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
+	"github.com/bakito/adguardhome-sync/internal/archive"
 	"github.com/bakito/adguardhome-sync/internal/client"
 	"github.com/bakito/adguardhome-sync/internal/client/model"
 	"github.com/bakito/adguardhome-sync/internal/log"
 	"github.com/bakito/adguardhome-sync/internal/metrics"
+	"github.com/bakito/adguardhome-sync/internal/recording"
+	"github.com/bakito/adguardhome-sync/internal/tracing"
 	"github.com/bakito/adguardhome-sync/internal/types"
+	"github.com/bakito/adguardhome-sync/internal/utils"
 	"github.com/bakito/adguardhome-sync/internal/versions"
 	"github.com/bakito/adguardhome-sync/version"
 )
@@ -31,6 +38,48 @@ func Sync(cfg *types.Config) error {
 	if len(cfg.UniqueReplicas()) == 0 {
 		return errors.New("no replicas configured")
 	}
+	for _, inst := range append([]types.AdGuardInstance{*cfg.Origin}, cfg.UniqueReplicas()...) {
+		if err := client.CheckSchemas(inst); err != nil {
+			return err
+		}
+	}
+	if cfg.LogHistorySize != nil {
+		log.SetHistorySize(*cfg.LogHistorySize)
+	}
+
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			l.With("error", err).Error("Error flushing the traces")
+		}
+	}()
+	defer func() {
+		if err := recording.Close(); err != nil {
+			l.With("error", err).Error("Error closing the record files")
+		}
+	}()
+
+	var history *metrics.History
+	if cfg.API.Port != 0 && cfg.API.Metrics.Enabled {
+		if err := metrics.Init(cfg.API.Metrics); err != nil {
+			return err
+		}
+		historyFile := cfg.API.Metrics.HistoryFile
+		if historyFile == "" {
+			historyFile = metrics.DefaultHistoryFile()
+		}
+		if historyFile == "" {
+			l.Warn("No home directory to persist the stats history to, it is kept in memory only")
+		}
+		if history, err = metrics.NewHistory(historyFile, cfg.API.Metrics.HistoryRetention); err != nil {
+			return err
+		}
+	}
 
 	l.With(
 		"version", version.Version,
@@ -43,8 +92,28 @@ func Sync(cfg *types.Config) error {
 	cfg.Origin.AutoSetup = false
 
 	w := &worker{
-		cfg:          cfg,
-		createClient: client.New,
+		cfg:           cfg,
+		createClient:  client.NewCache().Get,
+		statusRefresh: make(chan struct{}, 1),
+		history:       history,
+	}
+	w.runs = newRunManager(w.syncSelected, w.publishRun, w.refreshStatusAfter)
+	if cfg.QueryLogArchive.Enabled() {
+		a, err := archive.New(cfg.QueryLogArchive)
+		if err != nil {
+			return err
+		}
+		ctx, stopArchiving := context.WithCancel(context.Background())
+		archived := make(chan struct{})
+		go func() {
+			w.startArchiving(ctx, a)
+			close(archived)
+		}()
+		// finish the running archive write and close the archive on shutdown
+		defer func() {
+			stopArchiving()
+			<-archived
+		}()
 	}
 	if cfg.Cron != "" {
 		w.cron = cron.New()
@@ -56,13 +125,15 @@ func Sync(cfg *types.Config) error {
 		}
 		cl = cl.With("next-execution", sched.Next(time.Now()))
 		_, err = w.cron.AddFunc(cfg.Cron, func() {
-			w.sync()
+			w.runs.trigger("cron", syncRequest{})
+			metrics.UpdateNextSync(sched.Next(time.Now()))
 		})
 		if err != nil {
 			cl.With("error", err).Error("Error during cron job setup")
 			return err
 		}
 		cl.Info("Setup cronjob")
+		metrics.UpdateNextSync(sched.Next(time.Now()))
 		if cfg.API.Port != 0 {
 			w.cron.Start()
 		} else {
@@ -75,7 +146,7 @@ func Sync(cfg *types.Config) error {
 		w.listenAndServe()
 	} else if cfg.RunOnStart {
 		l.Info("Running sync on startup")
-		w.sync()
+		_ = w.sync()
 	}
 
 	return nil
@@ -83,47 +154,27 @@ func Sync(cfg *types.Config) error {
 
 func runOnStartAsync(cfg *types.Config, w *worker) {
 	if cfg.RunOnStart {
-		go func() {
-			l.Info("Running sync on startup")
-			w.sync()
-		}()
+		l.Info("Running sync on startup")
+		w.runs.trigger("startup", syncRequest{})
 	}
 }
 
 type worker struct {
-	cfg          *types.Config
-	running      bool
-	cron         *cron.Cron
-	createClient func(instance types.AdGuardInstance, timeout time.Duration) (client.Client, error)
-	actions      []syncAction
-}
-
-func (w *worker) status() *syncStatus {
-	syncStatus := &syncStatus{
-		Origin: w.getStatus(*w.cfg.Origin),
-	}
-
-	for _, replica := range w.cfg.Replicas {
-		st := w.getStatus(replica)
-		if w.running {
-			st.Status = "info"
-		}
-		syncStatus.Replicas = append(syncStatus.Replicas, st)
-	}
-
-	slices.SortFunc(syncStatus.Replicas, func(i, j replicaStatus) int {
-		if i.Host < j.Host {
-			return -1
-		}
-		if i.Host > j.Host {
-			return 1
-		}
-		return 0
-	})
-
-	syncStatus.SyncRunning = w.running
-
-	return syncStatus
+	cfg           *types.Config
+	running       atomic.Bool
+	cron          *cron.Cron
+	runs          *runManager
+	events        utils.Broadcaster[syncEvent]
+	hooks         hookVerifier
+	results       syncResults
+	statusCache   atomic.Pointer[syncStatus]
+	statusRefresh chan struct{}
+	createClient  func(instance types.AdGuardInstance, timeout time.Duration) (client.Client, error)
+	actions       []syncAction
+	// history the stats history recorded by the metrics scrapes, nil if the metrics are disabled
+	history *metrics.History
+	// queryLogCursors the time of the newest scraped query log entry per instance
+	queryLogCursors map[string]time.Time
 }
 
 func (w *worker) getStatus(inst types.AdGuardInstance) replicaStatus {
@@ -154,35 +205,73 @@ func (w *worker) getStatus(inst types.AdGuardInstance) replicaStatus {
 	return st
 }
 
-func (w *worker) sync() {
-	if w.running {
+func (w *worker) sync() error {
+	return w.syncSelected(syncRequest{})
+}
+
+func (w *worker) syncSelected(req syncRequest) (err error) {
+	cfg, err := w.cfg.Select(req.Features, req.Replicas)
+	if err != nil {
+		l.With("error", err).Error("Invalid sync selection")
+		return err
+	}
+
+	if !w.running.CompareAndSwap(false, true) {
 		l.Info("Sync already running")
-		return
+		return errSyncRunning
+	}
+	defer w.running.Store(false)
+
+	ctx, span := tracing.Tracer().Start(context.Background(), "sync", trace.WithAttributes(
+		attribute.StringSlice("sync.features", req.Features),
+		attribute.StringSlice("sync.replicas", req.Replicas),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if len(req.Features) > 0 || len(req.Replicas) > 0 {
+		l.With("features", req.Features, "replicas", req.Replicas).Info("Running targeted sync")
 	}
-	w.running = true
-	defer func() {
-		w.running = false
-	}()
 
-	oc, err := w.createClient(*w.cfg.Origin, w.cfg.ClientTimeout)
+	oc, err := w.createClient(*cfg.Origin, cfg.ClientTimeout)
 	if err != nil {
-		l.With("error", err, "url", w.cfg.Origin.URL).Error("Error creating origin client")
-		return
+		l.With("error", err, "url", cfg.Origin.URL).Error("Error creating origin client")
+		return err
 	}
 
 	sl := l.With("from", oc.Host())
+	octx, ospan := tracing.Tracer().Start(ctx, "load origin",
+		trace.WithAttributes(attribute.String("sync.origin", cfg.Origin.Host)))
+	o, err := loadOrigin(sl, cfg, client.WithContext(octx, oc))
+	tracing.End(ospan, err)
+	if err != nil {
+		return err
+	}
+
+	w.actions = setupActions(cfg)
 
+	var errs []error
+	for _, replica := range cfg.UniqueReplicas() {
+		if err := w.syncTo(ctx, sl, cfg, o, replica); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", replica.Host, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// loadOrigin loads the origin state of the enabled features.
+func loadOrigin(sl *zap.SugaredLogger, cfg *types.Config, oc client.Client) (*origin, error) {
+	var err error
 	o := &origin{}
 	o.status, err = oc.Status()
 	if err != nil {
 		sl.With("error", err).Error("Error getting origin status")
-		return
+		return nil, err
 	}
 
 	if versions.IsNewerThan(versions.MinAgh, o.status.Version) {
 		sl.With("error", err, "version", o.status.Version).
 			Errorf("Origin AdGuard Home version must be >= %s", versions.MinAgh)
-		return
+		return nil, fmt.Errorf("origin AdGuard Home version %s must be >= %s", o.status.Version, versions.MinAgh)
 	}
 
 	sl.With("version", o.status.Version).Info("Connected to origin")
@@ -195,138 +284,148 @@ func (w *worker) sync() {
 		// and https://github.com/AdguardTeam/AdGuardHome/issues/7985
 
 		clientErr := &client.Error{}
-		if !w.cfg.ContinueOnError || !errors.As(err, &clientErr) || clientErr.Code() != http.StatusUnauthorized {
-			return
+		if !cfg.ContinueOnError || !errors.As(err, &clientErr) || clientErr.Code() != http.StatusUnauthorized {
+			return nil, err
 		}
 	}
 
 	o.parental, err = oc.Parental()
 	if err != nil {
 		sl.With("error", err).Error("Error getting parental status")
-		return
+		return nil, err
 	}
 	o.safeSearch, err = oc.SafeSearchConfig()
 	if err != nil {
 		sl.With("error", err).Error("Error getting safe search status")
-		return
+		return nil, err
 	}
 	o.safeBrowsing, err = oc.SafeBrowsing()
 	if err != nil {
 		sl.With("error", err).Error("Error getting safe browsing status")
-		return
+		return nil, err
 	}
 
 	o.rewriteSettings, err = oc.RewriteSettings()
 	if err != nil {
 		sl.With("error", err).Error("Error getting origin rewrite entries")
-		return
+		return nil, err
 	}
 
 	o.rewriteEntries, err = oc.RewriteEntries()
 	if err != nil {
 		sl.With("error", err).Error("Error getting origin rewrite entries")
-		return
+		return nil, err
 	}
 
 	o.blockedServicesSchedule, err = oc.BlockedServicesSchedule()
 	if err != nil {
 		sl.With("error", err).Error("Error getting origin blocked services schedule")
-		return
+		return nil, err
 	}
 
 	o.filters, err = oc.Filtering()
 	if err != nil {
 		sl.With("error", err).Error("Error getting origin actionFilters")
-		return
+		return nil, err
 	}
 	o.clients, err = oc.Clients()
 	if err != nil {
 		sl.With("error", err).Error("Error getting origin clients")
-		return
+		return nil, err
 	}
 	o.queryLogConfig, err = oc.QueryLogConfig()
 	if err != nil {
 		sl.With("error", err).Error("Error getting query log config")
-		return
+		return nil, err
 	}
 	o.statsConfig, err = oc.StatsConfig()
 	if err != nil {
 		sl.With("error", err).Error("Error getting stats config")
-		return
+		return nil, err
 	}
 
 	o.accessList, err = oc.AccessList()
 	if err != nil {
 		sl.With("error", err).Error("Error getting access list")
-		return
+		return nil, err
 	}
 
 	o.dnsConfig, err = oc.DNSConfig()
 	if err != nil {
 		sl.With("error", err).Error("Error getting dns config")
-		return
+		return nil, err
 	}
 
-	if w.cfg.Features.DHCP.ServerConfig || w.cfg.Features.DHCP.StaticLeases {
+	if cfg.Features.DHCP.ServerConfig || cfg.Features.DHCP.StaticLeases {
 		o.dhcpServerConfig, err = oc.DhcpConfig()
 		if err != nil {
 			sl.With("error", err).Error("Error getting dhcp server config")
-			return
+			return nil, err
 		}
 	}
 
-	if w.cfg.Features.TLSConfig {
+	if cfg.Features.TLSConfig {
 		o.tlsConfig, err = oc.TLSConfig()
 		if err != nil {
 			sl.With("error", err).Error("Error getting tls config")
-			return
+			return nil, err
 		}
 	}
-
-	w.actions = setupActions(w.cfg)
-
-	replicas := w.cfg.UniqueReplicas()
-	for _, replica := range replicas {
-		w.syncTo(sl, o, replica)
-	}
+	return o, nil
 }
 
-func (w *worker) syncTo(l *zap.SugaredLogger, o *origin, replica types.AdGuardInstance) {
-	rc, err := w.createClient(replica, w.cfg.ClientTimeout)
+func (w *worker) syncTo(
+	ctx context.Context,
+	l *zap.SugaredLogger,
+	cfg *types.Config,
+	o *origin,
+	replica types.AdGuardInstance,
+) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "sync replica",
+		trace.WithAttributes(attribute.String("sync.replica", replica.Host)))
+	defer func() { tracing.End(span, err) }()
+
+	rc, err := w.createClient(replica, cfg.ClientTimeout)
 	if err != nil {
 		l.With("error", err, "url", replica.URL).Error("Error creating replica client")
-		return
+		w.publishReplica(replica.Host, replicaFailed, err)
+		return err
 	}
+	rc = client.WithContext(ctx, rc)
 
 	rl := l.With("to", rc.Host())
 	rl.Info("Start sync")
+	w.publishReplica(replica.Host, replicaStarted, nil)
 	start := time.Now()
-	withError := false
 	defer func() {
 		delta := time.Since(start).Seconds()
-		metrics.UpdateResult(rc.Host(), !withError, delta)
+		metrics.UpdateResult(rc.Host(), err == nil, delta)
 		doneLog := rl.With("duration", fmt.Sprintf("%vs", delta))
-		if withError {
+		result := syncResult{Status: replicaSucceeded, Finished: time.Now(), Duration: delta}
+		if err != nil {
 			doneLog.Error("Sync done")
+			w.publishReplica(replica.Host, replicaFailed, err)
+			result.Status = replicaFailed
+			result.Error = err.Error()
 		} else {
 			doneLog.Info("Sync done")
+			w.publishReplica(replica.Host, replicaSucceeded, nil)
 		}
+		w.results.store(replica.Host, result)
 	}()
 
 	replicaStatus, err := w.statusWithSetup(rl, replica, rc)
 	if err != nil {
 		rl.With("error", err).Error("Error getting replica status")
-		withError = true
-		return
+		return err
 	}
 
 	rl.With("version", replicaStatus.Version).Info("Connected to replica")
 
-	if versions.IsNewerThan(versions.MinAgh, replicaStatus.Version) {
+	if versions.IsNewerThan(versions.MinReplicaAgh, replicaStatus.Version) {
 		rl.With("error", err, "version", replicaStatus.Version).
-			Errorf("Replica AdGuard Home version must be >= %s", versions.MinAgh)
-		withError = true
-		return
+			Errorf("Replica AdGuard Home version must be >= %s", versions.MinReplicaAgh)
+		return fmt.Errorf("replica AdGuard Home version %s must be >= %s", replicaStatus.Version, versions.MinReplicaAgh)
 	}
 
 	if o.status.Version != replicaStatus.Version {
@@ -334,24 +433,43 @@ func (w *worker) syncTo(l *zap.SugaredLogger, o *origin, replica types.AdGuardIn
 			Warn("Versions do not match")
 	}
 
+	cc := &changeCounter{Client: rc}
 	ac := &actionContext{
-		cfg:           w.cfg,
+		cfg:           cfg,
 		rl:            rl,
 		origin:        o,
 		replicaStatus: replicaStatus,
-		client:        rc,
+		client:        cc,
 		replica:       replica,
 	}
 
+	var errs []error
 	for _, action := range w.actions {
-		if err := action.sync(ac); err != nil {
-			rl.With("error", err).Errorf("Error syncing %s", action.name())
-			withError = true
-			if !w.cfg.ContinueOnError {
-				return
+		if ep, ok := versions.Default.UnsupportedEndpoint(replicaStatus.Version, action.endpoints()...); ok {
+			rl.With("version", replicaStatus.Version, "endpoint", ep).
+				Warnf("Skipping %s: not supported by the replica version", action.name())
+			w.publishAction(replica.Host, action.name(), actionSkipped, nil)
+			continue
+		}
+		actx, aspan := tracing.Tracer().Start(ctx, action.name(),
+			trace.WithAttributes(attribute.String("sync.action", action.name())))
+		cc.Client = client.WithContext(actx, rc)
+		actionStart := time.Now()
+		aErr := action.sync(ac)
+		tracing.End(aspan, aErr)
+		metrics.UpdateActionResult(replica.Host, action.name(), aErr == nil, time.Since(actionStart).Seconds(), cc.reset())
+		if aErr != nil {
+			rl.With("error", aErr).Errorf("Error syncing %s", action.name())
+			w.publishAction(replica.Host, action.name(), actionFailed, aErr)
+			errs = append(errs, fmt.Errorf("error syncing %s: %w", action.name(), aErr))
+			if !cfg.ContinueOnError {
+				break
 			}
+			continue
 		}
+		w.publishAction(replica.Host, action.name(), actionApplied, nil)
 	}
+	return errors.Join(errs...)
 }
 
 func (*worker) statusWithSetup(
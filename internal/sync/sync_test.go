@@ -2,6 +2,7 @@ package sync
 
 import (
 	"errors"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 
 	"github.com/bakito/adguardhome-sync/internal/client"
 	"github.com/bakito/adguardhome-sync/internal/client/model"
+	"github.com/bakito/adguardhome-sync/internal/fakeagh"
 	clientmock "github.com/bakito/adguardhome-sync/internal/mocks/client"
 	"github.com/bakito/adguardhome-sync/internal/types"
 	"github.com/bakito/adguardhome-sync/internal/versions"
@@ -61,6 +63,7 @@ func newTestEnv(t *testing.T) *testEnv {
 			},
 		},
 	}
+	w.runs = newRunManager(w.syncSelected)
 
 	ac := &actionContext{
 		cfg: w.cfg,
@@ -981,7 +984,9 @@ func TestSync(t *testing.T) {
 				env.cl.EXPECT().DNSConfig().Return(&model.DNSConfig{}, nil)
 				env.cl.EXPECT().DhcpConfig().Return(&model.DhcpStatus{}, nil)
 				env.cl.EXPECT().TLSConfig().Return(&model.TlsConfig{}, nil)
-				env.w.sync()
+				if err := env.w.sync(); err != nil {
+					t.Errorf("sync() error = %v", err)
+				}
 			})
 			t.Run("should not sync DHCP", func(t *testing.T) {
 				env := newTestEnv(t)
@@ -1048,7 +1053,9 @@ func TestSync(t *testing.T) {
 				env.cl.EXPECT().AccessList().Return(&model.AccessList{}, nil)
 				env.cl.EXPECT().DNSConfig().Return(&model.DNSConfig{}, nil)
 				env.cl.EXPECT().TLSConfig().Return(&model.TlsConfig{}, nil)
-				env.w.sync()
+				if err := env.w.sync(); err != nil {
+					t.Errorf("sync() error = %v", err)
+				}
 			})
 			t.Run("origin version is too small", func(t *testing.T) {
 				env := newTestEnv(t)
@@ -1059,7 +1066,9 @@ func TestSync(t *testing.T) {
 				// origin
 				env.cl.EXPECT().Host()
 				env.cl.EXPECT().Status().Return(&model.ServerStatus{Version: "v0.106.9"}, nil)
-				env.w.sync()
+				if err := env.w.sync(); err == nil {
+					t.Error("sync() error = nil, want error")
+				}
 			})
 			t.Run("replica version is too small", func(t *testing.T) {
 				env := newTestEnv(t)
@@ -1111,7 +1120,9 @@ func TestSync(t *testing.T) {
 				// replica
 				env.cl.EXPECT().Host().Times(2)
 				env.cl.EXPECT().Status().Return(&model.ServerStatus{Version: "v0.106.9"}, nil)
-				env.w.sync()
+				if err := env.w.sync(); err == nil {
+					t.Error("sync() error = nil, want error")
+				}
 			})
 		})
 
@@ -1200,8 +1211,10 @@ func TestSync(t *testing.T) {
 				env.w.createClient = func(_ types.AdGuardInstance, _ time.Duration) (client.Client, error) {
 					return nil, errors.New("creation error")
 				}
-				env.w.sync()
-				if env.w.running {
+				if err := env.w.sync(); err == nil {
+					t.Error("sync() error = nil, want error")
+				}
+				if env.w.running.Load() {
 					t.Error("worker should not be running")
 				}
 			})
@@ -1210,7 +1223,9 @@ func TestSync(t *testing.T) {
 				env.w.cfg.Origin = &types.AdGuardInstance{URL: "http://origin"}
 				env.cl.EXPECT().Status().Return(nil, errors.New("status error"))
 				env.cl.EXPECT().Host().Return("origin")
-				env.w.sync()
+				if err := env.w.sync(); err == nil {
+					t.Error("sync() error = nil, want error")
+				}
 			})
 			t.Run("should handle profileInfo error", func(t *testing.T) {
 				env := newTestEnv(t)
@@ -1218,7 +1233,9 @@ func TestSync(t *testing.T) {
 				env.cl.EXPECT().Status().Return(&model.ServerStatus{Version: versions.MinAgh}, nil)
 				env.cl.EXPECT().Host().Return("origin")
 				env.cl.EXPECT().ProfileInfo().Return(nil, errors.New("profile error"))
-				env.w.sync()
+				if err := env.w.sync(); err == nil {
+					t.Error("sync() error = nil, want error")
+				}
 			})
 		})
 		t.Run("worker.syncTo", func(t *testing.T) {
@@ -1227,19 +1244,26 @@ func TestSync(t *testing.T) {
 				env.w.createClient = func(_ types.AdGuardInstance, _ time.Duration) (client.Client, error) {
 					return nil, errors.New("creation error")
 				}
-				env.w.syncTo(l, &origin{status: &model.ServerStatus{}}, types.AdGuardInstance{})
+				if err := env.w.syncTo(t.Context(), l, env.w.cfg, &origin{status: &model.ServerStatus{}}, types.AdGuardInstance{}); err == nil {
+					t.Error("syncTo() error = nil, want error")
+				}
 			})
 			t.Run("should handle status error", func(t *testing.T) {
 				env := newTestEnv(t)
 				env.cl.EXPECT().Status().Return(nil, errors.New("status error"))
 				env.cl.EXPECT().Host().Return("replica").AnyTimes()
-				env.w.syncTo(l, &origin{status: &model.ServerStatus{}}, types.AdGuardInstance{})
+				if err := env.w.syncTo(t.Context(), l, env.w.cfg, &origin{status: &model.ServerStatus{}}, types.AdGuardInstance{}); err == nil {
+					t.Error("syncTo() error = nil, want error")
+				}
 			})
 			t.Run("should handle version mismatch", func(t *testing.T) {
 				env := newTestEnv(t)
 				env.cl.EXPECT().Status().Return(&model.ServerStatus{Version: "v0.107.0"}, nil)
 				env.cl.EXPECT().Host().Return("replica").AnyTimes()
-				env.w.syncTo(l, &origin{status: &model.ServerStatus{Version: "v0.108.0"}}, types.AdGuardInstance{})
+				o := &origin{status: &model.ServerStatus{Version: "v0.108.0"}}
+				if err := env.w.syncTo(t.Context(), l, env.w.cfg, o, types.AdGuardInstance{}); err == nil {
+					t.Error("syncTo() error = nil, want error")
+				}
 			})
 		})
 		t.Run("runOnStartAsync", func(t *testing.T) {
@@ -1271,3 +1295,43 @@ func TestSync(t *testing.T) {
 		})
 	})
 }
+
+func TestSyncOlderReplica(t *testing.T) {
+	origin := fakeagh.New(fakeagh.WithState(fakeagh.SampleState()))
+	origin.Update(func(st *fakeagh.State) {
+		st.RewriteSettings.Enabled = false
+		st.RewriteEntries[1].Enabled = new(false)
+	})
+	ots := httptest.NewServer(origin)
+	defer ots.Close()
+	replica := fakeagh.New(fakeagh.WithVersion("v0.107.65"))
+	rts := httptest.NewServer(replica)
+	defer rts.Close()
+
+	cfg := &types.Config{
+		Origin:   &types.AdGuardInstance{URL: ots.URL},
+		Replicas: []types.AdGuardInstance{{URL: rts.URL}},
+		Features: types.NewFeatures(true),
+	}
+	if err := cfg.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	w := &worker{cfg: cfg, createClient: client.New}
+	if err := w.sync(); err != nil {
+		t.Fatalf("sync() error = %v", err)
+	}
+
+	st := replica.State()
+	if len(st.RewriteEntries) != len(origin.State().RewriteEntries) {
+		t.Fatalf("rewrite entries = %v, want the origin entries", st.RewriteEntries)
+	}
+	for _, e := range st.RewriteEntries {
+		// without the enabled field, the replica adds the entries enabled
+		if e.Enabled != nil && !*e.Enabled {
+			t.Errorf("rewrite entry %s disabled, want the enabled field removed for v0.107.65", *e.Domain)
+		}
+	}
+	if !st.RewriteSettings.Enabled {
+		t.Error("rewrite settings synced, want the unsupported endpoint skipped for v0.107.65")
+	}
+}
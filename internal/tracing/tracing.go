@@ -0,0 +1,108 @@
+// Package tracing provides the OpenTelemetry spans of the sync runs and the AdGuard Home API calls.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bakito/adguardhome-sync/internal/log"
+	"github.com/bakito/adguardhome-sync/internal/types"
+	"github.com/bakito/adguardhome-sync/version"
+)
+
+const (
+	instrumentationName = "github.com/bakito/adguardhome-sync"
+	serviceName         = "adguardhome-sync"
+)
+
+var l = log.GetLogger("tracing")
+
+// Tracer returns the tracer of the sync; its spans are dropped unless tracing is initialized.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// End records the error on the span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Init installs the global tracer provider exporting the spans with the configured exporter.
+// The returned function flushes the pending spans and must be called before the application exits.
+func Init(ctx context.Context, cfg types.Tracing) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var (
+		exporter sdktrace.SpanExporter
+		file     *os.File
+		err      error
+	)
+	switch cfg.Exporter {
+	case types.TracingExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case types.TracingExporterFile:
+		file, err = os.OpenFile(cfg.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("error opening tracing file %q: %w", cfg.File, err)
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	default:
+		// endpoint, headers and protocol options are read from the OTEL_EXPORTER_OTLP_* env vars
+		exporter, err = otlptracehttp.New(ctx)
+	}
+	if err != nil {
+		return nil, closeFile(file, fmt.Errorf("error creating the tracing exporter: %w", err))
+	}
+
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the default attributes
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			attribute.String("service.name", serviceName),
+			attribute.String("service.version", version.Version),
+		),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, closeFile(file, fmt.Errorf("error creating the tracing resource: %w", err))
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+	l.With("exporter", exporterName(cfg)).Info("Tracing enabled")
+
+	return func(ctx context.Context) error {
+		return closeFile(file, tp.Shutdown(ctx))
+	}, nil
+}
+
+func exporterName(cfg types.Tracing) string {
+	if cfg.Exporter == "" {
+		return types.TracingExporterOTLP
+	}
+	return cfg.Exporter
+}
+
+func closeFile(file *os.File, err error) error {
+	if file != nil {
+		return errors.Join(err, file.Close())
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/bakito/adguardhome-sync/internal/types"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("should not install a provider if disabled", func(t *testing.T) {
+		before := otel.GetTracerProvider()
+		shutdown, err := Init(t.Context(), types.Tracing{Exporter: types.TracingExporterStdout})
+		if err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+		if otel.GetTracerProvider() != before {
+			t.Error("Init() installed a tracer provider")
+		}
+		if err := shutdown(t.Context()); err != nil {
+			t.Errorf("shutdown() error = %v", err)
+		}
+	})
+	t.Run("should write the spans to the file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "spans.json")
+		shutdown, err := Init(t.Context(), types.Tracing{Enabled: true, Exporter: types.TracingExporterFile, File: file})
+		if err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+		_, span := Tracer().Start(t.Context(), "sync")
+		End(span, errors.New("boom"))
+		if err := shutdown(t.Context()); err != nil {
+			t.Fatalf("shutdown() error = %v", err)
+		}
+
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		for _, want := range []string{`"Name":"sync"`, `"Code":"Error"`, `"Description":"boom"`, `"adguardhome-sync"`} {
+			if !strings.Contains(string(b), want) {
+				t.Errorf("spans = %s, want %s", b, want)
+			}
+		}
+	})
+	t.Run("should fail if the file can not be opened", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "missing", "spans.json")
+		if _, err := Init(t.Context(), types.Tracing{Enabled: true, Exporter: types.TracingExporterFile, File: file}); err == nil {
+			t.Error("Init() error = nil, want error")
+		}
+	})
+}
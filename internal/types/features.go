@@ -2,10 +2,17 @@ package types
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 
 	"go.uber.org/zap"
 )
 
+// ErrUnknownFeature is returned if a feature selector does not match any feature.
+var ErrUnknownFeature = errors.New("unknown feature")
+
 func NewFeatures(enabled bool) Features {
 	return Features{
 		DNS: DNS{
@@ -176,3 +183,63 @@ func (f *Features) collectDisabled() []string {
 	}
 	return features
 }
+
+// Select returns the features restricted to the given selectors.
+// A selector is the json path of a feature (e.g. 'dns.rewrites') or of a feature group (e.g. 'dns').
+// Features disabled in the config stay disabled; without selectors, all configured features are returned.
+func (f Features) Select(selectors ...string) (Features, error) {
+	if len(selectors) == 0 {
+		return f, nil
+	}
+	selected := NewFeatures(false)
+	flags := selected.flags()
+	configured := f.flags()
+	for _, s := range selectors {
+		for feature, options := range featureOptions {
+			if slices.Contains(options, s) {
+				return f, fmt.Errorf("%w %q: it is synced with %q", ErrUnknownFeature, s, feature)
+			}
+		}
+		var found bool
+		for key, flag := range flags {
+			if key == s || strings.HasPrefix(key, s+".") {
+				*flag = *configured[key]
+				for _, option := range featureOptions[key] {
+					*flags[option] = *configured[option]
+				}
+				found = true
+			}
+		}
+		if !found {
+			return f, fmt.Errorf("%w %q", ErrUnknownFeature, s)
+		}
+	}
+	return selected, nil
+}
+
+// featureOptions the flags only applied as part of a feature, they are selected with it and not on their own.
+var featureOptions = map[string][]string{
+	"generalSettings": {"protectionStatus", "theme"},
+}
+
+// flags returns the feature flags by their json path.
+func (f *Features) flags() map[string]*bool {
+	return map[string]*bool{
+		"dns.accessLists":   &f.DNS.AccessLists,
+		"dns.serverConfig":  &f.DNS.ServerConfig,
+		"dns.rewrites":      &f.DNS.Rewrites,
+		"dhcp.serverConfig": &f.DHCP.ServerConfig,
+		"dhcp.staticLeases": &f.DHCP.StaticLeases,
+		"generalSettings":   &f.GeneralSettings,
+		"protectionStatus":  &f.ProtectionStatus,
+		"queryLogConfig":    &f.QueryLogConfig,
+		"statsConfig":       &f.StatsConfig,
+		"clientSettings":    &f.ClientSettings,
+		"services":          &f.Services,
+		"filters.blacklist": &f.Filters.Blacklist,
+		"filters.whitelist": &f.Filters.Whitelist,
+		"filters.userRules": &f.Filters.UserRules,
+		"theme":             &f.Theme,
+		"tlsConfig":         &f.TLSConfig,
+	}
+}
@@ -3,9 +3,12 @@
 package types
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"net/url"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -15,42 +18,207 @@ import (
 const (
 	// DefaultAPIPath default api path.
 	DefaultAPIPath = "/control"
+	// SchemaValidationLog log responses not matching the schema.
+	SchemaValidationLog = "log"
+	// SchemaValidationFail fail on responses not matching the schema.
+	SchemaValidationFail = "fail"
+	// RoleViewer may read the status, logs and metrics.
+	RoleViewer = "viewer"
+	// RoleOperator may additionally trigger syncs and run admin actions.
+	RoleOperator = "operator"
 )
 
+// ErrUnknownReplica is returned if a replica selector does not match any replica.
+var ErrUnknownReplica = errors.New("unknown replica")
+
 // Config application configuration struct
 // +k8s:deepcopy-gen=true
 type Config struct {
-	Cron                string        `docs:"Cron expression for the sync interval"                                           env:"CRON"                json:"cron,omitempty"            yaml:"cron,omitempty"`
-	RunOnStart          bool          `docs:"Run the sync on startup"                                                         env:"RUN_ON_START"        json:"runOnStart,omitempty"      yaml:"runOnStart,omitempty"`
-	PrintConfigOnly     bool          `docs:"Print current config only and stop the application"                              env:"PRINT_CONFIG_ONLY"   json:"printConfigOnly,omitempty" yaml:"printConfigOnly,omitempty"`
-	ContinueOnError     bool          `docs:"Continue sync on errors"                                                         env:"CONTINUE_ON_ERROR"   json:"continueOnError,omitempty" yaml:"continueOnError,omitempty"`
-	ClientTimeoutString string        `docs:"Define a custom http client timeout ^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"  env:"HTTP_CLIENT_TIMEOUT" faker:"oneof: 30s, 5m"           json:"httpClientTimeout,omitempty" yaml:"httpClientTimeout,omitempty"`
-	ClientTimeout       time.Duration `json:"-"                                                                               yaml:"-"`
+	Cron                string        `docs:"Cron expression for the sync interval"                                                            env:"CRON"                json:"cron,omitempty"            yaml:"cron,omitempty"`
+	RunOnStart          bool          `docs:"Run the sync on startup"                                                                          env:"RUN_ON_START"        json:"runOnStart,omitempty"      yaml:"runOnStart,omitempty"`
+	PrintConfigOnly     bool          `docs:"Print current config only and stop the application"                                               env:"PRINT_CONFIG_ONLY"   json:"printConfigOnly,omitempty" yaml:"printConfigOnly,omitempty"`
+	ContinueOnError     bool          `docs:"Continue sync on errors"                                                                          env:"CONTINUE_ON_ERROR"   json:"continueOnError,omitempty" yaml:"continueOnError,omitempty"`
+	LogHistorySize      *int          `docs:"Number of log entries kept for the web UI and the logs API (default 500, 0 disables the history)" env:"LOG_HISTORY_SIZE"    json:"logHistorySize,omitempty"  yaml:"logHistorySize,omitempty"`
+	ClientTimeoutString string        `docs:"Define a custom http client timeout ^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"                   env:"HTTP_CLIENT_TIMEOUT" faker:"oneof: 30s, 5m"           json:"httpClientTimeout,omitempty" yaml:"httpClientTimeout,omitempty"`
+	ClientTimeout       time.Duration `json:"-"                                                                                                yaml:"-"`
 	// Origin adguardhome instance
 	Origin *AdGuardInstance `docs:"Origin instance" json:"origin" yaml:"origin"`
 	// One single replica adguardhome instance
 	Replica *AdGuardInstance `docs:"Single or replica instance (don't use in combination with replicas')" json:"replica,omitempty" yaml:"replica,omitempty"`
 	// Multiple replica instances
-	Replicas []AdGuardInstance `docs:"List or replica instances (don't use in combination with replicas')" faker:"slice_len=2"       json:"replicas,omitempty" yaml:"replicas,omitempty"`
-	API      API               `json:"api,omitempty"                                                       yaml:"api,omitempty"`
-	Features Features          `json:"features,omitempty"                                                  yaml:"features,omitempty"`
+	Replicas        []AdGuardInstance `docs:"List or replica instances (don't use in combination with replicas')" faker:"slice_len=2"              json:"replicas,omitempty" yaml:"replicas,omitempty"`
+	API             API               `json:"api,omitempty"                                                       yaml:"api,omitempty"`
+	Features        Features          `json:"features,omitempty"                                                  yaml:"features,omitempty"`
+	Tracing         Tracing           `json:"tracing,omitempty"                                                   yaml:"tracing,omitempty"`
+	QueryLogArchive QueryLogArchive   `json:"queryLogArchive,omitempty"                                           yaml:"queryLogArchive,omitempty"`
 }
 
 // API configuration.
 type API struct {
-	Port     int     `docs:"API port (API is disabled if port is set to 0)" env:"API_PORT"           json:"port,omitempty"     yaml:"port,omitempty"`
-	Username string  `docs:"API username"                                   env:"API_USERNAME"       json:"username,omitempty" yaml:"username,omitempty"`
-	Password string  `docs:"API password"                                   env:"API_PASSWORD"       json:"password,omitempty" yaml:"password,omitempty"`
-	DarkMode bool    `docs:"API dark mode"                                  env:"API_DARK_MODE"      json:"darkMode,omitempty" yaml:"darkMode,omitempty"`
-	Metrics  Metrics `json:"metrics,omitempty"                              yaml:"metrics,omitempty"`
-	TLS      TLS     `json:"tls,omitempty"                                  yaml:"tls,omitempty"`
+	Port     int        `docs:"API port (API is disabled if port is set to 0)"                        env:"API_PORT"           json:"port,omitempty"     yaml:"port,omitempty"`
+	Username string     `docs:"API username"                                                          env:"API_USERNAME"       json:"username,omitempty" yaml:"username,omitempty"`
+	Password string     `docs:"API password"                                                          env:"API_PASSWORD"       json:"password,omitempty" yaml:"password,omitempty"`
+	Users    []APIUser  `docs:"API users with bcrypt password hashes (env: 'username:role:hash,...')" env:"API_USERS"          faker:"slice_len=2"       json:"users,omitempty"    yaml:"users,omitempty"`
+	Tokens   []APIToken `docs:"API bearer tokens with SHA-256 hashes (env: 'name:role:hash,...')"     env:"API_TOKENS"         faker:"slice_len=2"       json:"tokens,omitempty"   yaml:"tokens,omitempty"`
+	Hooks    []APIHook  `docs:"Signed webhooks triggering a sync (env: 'name:secret,...')"            env:"API_HOOKS"          faker:"slice_len=2"       json:"hooks,omitempty"    yaml:"hooks,omitempty"`
+	DarkMode bool       `docs:"API dark mode"                                                         env:"API_DARK_MODE"      json:"darkMode,omitempty" yaml:"darkMode,omitempty"`
+	Metrics  Metrics    `json:"metrics,omitempty"                                                     yaml:"metrics,omitempty"`
+	Health   Health     `json:"health,omitempty"                                                      yaml:"health,omitempty"`
+	TLS      TLS        `json:"tls,omitempty"                                                         yaml:"tls,omitempty"`
+}
+
+// APIUser an API user authenticating with basic auth.
+type APIUser struct {
+	Username string `docs:"Username"                                  json:"username"                 yaml:"username"`
+	Password string `docs:"Bcrypt hash of the password"               json:"password"                 yaml:"password"`
+	Role     string `docs:"Role of the user ('viewer' or 'operator')" faker:"oneof: viewer, operator" json:"role"     yaml:"role"`
+}
+
+// APIToken an API bearer token.
+type APIToken struct {
+	Name string `docs:"Name of the token"                          json:"name"                     yaml:"name"`
+	Hash string `docs:"Hex encoded SHA-256 hash of the token"      json:"hash"                     yaml:"hash"`
+	Role string `docs:"Role of the token ('viewer' or 'operator')" faker:"oneof: viewer, operator" json:"role" yaml:"role"`
+}
+
+// APIHook a webhook triggering a sync, authenticated with a HMAC signature.
+type APIHook struct {
+	Name     string   `docs:"Name of the hook, triggered with 'POST /api/v1/hooks/{name}'" json:"name"               yaml:"name"`
+	Secret   string   `docs:"Shared secret of the HMAC-SHA256 signature"                   json:"secret"             yaml:"secret"`
+	Features []string `docs:"Features synced by the hook (all if empty)"                   json:"features,omitempty" yaml:"features,omitempty"`
+	Replicas []string `docs:"Replicas synced to by the hook (all if empty)"                json:"replicas,omitempty" yaml:"replicas,omitempty"`
 }
 
 // Metrics configuration.
 type Metrics struct {
-	Enabled        bool          `docs:"Enable metrics"                env:"API_METRICS_ENABLED"         json:"enabled,omitempty"        yaml:"enabled,omitempty"`
-	ScrapeInterval time.Duration `docs:"Interval for metrics scraping" env:"API_METRICS_SCRAPE_INTERVAL" json:"scrapeInterval,omitempty" yaml:"scrapeInterval,omitempty"`
-	QueryLogLimit  int           `docs:"Metrics log query limit"       env:"API_METRICS_QUERY_LOG_LIMIT" json:"queryLogLimit,omitempty"  yaml:"queryLogLimit,omitempty"`
+	Enabled          bool          `docs:"Enable metrics"                                                                                                    env:"API_METRICS_ENABLED"           json:"enabled,omitempty"            yaml:"enabled,omitempty"`
+	ScrapeInterval   time.Duration `docs:"Interval for metrics scraping"                                                                                     env:"API_METRICS_SCRAPE_INTERVAL"   json:"scrapeInterval,omitempty"     yaml:"scrapeInterval,omitempty"`
+	QueryLogLimit    int           `docs:"Max query log entries read per metrics scrape"                                                                     env:"API_METRICS_QUERY_LOG_LIMIT"   json:"queryLogLimit,omitempty"      yaml:"queryLogLimit,omitempty"`
+	TopLimit         int           `docs:"Maximum number of top domains and clients and of query log clients and upstreams reported per instance (all if 0)" env:"API_METRICS_TOP_LIMIT"         json:"topLimit,omitempty"           yaml:"topLimit,omitempty"`
+	DomainLabel      string        `docs:"Label value of the top domains ('domain' (default), 'etld+1' or 'hash')"                                           env:"API_METRICS_DOMAIN_LABEL"      faker:"oneof: domain, etld+1, hash" json:"domainLabel,omitempty"      yaml:"domainLabel,omitempty"`
+	Disabled         []string      `docs:"Metrics not to report, e.g. 'top_clients' (env: comma separated)"                                                  env:"API_METRICS_DISABLED"          json:"disabled,omitempty"           yaml:"disabled,omitempty"`
+	HistoryFile      string        `docs:"File the hourly stats history is persisted to (default $HOME/.adguardhome-sync-history.json)"                      env:"API_METRICS_HISTORY_FILE"      json:"historyFile,omitempty"        yaml:"historyFile,omitempty"`
+	HistoryRetention time.Duration `docs:"How long the stats history is kept (default 744h, 31 days)"                                                        env:"API_METRICS_HISTORY_RETENTION" json:"historyRetention,omitempty"   yaml:"historyRetention,omitempty"`
+	InfluxDB         InfluxDB      `json:"influxdb,omitempty"                                                                                                yaml:"influxdb,omitempty"`
+	StatsD           StatsD        `json:"statsd,omitempty"                                                                                                  yaml:"statsd,omitempty"`
+}
+
+// InfluxDB configuration of the push exporter writing the metrics in the line protocol after each scrape.
+type InfluxDB struct {
+	URL   string `docs:"Write URL, e.g. 'http://influxdb:8086/api/v2/write?org=home&bucket=adguard' (disabled if empty)" env:"API_METRICS_INFLUXDB_URL"   json:"url,omitempty"   yaml:"url,omitempty"`
+	Token string `docs:"API token sent in the 'Authorization: Token' header"                                             env:"API_METRICS_INFLUXDB_TOKEN" json:"token,omitempty" yaml:"token,omitempty"`
+}
+
+// StatsD configuration of the push exporter sending the metrics as gauges after each scrape.
+type StatsD struct {
+	Address string `docs:"UDP address 'host:port' of the StatsD server (disabled if empty)" env:"API_METRICS_STATSD_ADDRESS" json:"address,omitempty" yaml:"address,omitempty"`
+	Prefix  string `docs:"Prefix of the metric names, e.g. 'homelab.'"                      env:"API_METRICS_STATSD_PREFIX"  json:"prefix,omitempty"  yaml:"prefix,omitempty"`
+}
+
+// Domain label values of the top domain metrics.
+const (
+	MetricsDomainLabelDomain = "domain"
+	MetricsDomainLabelETLD1  = "etld+1"
+	MetricsDomainLabelHash   = "hash"
+)
+
+// Init validates the top limit, history retention, push exporters and domain label.
+func (m *Metrics) Init() error {
+	if m.TopLimit < 0 {
+		return fmt.Errorf("API metrics top limit %d must not be negative", m.TopLimit)
+	}
+	if m.HistoryRetention < 0 {
+		return fmt.Errorf("API metrics history retention %s must not be negative", m.HistoryRetention)
+	}
+	if m.InfluxDB.URL != "" {
+		if u, err := url.Parse(m.InfluxDB.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid API metrics InfluxDB URL %q: must be a http or https URL", m.InfluxDB.URL)
+		}
+	}
+	if m.StatsD.Address != "" {
+		if _, _, err := net.SplitHostPort(m.StatsD.Address); err != nil {
+			return fmt.Errorf("invalid API metrics StatsD address %q: %w", m.StatsD.Address, err)
+		}
+	}
+	switch m.DomainLabel {
+	case "", MetricsDomainLabelDomain, MetricsDomainLabelETLD1, MetricsDomainLabelHash:
+		return nil
+	default:
+		return fmt.Errorf("invalid API metrics domain label %q: must be one of %q, %q or %q", m.DomainLabel,
+			MetricsDomainLabelDomain, MetricsDomainLabelETLD1, MetricsDomainLabelHash)
+	}
+}
+
+// Health configuration of the cached instance status and the readiness probe.
+type Health struct {
+	StatusInterval time.Duration `docs:"Interval to poll the status of the instances (default 30s)"               env:"API_HEALTH_STATUS_INTERVAL" json:"statusInterval,omitempty" yaml:"statusInterval,omitempty"`
+	OriginOptional bool          `docs:"Ready even if the origin is not healthy"                                  env:"API_HEALTH_ORIGIN_OPTIONAL" json:"originOptional,omitempty" yaml:"originOptional,omitempty"`
+	MinReplicas    *int          `docs:"Minimum number of healthy replicas to be ready (all replicas if not set)" env:"API_HEALTH_MIN_REPLICAS"    json:"minReplicas,omitempty"    yaml:"minReplicas,omitempty"`
+}
+
+// Tracing exporters.
+const (
+	TracingExporterOTLP   = "otlp"
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
+)
+
+// Tracing configuration of the OpenTelemetry spans; the OTLP exporter is configured by the OTEL_EXPORTER_OTLP_* env vars.
+type Tracing struct {
+	Enabled  bool   `docs:"Enable OpenTelemetry tracing"                         env:"TRACING_ENABLED"  json:"enabled,omitempty"          yaml:"enabled,omitempty"`
+	Exporter string `docs:"Span exporter ('otlp' (default), 'stdout' or 'file')" env:"TRACING_EXPORTER" faker:"oneof: otlp, stdout, file" json:"exporter,omitempty" yaml:"exporter,omitempty"`
+	File     string `docs:"File the 'file' exporter appends the spans to"        env:"TRACING_FILE"     json:"file,omitempty"             yaml:"file,omitempty"`
+}
+
+// Init validates the exporter.
+func (t *Tracing) Init() error {
+	switch t.Exporter {
+	case "", TracingExporterOTLP, TracingExporterStdout:
+	case TracingExporterFile:
+		if t.File == "" {
+			return errors.New("the tracing file exporter requires a file")
+		}
+	default:
+		return fmt.Errorf("invalid tracing exporter %q: must be one of %q, %q or %q",
+			t.Exporter, TracingExporterOTLP, TracingExporterStdout, TracingExporterFile)
+	}
+	return nil
+}
+
+// Query log archive formats.
+const (
+	QueryLogArchiveFormatJSONL = "jsonl"
+	QueryLogArchiveFormatCSV   = "csv"
+)
+
+// QueryLogArchive configuration of the background job archiving the query logs of all instances to local files.
+type QueryLogArchive struct {
+	Dir       string        `docs:"Directory of the archive files (disabled if empty)"                        env:"QUERY_LOG_ARCHIVE_DIR"       json:"dir,omitempty"       yaml:"dir,omitempty"`
+	Format    string        `docs:"File format ('jsonl' (default) or 'csv')"                                  env:"QUERY_LOG_ARCHIVE_FORMAT"    faker:"oneof: jsonl, csv"  json:"format,omitempty"    yaml:"format,omitempty"`
+	Interval  time.Duration `docs:"Interval to archive the new query log entries (default 1m)"                env:"QUERY_LOG_ARCHIVE_INTERVAL"  json:"interval,omitempty"  yaml:"interval,omitempty"`
+	Limit     int           `docs:"Max query log entries archived per instance and interval (default 100000)" env:"QUERY_LOG_ARCHIVE_LIMIT"     json:"limit,omitempty"     yaml:"limit,omitempty"`
+	MaxSize   int           `docs:"Size in MB a file is rotated at, besides daily (daily only if 0)"          env:"QUERY_LOG_ARCHIVE_MAX_SIZE"  json:"maxSize,omitempty"   yaml:"maxSize,omitempty"`
+	Compress  bool          `docs:"Compress the rotated files with gzip"                                      env:"QUERY_LOG_ARCHIVE_COMPRESS"  json:"compress,omitempty"  yaml:"compress,omitempty"`
+	Retention time.Duration `docs:"How long the files are kept, by the day of their queries (all if 0)"       env:"QUERY_LOG_ARCHIVE_RETENTION" json:"retention,omitempty" yaml:"retention,omitempty"`
+}
+
+// Enabled returns true if the archive directory is set.
+func (a QueryLogArchive) Enabled() bool {
+	return a.Dir != ""
+}
+
+// Init validates the format and the limits.
+func (a *QueryLogArchive) Init() error {
+	if a.Interval < 0 || a.Limit < 0 || a.MaxSize < 0 || a.Retention < 0 {
+		return errors.New("the query log archive interval, limit, max size and retention must not be negative")
+	}
+	switch a.Format {
+	case "", QueryLogArchiveFormatJSONL, QueryLogArchiveFormatCSV:
+		return nil
+	default:
+		return fmt.Errorf("invalid query log archive format %q: must be one of %q or %q",
+			a.Format, QueryLogArchiveFormatJSONL, QueryLogArchiveFormatCSV)
+	}
 }
 
 // TLS configuration.
@@ -81,6 +249,73 @@ func defaultIfEmpty(val, fallback string) string {
 func (a *API) Mask() {
 	a.Username = mask(a.Username)
 	a.Password = mask(a.Password)
+	for i := range a.Users {
+		a.Users[i].Password = mask(a.Users[i].Password)
+	}
+	for i := range a.Tokens {
+		a.Tokens[i].Hash = mask(a.Tokens[i].Hash)
+	}
+	for i := range a.Hooks {
+		a.Hooks[i].Secret = mask(a.Hooks[i].Secret)
+	}
+	a.Metrics.InfluxDB.Token = mask(a.Metrics.InfluxDB.Token)
+}
+
+// Init validates the users and tokens.
+func (a *API) Init() error {
+	for _, u := range a.Users {
+		if u.Username == "" || u.Password == "" {
+			return errors.New("API users require a username and a password hash")
+		}
+		if err := validateRole(u.Role); err != nil {
+			return fmt.Errorf("API user %q: %w", u.Username, err)
+		}
+	}
+	for _, t := range a.Tokens {
+		if t.Name == "" || t.Hash == "" {
+			return errors.New("API tokens require a name and a hash")
+		}
+		if err := validateRole(t.Role); err != nil {
+			return fmt.Errorf("API token %q: %w", t.Name, err)
+		}
+	}
+	hooks := make(map[string]bool, len(a.Hooks))
+	for _, h := range a.Hooks {
+		if h.Name == "" || h.Secret == "" {
+			return errors.New("API hooks require a name and a secret")
+		}
+		if url.PathEscape(h.Name) != h.Name {
+			return fmt.Errorf("API hook %q: the name must be usable as URL path segment", h.Name)
+		}
+		if hooks[h.Name] {
+			return fmt.Errorf("API hook %q is configured twice", h.Name)
+		}
+		hooks[h.Name] = true
+	}
+	if err := a.Metrics.Init(); err != nil {
+		return err
+	}
+	if a.Health.MinReplicas != nil && *a.Health.MinReplicas < 0 {
+		return errors.New("API health min replicas must not be negative")
+	}
+	return nil
+}
+
+// Hook returns the hook with the given name.
+func (a *API) Hook(name string) (APIHook, bool) {
+	for _, h := range a.Hooks {
+		if h.Name == name {
+			return h, true
+		}
+	}
+	return APIHook{}, false
+}
+
+func validateRole(role string) error {
+	if role != RoleViewer && role != RoleOperator {
+		return fmt.Errorf("invalid role %q: must be %q or %q", role, RoleViewer, RoleOperator)
+	}
+	return nil
 }
 
 // UniqueReplicas get unique replication instances.
@@ -108,6 +343,47 @@ func (cfg *Config) UniqueReplicas() []AdGuardInstance {
 	return r
 }
 
+// Select returns a copy of the config restricted to the selected features and replicas.
+func (cfg *Config) Select(features, replicas []string) (*Config, error) {
+	f, err := cfg.Features.Select(features...)
+	if err != nil {
+		return nil, err
+	}
+	r, err := cfg.SelectReplicas(replicas...)
+	if err != nil {
+		return nil, err
+	}
+	c := cfg.DeepCopy()
+	c.Features = f
+	if len(replicas) > 0 {
+		c.Replica = nil
+		c.Replicas = r
+	}
+	return c, nil
+}
+
+// SelectReplicas returns the unique replicas matching the selectors by host, web host or URL.
+// Without selectors, all unique replicas are returned.
+func (cfg *Config) SelectReplicas(selectors ...string) ([]AdGuardInstance, error) {
+	replicas := cfg.UniqueReplicas()
+	if len(selectors) == 0 {
+		return replicas, nil
+	}
+	var selected []AdGuardInstance
+	for _, s := range selectors {
+		idx := slices.IndexFunc(replicas, func(r AdGuardInstance) bool {
+			return s == r.Host || s == r.WebHost || s == r.URL || s == r.WebURL
+		})
+		if idx < 0 {
+			return nil, fmt.Errorf("%w %q", ErrUnknownReplica, s)
+		}
+		if !slices.ContainsFunc(selected, func(r AdGuardInstance) bool { return r.Key() == replicas[idx].Key() }) {
+			selected = append(selected, replicas[idx])
+		}
+	}
+	return selected, nil
+}
+
 // Log the current config.
 func (cfg *Config) Log(l *zap.SugaredLogger) {
 	c := cfg.mask()
@@ -132,6 +408,18 @@ func (cfg *Config) mask() *Config {
 }
 
 func (cfg *Config) Init() error {
+	if cfg.LogHistorySize != nil && *cfg.LogHistorySize < 0 {
+		return errors.New("log history size must not be negative")
+	}
+	if err := cfg.API.Init(); err != nil {
+		return err
+	}
+	if err := cfg.Tracing.Init(); err != nil {
+		return err
+	}
+	if err := cfg.QueryLogArchive.Init(); err != nil {
+		return err
+	}
 	if err := cfg.Origin.Init(); err != nil {
 		return err
 	}
@@ -141,23 +429,33 @@ func (cfg *Config) Init() error {
 			return err
 		}
 	}
+	for _, h := range cfg.API.Hooks {
+		if _, err := cfg.Select(h.Features, h.Replicas); err != nil {
+			return fmt.Errorf("API hook %q: %w", h.Name, err)
+		}
+	}
+	if mr := cfg.API.Health.MinReplicas; mr != nil && *mr > len(cfg.UniqueReplicas()) {
+		return fmt.Errorf("API health min replicas %d exceeds the number of replicas %d", *mr, len(cfg.UniqueReplicas()))
+	}
 	return nil
 }
 
 // AdGuardInstance AdguardHome config instance
 // +k8s:deepcopy-gen=true
 type AdGuardInstance struct {
-	URL                string            `docs:"URL of adguardhome instance"                               env:"URL"                  faker:"url"                        json:"url"                         yaml:"url"`
-	WebURL             string            `docs:"Web URL of adguardhome instance"                           env:"WEB_URL"              faker:"url"                        json:"webURL"                      yaml:"webURL"`
-	APIPath            string            `docs:"API Path"                                                  env:"API_PATH"             json:"apiPath,omitempty"           yaml:"apiPath,omitempty"`
-	Username           string            `docs:"Adguardhome username"                                      env:"USERNAME"             json:"username,omitempty"          yaml:"username,omitempty"`
-	Password           string            `docs:"Adguardhome password"                                      env:"PASSWORD"             json:"password,omitempty"          yaml:"password,omitempty"`
-	Cookie             string            `docs:"Adguardhome cookie"                                        env:"COOKIE"               json:"cookie,omitempty"            yaml:"cookie,omitempty"`
-	RequestHeaders     map[string]string `docs:"Request Headers 'key1:value1,key2:value2'"                 env:"REQUEST_HEADERS"      json:"requestHeaders,omitempty"    yaml:"requestHeaders,omitempty"`
-	InsecureSkipVerify bool              `docs:"Skip TLS verification"                                     env:"INSECURE_SKIP_VERIFY" json:"insecureSkipVerify"          yaml:"insecureSkipVerify"`
-	AutoSetup          bool              `docs:"Automatically setup the instance if it is not initialized" env:"AUTO_SETUP"           json:"autoSetup"                   yaml:"autoSetup"`
-	InterfaceName      string            `docs:"Network interface name"                                    env:"INTERFACE_NAME"       json:"interfaceName,omitempty"     yaml:"interfaceName,omitempty"`
-	DHCPServerEnabled  *bool             `docs:"Enable DHCP server"                                        env:"DHCP_SERVER_ENABLED"  json:"dhcpServerEnabled,omitempty" yaml:"dhcpServerEnabled,omitempty"`
+	URL                string            `docs:"URL of adguardhome instance"                                           env:"URL"                  faker:"url"                        json:"url"                         yaml:"url"`
+	WebURL             string            `docs:"Web URL of adguardhome instance"                                       env:"WEB_URL"              faker:"url"                        json:"webURL"                      yaml:"webURL"`
+	APIPath            string            `docs:"API Path"                                                              env:"API_PATH"             json:"apiPath,omitempty"           yaml:"apiPath,omitempty"`
+	Username           string            `docs:"Adguardhome username"                                                  env:"USERNAME"             json:"username,omitempty"          yaml:"username,omitempty"`
+	Password           string            `docs:"Adguardhome password"                                                  env:"PASSWORD"             json:"password,omitempty"          yaml:"password,omitempty"`
+	Cookie             string            `docs:"Adguardhome cookie"                                                    env:"COOKIE"               json:"cookie,omitempty"            yaml:"cookie,omitempty"`
+	RequestHeaders     map[string]string `docs:"Request Headers 'key1:value1,key2:value2'"                             env:"REQUEST_HEADERS"      json:"requestHeaders,omitempty"    yaml:"requestHeaders,omitempty"`
+	InsecureSkipVerify bool              `docs:"Skip TLS verification"                                                 env:"INSECURE_SKIP_VERIFY" json:"insecureSkipVerify"          yaml:"insecureSkipVerify"`
+	AutoSetup          bool              `docs:"Automatically setup the instance if it is not initialized"             env:"AUTO_SETUP"           json:"autoSetup"                   yaml:"autoSetup"`
+	InterfaceName      string            `docs:"Network interface name"                                                env:"INTERFACE_NAME"       json:"interfaceName,omitempty"     yaml:"interfaceName,omitempty"`
+	DHCPServerEnabled  *bool             `docs:"Enable DHCP server"                                                    env:"DHCP_SERVER_ENABLED"  json:"dhcpServerEnabled,omitempty" yaml:"dhcpServerEnabled,omitempty"`
+	RecordFile         string            `docs:"Record the HTTP traffic into a redacted JSON lines or HAR (.har) file" env:"RECORD_FILE"          json:"recordFile,omitempty"        yaml:"recordFile,omitempty"`
+	SchemaValidation   string            `docs:"Validate the API responses against the schema ('log' or 'fail')"       env:"SCHEMA_VALIDATION"    faker:"oneof: log, fail"           json:"schemaValidation,omitempty"  yaml:"schemaValidation,omitempty"`
 
 	Host    string `json:"-" yaml:"-"`
 	WebHost string `json:"-" yaml:"-"`
@@ -191,6 +489,11 @@ func (i *AdGuardInstance) Init() error {
 		}
 		i.WebHost = u.Host
 	}
+	if i.SchemaValidation != "" && i.SchemaValidation != SchemaValidationLog &&
+		i.SchemaValidation != SchemaValidationFail {
+		return fmt.Errorf("invalid schema validation %q of %s: must be one of %q, %q",
+			i.SchemaValidation, i.URL, SchemaValidationLog, SchemaValidationFail)
+	}
 	return nil
 }
 
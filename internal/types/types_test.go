@@ -1,8 +1,10 @@
 package types
 
 import (
+	"errors"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestAdGuardInstance_Init(t *testing.T) {
@@ -82,6 +84,106 @@ func TestConfig_Init(t *testing.T) {
 	if cfg.Replicas[0].WebURL != "https://localhost:3000" {
 		t.Errorf("cfg.Replicas[0].WebURL = %v, want https://localhost:3000", cfg.Replicas[0].WebURL)
 	}
+
+	cfg.API.Health.MinReplicas = new(2)
+	if err := cfg.Init(); err == nil {
+		t.Error("Config.Init() error = nil, want error for more min replicas than replicas")
+	}
+
+	cfg.API.Health.MinReplicas = nil
+	cfg.LogHistorySize = new(-1)
+	if err := cfg.Init(); err == nil {
+		t.Error("Config.Init() error = nil, want error for a negative log history size")
+	}
+}
+
+func TestAPI_Init(t *testing.T) {
+	tests := []struct {
+		name    string
+		api     API
+		wantErr bool
+	}{
+		{name: "should accept no users", api: API{}},
+		{
+			name: "should accept valid users and tokens",
+			api: API{
+				Users:  []APIUser{{Username: "u", Password: "hash", Role: RoleViewer}},
+				Tokens: []APIToken{{Name: "t", Hash: "hash", Role: RoleOperator}},
+			},
+		},
+		{name: "should reject unknown roles", api: API{Users: []APIUser{{Username: "u", Password: "hash", Role: "admin"}}}, wantErr: true},
+		{name: "should reject users without password", api: API{Users: []APIUser{{Username: "u", Role: RoleViewer}}}, wantErr: true},
+		{name: "should reject tokens without hash", api: API{Tokens: []APIToken{{Name: "t", Role: RoleViewer}}}, wantErr: true},
+		{name: "should accept hooks", api: API{Hooks: []APIHook{{Name: "dns-repo", Secret: "secret"}}}},
+		{name: "should reject hooks without secret", api: API{Hooks: []APIHook{{Name: "dns-repo"}}}, wantErr: true},
+		{name: "should reject hook names with slashes", api: API{Hooks: []APIHook{{Name: "a/b", Secret: "s"}}}, wantErr: true},
+		{
+			name:    "should reject duplicate hooks",
+			api:     API{Hooks: []APIHook{{Name: "h", Secret: "s"}, {Name: "h", Secret: "t"}}},
+			wantErr: true,
+		},
+		{name: "should accept optional replicas", api: API{Health: Health{MinReplicas: new(0)}}},
+		{name: "should reject negative min replicas", api: API{Health: Health{MinReplicas: new(-1)}}, wantErr: true},
+		{name: "should accept a domain label", api: API{Metrics: Metrics{TopLimit: 10, DomainLabel: MetricsDomainLabelETLD1}}},
+		{name: "should reject unknown domain labels", api: API{Metrics: Metrics{DomainLabel: "tld"}}, wantErr: true},
+		{name: "should reject a negative top limit", api: API{Metrics: Metrics{TopLimit: -1}}, wantErr: true},
+		{name: "should reject a negative history retention", api: API{Metrics: Metrics{HistoryRetention: -time.Hour}}, wantErr: true},
+		{name: "should reject invalid InfluxDB URLs", api: API{Metrics: Metrics{InfluxDB: InfluxDB{URL: "influxdb:8086"}}}, wantErr: true},
+		{name: "should reject invalid StatsD addresses", api: API{Metrics: Metrics{StatsD: StatsD{Address: "statsd"}}}, wantErr: true},
+		{
+			name: "should accept push exporters",
+			api:  API{Metrics: Metrics{InfluxDB: InfluxDB{URL: "http://influxdb:8086/write?db=adguard"}, StatsD: StatsD{Address: "statsd:8125"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.api.Init(); (err != nil) != tt.wantErr {
+				t.Errorf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTracing_Init(t *testing.T) {
+	tests := []struct {
+		name    string
+		tracing Tracing
+		wantErr bool
+	}{
+		{name: "should default to otlp", tracing: Tracing{Enabled: true}},
+		{name: "should accept stdout", tracing: Tracing{Enabled: true, Exporter: TracingExporterStdout}},
+		{name: "should accept a file", tracing: Tracing{Exporter: TracingExporterFile, File: "spans.json"}},
+		{name: "should reject the file exporter without file", tracing: Tracing{Exporter: TracingExporterFile}, wantErr: true},
+		{name: "should reject unknown exporters", tracing: Tracing{Exporter: "jaeger"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.tracing.Init(); (err != nil) != tt.wantErr {
+				t.Errorf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQueryLogArchive_Init(t *testing.T) {
+	tests := []struct {
+		name    string
+		archive QueryLogArchive
+		wantErr bool
+	}{
+		{name: "should default to jsonl", archive: QueryLogArchive{Dir: "archive"}},
+		{name: "should accept csv", archive: QueryLogArchive{Dir: "archive", Format: QueryLogArchiveFormatCSV}},
+		{name: "should reject unknown formats", archive: QueryLogArchive{Format: "xml"}, wantErr: true},
+		{name: "should reject a negative retention", archive: QueryLogArchive{Retention: -time.Hour}, wantErr: true},
+		{name: "should reject a negative max size", archive: QueryLogArchive{MaxSize: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.archive.Init(); (err != nil) != tt.wantErr {
+				t.Errorf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
 }
 
 func TestConfig_UniqueReplicas(t *testing.T) {
@@ -101,6 +203,40 @@ func TestConfig_UniqueReplicas(t *testing.T) {
 	}
 }
 
+func TestConfig_SelectReplicas(t *testing.T) {
+	cfg := Config{
+		Origin: &AdGuardInstance{},
+		Replicas: []AdGuardInstance{
+			{URL: "https://a:3000", Host: "a:3000", WebHost: "a", WebURL: "https://a"},
+			{URL: "https://b:3000", Host: "b:3000", WebHost: "b:3000", WebURL: "https://b:3000"},
+		},
+	}
+	tests := []struct {
+		name      string
+		selectors []string
+		want      int
+		wantErr   bool
+	}{
+		{name: "should select all replicas without selectors", want: 2},
+		{name: "should select by host", selectors: []string{"a:3000"}, want: 1},
+		{name: "should select by web host", selectors: []string{"a"}, want: 1},
+		{name: "should select by url", selectors: []string{"https://b:3000"}, want: 1},
+		{name: "should select a replica only once", selectors: []string{"a", "https://a"}, want: 1},
+		{name: "should fail on unknown replicas", selectors: []string{"c"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cfg.SelectReplicas(tt.selectors...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectReplicas() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("len(SelectReplicas()) = %v, want %v", len(got), tt.want)
+			}
+		})
+	}
+}
+
 func TestConfig_mask(t *testing.T) {
 	cfg := Config{
 		Origin: &AdGuardInstance{},
@@ -170,6 +306,63 @@ func TestFeature_collectDisabled(t *testing.T) {
 	}
 }
 
+func TestFeatures_Select(t *testing.T) {
+	t.Run("should return all configured features without selectors", func(t *testing.T) {
+		f := NewFeatures(true)
+		got, err := f.Select()
+		if err != nil || got != f {
+			t.Errorf("Select() = %v, %v, want %v", got, err, f)
+		}
+	})
+	t.Run("should select single features and groups", func(t *testing.T) {
+		got, err := NewFeatures(true).Select("dns.rewrites", "filters")
+		if err != nil {
+			t.Fatalf("Select() error = %v", err)
+		}
+		want := NewFeatures(false)
+		want.DNS.Rewrites = true
+		want.Filters = FiltersType{Blacklist: true, Whitelist: true, UserRules: true}
+		if got != want {
+			t.Errorf("Select() = %v, want %v", got, want)
+		}
+	})
+	t.Run("should keep disabled features disabled", func(t *testing.T) {
+		f := NewFeatures(true)
+		f.DNS.Rewrites = false
+		got, err := f.Select("dns")
+		if err != nil {
+			t.Fatalf("Select() error = %v", err)
+		}
+		if got.DNS.Rewrites || !got.DNS.AccessLists || got.GeneralSettings {
+			t.Errorf("Select() = %v", got)
+		}
+	})
+	t.Run("should fail on unknown features", func(t *testing.T) {
+		if _, err := NewFeatures(true).Select("dns.foo"); !errors.Is(err, ErrUnknownFeature) {
+			t.Errorf("Select() error = %v, want %v", err, ErrUnknownFeature)
+		}
+	})
+	t.Run("should select the options of the general settings", func(t *testing.T) {
+		f := NewFeatures(true)
+		f.ProtectionStatus = true
+		f.Theme = false
+		got, err := f.Select("generalSettings")
+		if err != nil {
+			t.Fatalf("Select() error = %v", err)
+		}
+		if !got.GeneralSettings || !got.ProtectionStatus || got.Theme || got.DNS.Rewrites {
+			t.Errorf("Select() = %v, want the general settings with the protection status", got)
+		}
+	})
+	t.Run("should fail on options of the general settings", func(t *testing.T) {
+		for _, s := range []string{"protectionStatus", "theme"} {
+			if _, err := NewFeatures(true).Select(s); !errors.Is(err, ErrUnknownFeature) {
+				t.Errorf("Select(%q) error = %v, want %v", s, err, ErrUnknownFeature)
+			}
+		}
+	})
+}
+
 func TestTLS_Enabled(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -9,7 +9,25 @@ import ()
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *API) DeepCopyInto(out *API) {
 	*out = *in
-	out.Metrics = in.Metrics
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]APIUser, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tokens != nil {
+		in, out := &in.Tokens, &out.Tokens
+		*out = make([]APIToken, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]APIHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Metrics.DeepCopyInto(&out.Metrics)
+	in.Health.DeepCopyInto(&out.Health)
 	out.TLS = in.TLS
 }
 
@@ -23,6 +41,61 @@ func (in *API) DeepCopy() *API {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIHook) DeepCopyInto(out *APIHook) {
+	*out = *in
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIHook.
+func (in *APIHook) DeepCopy() *APIHook {
+	if in == nil {
+		return nil
+	}
+	out := new(APIHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIToken) DeepCopyInto(out *APIToken) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIToken.
+func (in *APIToken) DeepCopy() *APIToken {
+	if in == nil {
+		return nil
+	}
+	out := new(APIToken)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIUser) DeepCopyInto(out *APIUser) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIUser.
+func (in *APIUser) DeepCopy() *APIUser {
+	if in == nil {
+		return nil
+	}
+	out := new(APIUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AdGuardInstance) DeepCopyInto(out *AdGuardInstance) {
 	*out = *in
@@ -53,6 +126,11 @@ func (in *AdGuardInstance) DeepCopy() *AdGuardInstance {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Config) DeepCopyInto(out *Config) {
 	*out = *in
+	if in.LogHistorySize != nil {
+		in, out := &in.LogHistorySize, &out.LogHistorySize
+		*out = new(int)
+		**out = **in
+	}
 	if in.Origin != nil {
 		in, out := &in.Origin, &out.Origin
 		*out = new(AdGuardInstance)
@@ -70,8 +148,10 @@ func (in *Config) DeepCopyInto(out *Config) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.API = in.API
+	in.API.DeepCopyInto(&out.API)
 	out.Features = in.Features
+	out.Tracing = in.Tracing
+	out.QueryLogArchive = in.QueryLogArchive
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Config.
@@ -147,6 +227,41 @@ func (in *FiltersType) DeepCopy() *FiltersType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Health) DeepCopyInto(out *Health) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Health.
+func (in *Health) DeepCopy() *Health {
+	if in == nil {
+		return nil
+	}
+	out := new(Health)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfluxDB) DeepCopyInto(out *InfluxDB) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfluxDB.
+func (in *InfluxDB) DeepCopy() *InfluxDB {
+	if in == nil {
+		return nil
+	}
+	out := new(InfluxDB)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstallConfig) DeepCopyInto(out *InstallConfig) {
 	*out = *in
@@ -182,6 +297,13 @@ func (in *InstallPort) DeepCopy() *InstallPort {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Metrics) DeepCopyInto(out *Metrics) {
 	*out = *in
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.InfluxDB = in.InfluxDB
+	out.StatsD = in.StatsD
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Metrics.
@@ -209,6 +331,36 @@ func (in *Protection) DeepCopy() *Protection {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryLogArchive) DeepCopyInto(out *QueryLogArchive) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryLogArchive.
+func (in *QueryLogArchive) DeepCopy() *QueryLogArchive {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryLogArchive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatsD) DeepCopyInto(out *StatsD) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatsD.
+func (in *StatsD) DeepCopy() *StatsD {
+	if in == nil {
+		return nil
+	}
+	out := new(StatsD)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLS) DeepCopyInto(out *TLS) {
 	*out = *in
@@ -223,3 +375,18 @@ func (in *TLS) DeepCopy() *TLS {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tracing) DeepCopyInto(out *Tracing) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tracing.
+func (in *Tracing) DeepCopy() *Tracing {
+	if in == nil {
+		return nil
+	}
+	out := new(Tracing)
+	in.DeepCopyInto(out)
+	return out
+}
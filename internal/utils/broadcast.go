@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"sync"
+)
+
+// Broadcaster fans out published values to all subscribers.
+// Slow subscribers miss values instead of blocking the publisher. The zero value is ready to use.
+type Broadcaster[T any] struct {
+	mu   sync.Mutex
+	subs map[chan T]struct{}
+}
+
+// Subscribe returns a channel receiving all values published from now on and a function to unsubscribe.
+func (b *Broadcaster[T]) Subscribe(buffer int) (<-chan T, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs == nil {
+		b.subs = make(map[chan T]struct{})
+	}
+	ch := make(chan T, buffer)
+	b.subs[ch] = struct{}{}
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[ch]; ok {
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Publish sends the value to all subscribers with free buffer capacity.
+func (b *Broadcaster[T]) Publish(v T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- v:
+		default:
+		}
+	}
+}
+
+// HasSubscribers returns true if there is at least one subscriber.
+func (b *Broadcaster[T]) HasSubscribers() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs) > 0
+}
@@ -0,0 +1,112 @@
+package versions
+
+import (
+	"strings"
+)
+
+// Range a range of AdGuard Home versions. Since is inclusive, Until exclusive, empty bounds are unlimited.
+type Range struct {
+	Since string
+	Until string
+}
+
+// Contains returns true if the version is within the range. Unknown versions are always contained.
+func (r Range) Contains(version string) bool {
+	if version == "" {
+		return true
+	}
+	if r.Since != "" && IsNewerThan(r.Since, version) {
+		return false
+	}
+	return r.Until == "" || IsNewerThan(r.Until, version)
+}
+
+// Endpoint an API endpoint only available in a range of versions.
+type Endpoint struct {
+	Range
+	Path string
+}
+
+// Field a request field only supported by a range of versions.
+// If Convert is defined, the value is converted for versions out of range, otherwise the field is removed.
+type Field struct {
+	Range
+	Path    string
+	Name    string
+	Convert func(value any) any
+}
+
+// Compatibility known endpoints and request fields per AdGuard Home version.
+type Compatibility struct {
+	Endpoints []Endpoint
+	Fields    []Field
+}
+
+// Default compatibility rules, maintained from the AdGuard Home changelog.
+var Default = Compatibility{
+	Endpoints: []Endpoint{
+		{Path: "/rewrite/settings/update", Range: Range{Since: "v0.107.68"}},
+	},
+	Fields: []Field{
+		{Path: "/rewrite/add", Name: "enabled", Range: Range{Since: "v0.107.68"}},
+		{Path: "/rewrite/delete", Name: "enabled", Range: Range{Since: "v0.107.68"}},
+		{Path: "/rewrite/update", Name: "target.enabled", Range: Range{Since: "v0.107.68"}},
+		{Path: "/rewrite/update", Name: "update.enabled", Range: Range{Since: "v0.107.68"}},
+	},
+}
+
+// UnsupportedEndpoint returns the first of the given endpoints not available in the version.
+func (c Compatibility) UnsupportedEndpoint(version string, paths ...string) (string, bool) {
+	for _, p := range paths {
+		for _, e := range c.Endpoints {
+			if e.Path == normalize(p) && !e.Contains(version) {
+				return e.Path, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Adapt strips or converts the fields of the request payload for the given path not supported by the version.
+// It returns the names of the adapted fields.
+func (c Compatibility) Adapt(path, version string, payload map[string]any) []string {
+	var adapted []string
+	for _, f := range c.Fields {
+		if f.Path != normalize(path) || f.Contains(version) {
+			continue
+		}
+		parent, name := lookup(payload, f.Name)
+		if parent == nil {
+			continue
+		}
+		v, ok := parent[name]
+		if !ok {
+			continue
+		}
+		if f.Convert != nil {
+			parent[name] = f.Convert(v)
+		} else {
+			delete(parent, name)
+		}
+		adapted = append(adapted, f.Name)
+	}
+	return adapted
+}
+
+// lookup returns the object containing the dot separated field and the field's name.
+func lookup(payload map[string]any, field string) (map[string]any, string) {
+	parts := strings.Split(field, ".")
+	obj := payload
+	for _, p := range parts[:len(parts)-1] {
+		child, ok := obj[p].(map[string]any)
+		if !ok {
+			return nil, ""
+		}
+		obj = child
+	}
+	return obj, parts[len(parts)-1]
+}
+
+func normalize(path string) string {
+	return "/" + strings.TrimPrefix(strings.SplitN(path, "?", 2)[0], "/")
+}
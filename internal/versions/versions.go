@@ -5,6 +5,9 @@ import "golang.org/x/mod/semver"
 const (
 	// MinAgh minimal adguardhome version.
 	MinAgh = "v0.107.68"
+	// MinReplicaAgh minimal adguardhome version of replicas. Replicas older than MinAgh are supported by adapting
+	// the requests with the Default compatibility rules.
+	MinReplicaAgh = "v0.107.62"
 )
 
 func IsNewerThan(v1, v2 string) bool {
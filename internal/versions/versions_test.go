@@ -49,3 +49,78 @@ func TestIsSame(t *testing.T) {
 		})
 	}
 }
+
+func TestRange_Contains(t *testing.T) {
+	tests := []struct {
+		r       versions.Range
+		version string
+		want    bool
+	}{
+		{versions.Range{Since: "v0.107.70"}, "v0.107.70", true},
+		{versions.Range{Since: "v0.107.70"}, "v0.107.69", false},
+		{versions.Range{Until: "v0.107.70"}, "v0.107.70", false},
+		{versions.Range{Until: "v0.107.70"}, "0.107.69", true},
+		{versions.Range{Since: "v0.107.70", Until: "v0.107.75"}, "v0.107.72", true},
+		{versions.Range{Since: "v0.107.70"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.r.Since+"_"+tt.r.Until+"_"+tt.version, func(t *testing.T) {
+			if got := tt.r.Contains(tt.version); got != tt.want {
+				t.Errorf("Contains(%v) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompatibility(t *testing.T) {
+	c := versions.Compatibility{
+		Endpoints: []versions.Endpoint{{Path: "/new/endpoint", Range: versions.Range{Since: "v0.107.70"}}},
+		Fields: []versions.Field{
+			{Path: "/dns_config", Name: "new_field", Range: versions.Range{Since: "v0.107.70"}},
+			{Path: "/clients/update", Name: "data.old_field", Range: versions.Range{Until: "v0.107.70"}},
+			{
+				Path:    "/dns_config",
+				Name:    "mode",
+				Range:   versions.Range{Since: "v0.107.70"},
+				Convert: func(any) any { return "legacy" },
+			},
+		},
+	}
+
+	t.Run("should report unsupported endpoints", func(t *testing.T) {
+		if ep, ok := c.UnsupportedEndpoint("v0.107.69", "/other", "new/endpoint"); !ok || ep != "/new/endpoint" {
+			t.Errorf("UnsupportedEndpoint() = %v, %v, want /new/endpoint, true", ep, ok)
+		}
+		if _, ok := c.UnsupportedEndpoint("v0.107.70", "/new/endpoint"); ok {
+			t.Error("UnsupportedEndpoint() = true, want false")
+		}
+	})
+	t.Run("should strip and convert fields for older versions", func(t *testing.T) {
+		payload := map[string]any{"new_field": true, "mode": "parallel", "other": 1}
+		adapted := c.Adapt("/dns_config", "v0.107.69", payload)
+		if len(adapted) != 2 {
+			t.Errorf("Adapt() = %v, want 2 adapted fields", adapted)
+		}
+		if _, ok := payload["new_field"]; ok {
+			t.Error("new_field should be removed")
+		}
+		if payload["mode"] != "legacy" || payload["other"] != 1 {
+			t.Errorf("payload = %v, want converted mode and unchanged other", payload)
+		}
+	})
+	t.Run("should strip nested fields for newer versions", func(t *testing.T) {
+		payload := map[string]any{"name": "a", "data": map[string]any{"old_field": true, "name": "a"}}
+		if adapted := c.Adapt("clients/update", "v0.107.70", payload); len(adapted) != 1 {
+			t.Errorf("Adapt() = %v, want 1 adapted field", adapted)
+		}
+		if _, ok := payload["data"].(map[string]any)["old_field"]; ok {
+			t.Error("data.old_field should be removed")
+		}
+	})
+	t.Run("should keep fields for supported versions", func(t *testing.T) {
+		payload := map[string]any{"new_field": true, "mode": "parallel"}
+		if adapted := c.Adapt("/dns_config", "v0.107.70", payload); len(adapted) != 0 {
+			t.Errorf("Adapt() = %v, want none", adapted)
+		}
+	})
+}